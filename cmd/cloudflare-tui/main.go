@@ -9,11 +9,19 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/cli"
 	"github.com/Azahorscak/cloudflare-tui/internal/config"
+	"github.com/Azahorscak/cloudflare-tui/internal/prefs"
 	"github.com/Azahorscak/cloudflare-tui/internal/tui"
 )
 
 func main() {
+	// A leading command word such as "zones" or "records" runs a single
+	// command and prints its result instead of starting the TUI.
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr, connect))
+	}
+
 	secret := flag.String("secret", "", "Kubernetes secret in namespace/secret-name format (required)")
 	secretKey := flag.String("secret-key", "cloudflare_api_token", "key within the Kubernetes secret that holds the Cloudflare API token")
 	kubeconfig := flag.String("kubeconfig", "", "path to kubeconfig file (optional, uses default context if omitted)")
@@ -28,18 +36,36 @@ func main() {
 
 	ctx := context.Background()
 
-	cfg, err := config.Load(ctx, *secret, *kubeconfig, *secretKey)
+	client, err := connect(ctx, *secret, *kubeconfig, *secretKey)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 
-	client := api.NewClient(cfg)
 	model := tui.New(client, *readOnly)
 
+	// Preferences are a convenience: if they cannot be read, start with the
+	// defaults rather than refusing to run.
+	if path, err := prefs.DefaultPath(); err == nil {
+		p, err := prefs.Load(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		model = model.WithPrefs(path, p)
+	}
+
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// connect loads the API token from the Kubernetes secret and builds a client.
+func connect(ctx context.Context, secret, kubeconfig, secretKey string) (*api.Client, error) {
+	cfg, err := config.Load(ctx, secret, kubeconfig, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(cfg), nil
+}
@@ -3,7 +3,12 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/dns"
@@ -26,21 +31,74 @@ type Zone struct {
 
 // DNSRecord represents a single DNS record.
 type DNSRecord struct {
-	ID      string
-	Type    string
-	Name    string
-	Content string
-	TTL     int
-	Proxied bool
+	ID        string
+	Type      string
+	Name      string
+	Content   string
+	TTL       int
+	Proxied   bool
+	Proxiable bool
+
+	// Priority is only meaningful for record types where UsesPriority is true.
+	Priority int
+	// Data holds the structured components of SRV, CAA, HTTPS and similar
+	// records, keyed as in the Cloudflare API. It is nil for simple types.
+	Data map[string]any
+
+	Comment    string
+	Tags       []string
+	CreatedOn  time.Time
+	ModifiedOn time.Time
 }
 
 // UpdateDNSRecordParams contains the editable fields for updating a DNS record.
+// An update replaces the whole record, so every field is sent even when empty.
 type UpdateDNSRecordParams struct {
-	Name    string
-	Type    string
-	Content string
-	TTL     int
-	Proxied bool
+	Name     string
+	Type     string
+	Content  string
+	TTL      int
+	Proxied  bool
+	Priority int
+	Data     map[string]any
+	Comment  string
+	Tags     []string
+}
+
+// CreateDNSRecordParams contains the fields for creating a DNS record. It shares
+// its shape with UpdateDNSRecordParams so a single form can drive both calls.
+type CreateDNSRecordParams = UpdateDNSRecordParams
+
+// Params returns the params that would save r as it is, for sending a
+// snapshot back to Cloudflare.
+func (r DNSRecord) Params() UpdateDNSRecordParams {
+	return UpdateDNSRecordParams{
+		Name:     r.Name,
+		Type:     r.Type,
+		Content:  r.Content,
+		TTL:      r.TTL,
+		Proxied:  r.Proxied,
+		Priority: r.Priority,
+		Data:     r.Data,
+		Comment:  r.Comment,
+		Tags:     r.Tags,
+	}
+}
+
+// QualifyName returns a record name relative to zone in full, as Cloudflare
+// stores it. "@" and "" stand for the apex, and a name that already ends
+// with the zone, in any case, is kept as written. A trailing dot is dropped.
+func QualifyName(name, zone string) string {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".")
+	zone = strings.TrimSuffix(zone, ".")
+	lower, lowerZone := strings.ToLower(name), strings.ToLower(zone)
+	switch {
+	case name == "" || name == "@":
+		return zone
+	case zone == "" || lower == lowerZone || strings.HasSuffix(lower, "."+lowerZone):
+		return name
+	}
+	return name + "." + zone
 }
 
 // NewClient creates an authenticated Cloudflare API client from the given config.
@@ -88,15 +146,7 @@ func (c *Client) ListDNSRecords(ctx context.Context, zoneID string) ([]DNSRecord
 		ZoneID: cloudflare.F(zoneID),
 	})
 	for pager.Next() {
-		r := pager.Current()
-		result = append(result, DNSRecord{
-			ID:      r.ID,
-			Type:    string(r.Type),
-			Name:    r.Name,
-			Content: r.Content,
-			TTL:     int(r.TTL),
-			Proxied: r.Proxied,
-		})
+		result = append(result, toDNSRecord(pager.Current()))
 	}
 	if err := pager.Err(); err != nil {
 		return nil, fmt.Errorf("listing DNS records for zone %s: %w", zoneID, err)
@@ -114,38 +164,381 @@ func (c *Client) GetDNSRecord(ctx context.Context, zoneID, recordID string) (DNS
 		return DNSRecord{}, fmt.Errorf("getting DNS record %s in zone %s: %w", recordID, zoneID, err)
 	}
 
-	return DNSRecord{
-		ID:      resp.ID,
-		Type:    string(resp.Type),
-		Name:    resp.Name,
-		Content: resp.Content,
-		TTL:     int(resp.TTL),
-		Proxied: resp.Proxied,
-	}, nil
+	return toDNSRecord(*resp), nil
+}
+
+// CreateDNSRecord creates a new DNS record in the given zone and returns it.
+func (c *Client) CreateDNSRecord(ctx context.Context, zoneID string, params CreateDNSRecordParams) (DNSRecord, error) {
+	resp, err := c.cf.DNS.Records.New(ctx, dns.RecordNewParams{
+		ZoneID: cloudflare.F(zoneID),
+		Body:   newRecordBody(params),
+	})
+	if err != nil {
+		return DNSRecord{}, fmt.Errorf("creating %s record %s in zone %s: %w", params.Type, params.Name, zoneID, err)
+	}
+
+	return toDNSRecord(*resp), nil
 }
 
 // UpdateDNSRecord updates a DNS record and returns the updated record.
 func (c *Client) UpdateDNSRecord(ctx context.Context, zoneID, recordID string, params UpdateDNSRecordParams) (DNSRecord, error) {
 	resp, err := c.cf.DNS.Records.Update(ctx, recordID, dns.RecordUpdateParams{
 		ZoneID: cloudflare.F(zoneID),
-		Body: dns.RecordUpdateParamsBody{
-			Name:    cloudflare.F(params.Name),
-			Type:    cloudflare.F(dns.RecordUpdateParamsBodyType(params.Type)),
-			Content: cloudflare.F(params.Content),
-			TTL:     cloudflare.F(dns.TTL(params.TTL)),
-			Proxied: cloudflare.F(params.Proxied),
-		},
+		Body:   updateRecordBody(params),
 	})
 	if err != nil {
 		return DNSRecord{}, fmt.Errorf("updating DNS record %s in zone %s: %w", recordID, zoneID, err)
 	}
 
-	return DNSRecord{
-		ID:      resp.ID,
-		Type:    string(resp.Type),
-		Name:    resp.Name,
-		Content: resp.Content,
-		TTL:     int(resp.TTL),
-		Proxied: resp.Proxied,
+	return toDNSRecord(*resp), nil
+}
+
+// DeleteDNSRecord permanently deletes a DNS record.
+func (c *Client) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) error {
+	_, err := c.cf.DNS.Records.Delete(ctx, recordID, dns.RecordDeleteParams{
+		ZoneID: cloudflare.F(zoneID),
+	})
+	if err != nil {
+		return fmt.Errorf("deleting DNS record %s in zone %s: %w", recordID, zoneID, err)
+	}
+	return nil
+}
+
+// ExportZoneFile returns the zone's records as a BIND zone file, as produced
+// by Cloudflare. The token needs DNS read access to the zone.
+func (c *Client) ExportZoneFile(ctx context.Context, zoneID string) (string, error) {
+	resp, err := c.cf.DNS.Records.Export(ctx, dns.RecordExportParams{
+		ZoneID: cloudflare.F(zoneID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("exporting zone %s: %w", zoneID, err)
+	}
+	if resp == nil {
+		return "", nil
+	}
+	return *resp, nil
+}
+
+// ImportResult counts the records of an imported zone file.
+type ImportResult struct {
+	Added  int // records created
+	Parsed int // records read from the file
+}
+
+// ImportZoneFile creates the records in a BIND zone file through
+// Cloudflare's import endpoint. The endpoint only adds records. Proxiable
+// records are proxied if the file marks them with cf_tags=cf-proxied:true.
+func (c *Client) ImportZoneFile(ctx context.Context, zoneID, zoneFile string) (ImportResult, error) {
+	resp, err := c.cf.DNS.Records.Import(ctx, dns.RecordImportParams{
+		ZoneID: cloudflare.F(zoneID),
+		File:   cloudflare.F(zoneFile),
+	})
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("importing zone file into zone %s: %w", zoneID, err)
+	}
+	return ImportResult{Added: int(resp.RecsAdded), Parsed: int(resp.TotalRecordsParsed)}, nil
+}
+
+// StatusCode returns the HTTP status of the Cloudflare response that caused
+// err, or 0 if err did not come from an API response (for example a network
+// failure).
+func StatusCode(err error) int {
+	var apiErr *cloudflare.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// BatchOp identifies one operation of a BatchRequest: List is "deletes",
+// "patches", "puts" or "posts", and Index its position in that list.
+type BatchOp struct {
+	List  string
+	Index int
+}
+
+// BatchErrors returns the messages of a rejected batch by the operation
+// they concern, which Cloudflare names in each error's source pointer, such
+// as "/posts/0/content". Several messages for one operation are joined with
+// "; ". Errors that name no operation are left out; the error itself still
+// carries them.
+func BatchErrors(err error) map[BatchOp]string {
+	var apiErr *cloudflare.Error
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	ops := make(map[BatchOp]string)
+	for _, e := range apiErr.Errors {
+		parts := strings.Split(strings.TrimPrefix(e.Source.Pointer, "/"), "/")
+		if len(parts) < 2 {
+			continue
+		}
+		i, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		switch op := (BatchOp{List: parts[0], Index: i}); op.List {
+		case "deletes", "patches", "puts", "posts":
+			if ops[op] != "" {
+				ops[op] += "; "
+			}
+			ops[op] += e.Message
+		}
+	}
+	return ops
+}
+
+// BatchPatch changes the non-nil fields of an existing record and leaves
+// the rest as they are. Type is the record's current type.
+type BatchPatch struct {
+	ID      string
+	Type    string
+	Content *string
+	TTL     *int
+	Proxied *bool
+	Comment *string
+}
+
+// BatchPut replaces an existing record, like UpdateDNSRecord.
+type BatchPut struct {
+	ID     string
+	Params UpdateDNSRecordParams
+}
+
+// BatchRequest lists the changes to make in one batch. Cloudflare applies
+// them in the order deletes, patches, puts, posts.
+type BatchRequest struct {
+	Deletes []string
+	Patches []BatchPatch
+	Puts    []BatchPut
+	Posts   []CreateDNSRecordParams
+}
+
+// BatchResult holds the records returned for each operation, in the order
+// the operations were requested.
+type BatchResult struct {
+	Deletes []DNSRecord
+	Patches []DNSRecord
+	Puts    []DNSRecord
+	Posts   []DNSRecord
+}
+
+// Len returns the number of operations in the request.
+func (b BatchRequest) Len() int {
+	return len(b.Deletes) + len(b.Patches) + len(b.Puts) + len(b.Posts)
+}
+
+// BatchDNSRecords applies several record changes in a single transaction:
+// either all of them succeed or none is applied.
+//
+// The SDK models puts and patches as a union with one struct per record
+// type, so the request body is encoded here from the same bodies that
+// CreateDNSRecord and UpdateDNSRecord send.
+func (c *Client) BatchDNSRecords(ctx context.Context, zoneID string, req BatchRequest) (BatchResult, error) {
+	body, err := batchBody(req)
+	if err != nil {
+		return BatchResult{}, fmt.Errorf("encoding batch for zone %s: %w", zoneID, err)
+	}
+	resp, err := c.cf.DNS.Records.Batch(ctx, dns.RecordBatchParams{
+		ZoneID: cloudflare.F(zoneID),
+	}, option.WithRequestBody("application/json", body))
+	if err != nil {
+		return BatchResult{}, fmt.Errorf("applying %d DNS record changes in zone %s: %w", req.Len(), zoneID, err)
+	}
+
+	toRecords := func(rs []dns.RecordResponse) []DNSRecord {
+		out := make([]DNSRecord, len(rs))
+		for i, r := range rs {
+			out[i] = toDNSRecord(r)
+		}
+		return out
+	}
+	return BatchResult{
+		Deletes: toRecords(resp.Deletes),
+		Patches: toRecords(resp.Patches),
+		Puts:    toRecords(resp.Puts),
+		Posts:   toRecords(resp.Posts),
 	}, nil
 }
+
+// batchBody encodes req as the JSON body of the batch endpoint. Empty
+// operation lists are omitted.
+func batchBody(req BatchRequest) ([]byte, error) {
+	body := make(map[string]any)
+	if len(req.Deletes) > 0 {
+		deletes := make([]map[string]any, len(req.Deletes))
+		for i, id := range req.Deletes {
+			deletes[i] = map[string]any{"id": id}
+		}
+		body["deletes"] = deletes
+	}
+	if len(req.Patches) > 0 {
+		patches := make([]map[string]any, len(req.Patches))
+		for i, p := range req.Patches {
+			patch := map[string]any{"id": p.ID, "type": p.Type}
+			if p.Content != nil {
+				patch["content"] = *p.Content
+			}
+			if p.TTL != nil {
+				patch["ttl"] = *p.TTL
+			}
+			if p.Proxied != nil {
+				patch["proxied"] = *p.Proxied
+			}
+			if p.Comment != nil {
+				patch["comment"] = *p.Comment
+			}
+			patches[i] = patch
+		}
+		body["patches"] = patches
+	}
+	if len(req.Puts) > 0 {
+		puts := make([]json.RawMessage, len(req.Puts))
+		for i, p := range req.Puts {
+			put, err := withID(updateRecordBody(p.Params), p.ID)
+			if err != nil {
+				return nil, err
+			}
+			puts[i] = put
+		}
+		body["puts"] = puts
+	}
+	if len(req.Posts) > 0 {
+		posts := make([]json.RawMessage, len(req.Posts))
+		for i, p := range req.Posts {
+			post, err := json.Marshal(newRecordBody(p))
+			if err != nil {
+				return nil, err
+			}
+			posts[i] = post
+		}
+		body["posts"] = posts
+	}
+	return json.Marshal(body)
+}
+
+// withID encodes a record body with the record ID added.
+func withID(body any, id string) (json.RawMessage, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	fields["id"], _ = json.Marshal(id)
+	return json.Marshal(fields)
+}
+
+// UsesPriority reports whether records of the given type carry a top-level
+// priority (MX and URI). SRV, HTTPS and SVCB keep theirs in Data.
+func UsesPriority(recordType string) bool {
+	switch recordType {
+	case "MX", "URI":
+		return true
+	}
+	return false
+}
+
+// UsesData reports whether records of the given type carry their payload in
+// Data rather than Content (SRV, CAA, HTTPS, SVCB, TLSA, SSHFP, NAPTR, LOC
+// and URI).
+func UsesData(recordType string) bool {
+	_, ok := structuredBody(UpdateDNSRecordParams{Type: recordType})
+	return ok
+}
+
+// newRecordBody builds the SDK create body from params.
+func newRecordBody(params CreateDNSRecordParams) dns.RecordNewParamsBodyUnion {
+	if body, ok := structuredBody(params); ok {
+		return body.(dns.RecordNewParamsBodyUnion)
+	}
+	body := dns.RecordNewParamsBody{
+		Name:    cloudflare.F(params.Name),
+		Type:    cloudflare.F(dns.RecordNewParamsBodyType(params.Type)),
+		Content: cloudflare.F(params.Content),
+		TTL:     cloudflare.F(dns.TTL(params.TTL)),
+		Proxied: cloudflare.F(params.Proxied),
+		Comment: cloudflare.F(params.Comment),
+		Tags:    cloudflare.F[any](tagsOrEmpty(params.Tags)),
+	}
+	if UsesPriority(params.Type) {
+		body.Priority = cloudflare.F(float64(params.Priority))
+	}
+	if params.Data != nil {
+		body.Data = cloudflare.F[any](params.Data)
+	}
+	return body
+}
+
+// updateRecordBody builds the SDK update body from params.
+func updateRecordBody(params UpdateDNSRecordParams) dns.RecordUpdateParamsBodyUnion {
+	if body, ok := structuredBody(params); ok {
+		return body.(dns.RecordUpdateParamsBodyUnion)
+	}
+	body := dns.RecordUpdateParamsBody{
+		Name:    cloudflare.F(params.Name),
+		Type:    cloudflare.F(dns.RecordUpdateParamsBodyType(params.Type)),
+		Content: cloudflare.F(params.Content),
+		TTL:     cloudflare.F(dns.TTL(params.TTL)),
+		Proxied: cloudflare.F(params.Proxied),
+		Comment: cloudflare.F(params.Comment),
+		Tags:    cloudflare.F[any](tagsOrEmpty(params.Tags)),
+	}
+	if UsesPriority(params.Type) {
+		body.Priority = cloudflare.F(float64(params.Priority))
+	}
+	if params.Data != nil {
+		body.Data = cloudflare.F[any](params.Data)
+	}
+	return body
+}
+
+// tagsOrEmpty returns tags, or an empty slice when nil, so the request clears
+// tags explicitly rather than omitting the field.
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// toDNSRecord maps an SDK record response onto the thin DNSRecord struct.
+//
+// The SDK exposes structured data only as a union it does not decode into a
+// usable form, so data is read back from the raw JSON. Priority is read
+// from the raw JSON too: the SDK fills its plain field from whichever union
+// variant decodes first, usually the A record that has no priority, so that
+// field is only the fallback when the JSON has none.
+func toDNSRecord(r dns.RecordResponse) DNSRecord {
+	record := DNSRecord{
+		ID:         r.ID,
+		Type:       string(r.Type),
+		Name:       r.Name,
+		Content:    r.Content,
+		TTL:        int(r.TTL),
+		Proxied:    r.Proxied,
+		Proxiable:  r.Proxiable,
+		Comment:    r.Comment,
+		CreatedOn:  r.CreatedOn,
+		ModifiedOn: r.ModifiedOn,
+	}
+	if tags, ok := r.Tags.([]string); ok && len(tags) > 0 {
+		record.Tags = tags
+	}
+	var raw struct {
+		Priority *float64       `json:"priority"`
+		Data     map[string]any `json:"data"`
+	}
+	record.Priority = int(r.Priority)
+	if err := json.Unmarshal([]byte(r.JSON.RawJSON()), &raw); err == nil {
+		if raw.Priority != nil {
+			record.Priority = int(*raw.Priority)
+		}
+		if len(raw.Data) > 0 {
+			record.Data = raw.Data
+		}
+	}
+	return record
+}
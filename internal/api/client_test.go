@@ -2,12 +2,18 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azahorscak/cloudflare-tui/internal/config"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
 	"github.com/cloudflare/cloudflare-go/v4/option"
 )
 
@@ -172,12 +178,107 @@ func TestListDNSRecords(t *testing.T) {
 		{ID: "rec-3", Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 3600, Proxied: false},
 	}
 	for i, r := range records {
-		if r != want[i] {
+		if !reflect.DeepEqual(r, want[i]) {
 			t.Errorf("record[%d] = %+v, want %+v", i, r, want[i])
 		}
 	}
 }
 
+func TestListDNSRecordsFullFields(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") != "" && r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[],"result_info":{"page":2,"per_page":20,"total_count":1,"total_pages":1}}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "rec-srv",
+				"type": "SRV",
+				"name": "_sip._tcp.example.com",
+				"content": "10 5060 sip.example.com",
+				"priority": 5,
+				"data": {"priority": 5, "weight": 10, "port": 5060, "target": "sip.example.com"},
+				"comment": "VoIP",
+				"tags": ["owner:voice", "env:prod"],
+				"proxiable": false,
+				"proxied": false,
+				"ttl": 300,
+				"created_on": "2024-01-02T03:04:05Z",
+				"modified_on": "2024-02-03T04:05:06Z"
+			}],
+			"result_info": {"page": 1, "per_page": 20, "total_count": 1, "total_pages": 1}
+		}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	records, err := client.ListDNSRecords(context.Background(), "zone-1")
+	if err != nil {
+		t.Fatalf("ListDNSRecords returned error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	want := DNSRecord{
+		ID:       "rec-srv",
+		Type:     "SRV",
+		Name:     "_sip._tcp.example.com",
+		Content:  "10 5060 sip.example.com",
+		TTL:      300,
+		Priority: 5,
+		Data: map[string]any{
+			"priority": float64(5),
+			"weight":   float64(10),
+			"port":     float64(5060),
+			"target":   "sip.example.com",
+		},
+		Comment:    "VoIP",
+		Tags:       []string{"owner:voice", "env:prod"},
+		CreatedOn:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		ModifiedOn: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+	}
+	got := records[0]
+	if !got.CreatedOn.Equal(want.CreatedOn) || !got.ModifiedOn.Equal(want.ModifiedOn) {
+		t.Errorf("timestamps = %v / %v, want %v / %v", got.CreatedOn, got.ModifiedOn, want.CreatedOn, want.ModifiedOn)
+	}
+	got.CreatedOn, got.ModifiedOn = want.CreatedOn, want.ModifiedOn
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("record = %+v, want %+v", got, want)
+	}
+}
+
+func TestToDNSRecordPriority(t *testing.T) {
+	decode := func(raw string) dns.RecordResponse {
+		t.Helper()
+		var r dns.RecordResponse
+		if err := json.Unmarshal([]byte(raw), &r); err != nil {
+			t.Fatalf("decoding %s: %v", raw, err)
+		}
+		return r
+	}
+
+	// The raw JSON wins over the SDK field, even for a priority of 0.
+	r := decode(`{"id":"mx","type":"MX","name":"example.com","content":"mx.example.com","priority":0,"ttl":1}`)
+	r.Priority = 7
+	if got := toDNSRecord(r).Priority; got != 0 {
+		t.Errorf("priority = %d, want 0 from the raw JSON", got)
+	}
+
+	// Without a priority in the JSON, the SDK field is used.
+	r = decode(`{"id":"mx","type":"MX","name":"example.com","content":"mx.example.com","ttl":1}`)
+	r.Priority = 7
+	if got := toDNSRecord(r).Priority; got != 7 {
+		t.Errorf("priority = %d, want the SDK field's 7", got)
+	}
+}
+
 func TestListDNSRecordsEmpty(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
@@ -273,14 +374,15 @@ func TestGetDNSRecord(t *testing.T) {
 	}
 
 	want := DNSRecord{
-		ID:      "rec-1",
-		Type:    "A",
-		Name:    "example.com",
-		Content: "192.0.2.1",
-		TTL:     300,
-		Proxied: true,
+		ID:        "rec-1",
+		Type:      "A",
+		Name:      "example.com",
+		Content:   "192.0.2.1",
+		TTL:       300,
+		Proxied:   true,
+		Proxiable: true,
 	}
-	if record != want {
+	if !reflect.DeepEqual(record, want) {
 		t.Errorf("GetDNSRecord = %+v, want %+v", record, want)
 	}
 }
@@ -300,6 +402,9 @@ func TestGetDNSRecordError(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error from GetDNSRecord, got nil")
 	}
+	if got := StatusCode(err); got != http.StatusNotFound {
+		t.Errorf("StatusCode = %d, want %d", got, http.StatusNotFound)
+	}
 }
 
 func TestGetDNSRecordNetworkFailure(t *testing.T) {
@@ -311,6 +416,9 @@ func TestGetDNSRecordNetworkFailure(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error from GetDNSRecord with closed server, got nil")
 	}
+	if got := StatusCode(err); got != 0 {
+		t.Errorf("StatusCode = %d, want 0 for a network failure", got)
+	}
 }
 
 func TestUpdateDNSRecord(t *testing.T) {
@@ -355,18 +463,100 @@ func TestUpdateDNSRecord(t *testing.T) {
 	}
 
 	want := DNSRecord{
-		ID:      "rec-1",
-		Type:    "A",
-		Name:    "example.com",
-		Content: "203.0.113.50",
-		TTL:     600,
-		Proxied: false,
+		ID:        "rec-1",
+		Type:      "A",
+		Name:      "example.com",
+		Content:   "203.0.113.50",
+		TTL:       600,
+		Proxied:   false,
+		Proxiable: true,
 	}
-	if record != want {
+	if !reflect.DeepEqual(record, want) {
 		t.Errorf("UpdateDNSRecord = %+v, want %+v", record, want)
 	}
 }
 
+func TestUpdateDNSRecordSendsFullBody(t *testing.T) {
+	var body map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-mx", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-mx","type":"MX","name":"example.com","content":"mail.example.com","priority":10,"ttl":3600}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	record, err := client.UpdateDNSRecord(context.Background(), "zone-1", "rec-mx", UpdateDNSRecordParams{
+		Name:     "example.com",
+		Type:     "MX",
+		Content:  "mail.example.com",
+		TTL:      3600,
+		Priority: 10,
+		Comment:  "primary MX",
+	})
+	if err != nil {
+		t.Fatalf("UpdateDNSRecord returned error: %v", err)
+	}
+	if record.Priority != 10 {
+		t.Errorf("expected priority 10 in result, got %d", record.Priority)
+	}
+
+	if body["priority"] != float64(10) {
+		t.Errorf("expected priority 10 in request, got %v", body["priority"])
+	}
+	if body["comment"] != "primary MX" {
+		t.Errorf("expected comment in request, got %v", body["comment"])
+	}
+	// Tags must be sent explicitly so a PUT does not depend on omitted fields.
+	if tags, ok := body["tags"].([]any); !ok || len(tags) != 0 {
+		t.Errorf("expected empty tags list in request, got %v", body["tags"])
+	}
+	if _, ok := body["data"]; ok {
+		t.Errorf("expected no data for MX record, got %v", body["data"])
+	}
+}
+
+func TestUpdateDNSRecordStructuredBody(t *testing.T) {
+	var body map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-srv", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-srv","type":"SRV","name":"_sip._tcp.example.com","ttl":300}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	_, err := client.UpdateDNSRecord(context.Background(), "zone-1", "rec-srv", UpdateDNSRecordParams{
+		Name:    "_sip._tcp.example.com",
+		Type:    "SRV",
+		Content: "10 60 5060 sip.example.com.",
+		TTL:     300,
+		Data:    map[string]any{"priority": float64(10), "weight": float64(60), "port": float64(5060), "target": "sip.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateDNSRecord returned error: %v", err)
+	}
+
+	want := map[string]any{"priority": float64(10), "weight": float64(60), "port": float64(5060), "target": "sip.example.com"}
+	if !reflect.DeepEqual(body["data"], want) {
+		t.Errorf("expected SRV data %v, got %v", want, body["data"])
+	}
+	if _, ok := body["content"]; ok {
+		t.Errorf("expected no content for a structured SRV body, got %v", body["content"])
+	}
+	if body["type"] != "SRV" {
+		t.Errorf("expected type SRV, got %v", body["type"])
+	}
+}
+
 func TestUpdateDNSRecordError(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
@@ -407,6 +597,326 @@ func TestUpdateDNSRecordNetworkFailure(t *testing.T) {
 	}
 }
 
+func TestCreateDNSRecord(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("unexpected Authorization header: %s", got)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		if body["name"] != "new.example.com" || body["type"] != "AAAA" || body["content"] != "2001:db8::1" {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "rec-new",
+				"type": "AAAA",
+				"name": "new.example.com",
+				"content": "2001:db8::1",
+				"ttl": 1,
+				"proxied": false,
+				"proxiable": true
+			}
+		}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	record, err := client.CreateDNSRecord(context.Background(), "zone-1", CreateDNSRecordParams{
+		Name:    "new.example.com",
+		Type:    "AAAA",
+		Content: "2001:db8::1",
+		TTL:     1,
+		Proxied: false,
+	})
+	if err != nil {
+		t.Fatalf("CreateDNSRecord returned error: %v", err)
+	}
+
+	want := DNSRecord{
+		ID:        "rec-new",
+		Type:      "AAAA",
+		Name:      "new.example.com",
+		Content:   "2001:db8::1",
+		TTL:       1,
+		Proxied:   false,
+		Proxiable: true,
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("CreateDNSRecord = %+v, want %+v", record, want)
+	}
+}
+
+func TestCreateDNSRecordError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":81057,"message":"An identical record already exists."}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	_, err := client.CreateDNSRecord(context.Background(), "zone-1", CreateDNSRecordParams{
+		Name:    "example.com",
+		Type:    "A",
+		Content: "192.0.2.1",
+		TTL:     1,
+	})
+	if err == nil {
+		t.Fatal("expected error from CreateDNSRecord, got nil")
+	}
+}
+
+func TestDeleteDNSRecord(t *testing.T) {
+	deleteCalled := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("unexpected Authorization header: %s", got)
+		}
+		deleteCalled = true
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-1"}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	if err := client.DeleteDNSRecord(context.Background(), "zone-1", "rec-1"); err != nil {
+		t.Fatalf("DeleteDNSRecord returned error: %v", err)
+	}
+	if !deleteCalled {
+		t.Error("expected DELETE request to be sent")
+	}
+}
+
+func TestDeleteDNSRecordError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":81044,"message":"Record does not exist."}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	if err := client.DeleteDNSRecord(context.Background(), "zone-1", "rec-1"); err == nil {
+		t.Fatal("expected error from DeleteDNSRecord, got nil")
+	}
+}
+
+func TestExportZoneFile(t *testing.T) {
+	const zoneFile = ";; Domain:     example.com.\nwww.example.com.\t1\tIN\tA\t192.0.2.1 ; cf_tags=cf-proxied:true\n"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, zoneFile)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	got, err := client.ExportZoneFile(context.Background(), "zone-1")
+	if err != nil {
+		t.Fatalf("ExportZoneFile returned error: %v", err)
+	}
+	if got != zoneFile {
+		t.Errorf("ExportZoneFile = %q, want %q", got, zoneFile)
+	}
+}
+
+func TestExportZoneFileForbidden(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	_, err := client.ExportZoneFile(context.Background(), "zone-1")
+	if err == nil {
+		t.Fatal("expected error from ExportZoneFile, got nil")
+	}
+	if got := StatusCode(err); got != http.StatusForbidden {
+		t.Errorf("StatusCode = %d, want %d", got, http.StatusForbidden)
+	}
+}
+
+func TestImportZoneFile(t *testing.T) {
+	const zoneFile = "www.example.com. 300 IN A 192.0.2.1\n"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/import", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("expected a multipart body: %v", err)
+		}
+		if got := r.FormValue("file"); got != zoneFile {
+			t.Errorf("file = %q, want %q", got, zoneFile)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"recs_added":1,"total_records_parsed":1}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	got, err := client.ImportZoneFile(context.Background(), "zone-1", zoneFile)
+	if err != nil {
+		t.Fatalf("ImportZoneFile returned error: %v", err)
+	}
+	if got != (ImportResult{Added: 1, Parsed: 1}) {
+		t.Errorf("ImportZoneFile = %+v, want 1 added of 1 parsed", got)
+	}
+}
+
+func TestBatchDNSRecords(t *testing.T) {
+	var body map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{
+			"deletes":[{"id":"rec-old","type":"A","name":"old.example.com","content":"192.0.2.9","ttl":1}],
+			"patches":[{"id":"rec-1","type":"A","name":"www.example.com","content":"192.0.2.1","ttl":300}],
+			"puts":[{"id":"rec-mx","type":"MX","name":"example.com","content":"mail.example.com","priority":20,"ttl":3600}],
+			"posts":[{"id":"rec-new","type":"TXT","name":"example.com","content":"v=spf1 -all","ttl":1}]
+		}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ttl := 300
+	client := newTestClient(t, srv.URL)
+	result, err := client.BatchDNSRecords(context.Background(), "zone-1", BatchRequest{
+		Deletes: []string{"rec-old"},
+		Patches: []BatchPatch{{ID: "rec-1", Type: "A", TTL: &ttl}},
+		Puts: []BatchPut{{ID: "rec-mx", Params: UpdateDNSRecordParams{
+			Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: 20,
+		}}},
+		Posts: []CreateDNSRecordParams{{Name: "example.com", Type: "TXT", Content: "v=spf1 -all", TTL: 1}},
+	})
+	if err != nil {
+		t.Fatalf("BatchDNSRecords returned error: %v", err)
+	}
+
+	if want := []any{map[string]any{"id": "rec-old"}}; !reflect.DeepEqual(body["deletes"], want) {
+		t.Errorf("expected deletes %v, got %v", want, body["deletes"])
+	}
+	// A patch sends only the fields that change.
+	if want := []any{map[string]any{"id": "rec-1", "type": "A", "ttl": float64(300)}}; !reflect.DeepEqual(body["patches"], want) {
+		t.Errorf("expected patches %v, got %v", want, body["patches"])
+	}
+	puts, _ := body["puts"].([]any)
+	if len(puts) != 1 {
+		t.Fatalf("expected 1 put, got %v", body["puts"])
+	}
+	if put := puts[0].(map[string]any); put["id"] != "rec-mx" || put["priority"] != float64(20) || put["content"] != "mail.example.com" {
+		t.Errorf("expected full MX body with id, got %v", put)
+	}
+	if posts, _ := body["posts"].([]any); len(posts) != 1 || posts[0].(map[string]any)["type"] != "TXT" {
+		t.Errorf("expected TXT post, got %v", body["posts"])
+	}
+
+	if len(result.Deletes) != 1 || result.Deletes[0].ID != "rec-old" {
+		t.Errorf("unexpected deletes result: %+v", result.Deletes)
+	}
+	if len(result.Patches) != 1 || result.Patches[0].TTL != 300 {
+		t.Errorf("unexpected patches result: %+v", result.Patches)
+	}
+	if len(result.Puts) != 1 || result.Puts[0].Priority != 20 {
+		t.Errorf("expected priority decoded from put result, got %+v", result.Puts)
+	}
+	if len(result.Posts) != 1 || result.Posts[0].ID != "rec-new" {
+		t.Errorf("unexpected posts result: %+v", result.Posts)
+	}
+}
+
+func TestBatchDNSRecordsOmitsEmptyOperations(t *testing.T) {
+	var body map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"deletes":[{"id":"rec-1"}]}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	if _, err := client.BatchDNSRecords(context.Background(), "zone-1", BatchRequest{Deletes: []string{"rec-1"}}); err != nil {
+		t.Fatalf("BatchDNSRecords returned error: %v", err)
+	}
+	for _, key := range []string{"patches", "puts", "posts"} {
+		if _, ok := body[key]; ok {
+			t.Errorf("expected no %s in request, got %v", key, body[key])
+		}
+	}
+}
+
+func TestBatchDNSRecordsError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"errors":[`+
+			`{"code":81057,"message":"An identical record already exists.","source":{"pointer":"/deletes/1"}},`+
+			`{"code":9005,"message":"Content is invalid.","source":{"pointer":"/deletes/1/content"}},`+
+			`{"code":1000,"message":"Batch failed."}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	_, err := client.BatchDNSRecords(context.Background(), "zone-1", BatchRequest{Deletes: []string{"a", "b"}})
+	if err == nil {
+		t.Fatal("expected error from BatchDNSRecords, got nil")
+	}
+	if !strings.Contains(err.Error(), "2 DNS record changes") {
+		t.Errorf("expected error to mention the batch size, got %v", err)
+	}
+	want := map[BatchOp]string{{List: "deletes", Index: 1}: "An identical record already exists.; Content is invalid."}
+	if got := BatchErrors(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("BatchErrors = %v, want %v", got, want)
+	}
+	if got := BatchErrors(errors.New("network down")); got != nil {
+		t.Errorf("BatchErrors of a network error = %v, want nil", got)
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	cfg := &config.Config{APIToken: "my-token"}
 	client := NewClient(cfg)
@@ -417,3 +927,20 @@ func TestNewClient(t *testing.T) {
 		t.Fatal("NewClient returned Client with nil cloudflare client")
 	}
 }
+
+func TestQualifyName(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"www", "www.example.com"},
+		{"@", "example.com"},
+		{"", "example.com"},
+		{"example.com.", "example.com"},
+		{"WWW.Example.com", "WWW.Example.com"},
+		{" www ", "www.example.com"},
+		{"www.example.org", "www.example.org.example.com"},
+	}
+	for _, tt := range tests {
+		if got := QualifyName(tt.name, "example.com."); got != tt.want {
+			t.Errorf("QualifyName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+)
+
+// structuredBody builds the type-specific SDK body for record types whose
+// payload is carried in the structured data field. It returns false for
+// simple types, which use the generic content-based body instead.
+//
+// The returned value satisfies both dns.RecordNewParamsBodyUnion and
+// dns.RecordUpdateParamsBodyUnion.
+func structuredBody(params UpdateDNSRecordParams) (any, bool) {
+	name := cloudflare.F(params.Name)
+	ttl := cloudflare.F(dns.TTL(params.TTL))
+	comment := cloudflare.F(params.Comment)
+	proxied := cloudflare.F(params.Proxied)
+	tags := cloudflare.F(tagsOrEmpty(params.Tags))
+	d := params.Data
+
+	switch params.Type {
+	case "SRV":
+		return dns.SRVRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.SRVRecordTypeSRV),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.SRVRecordDataParam{
+				Priority: cloudflare.F(dataFloat(d, "priority")),
+				Weight:   cloudflare.F(dataFloat(d, "weight")),
+				Port:     cloudflare.F(dataFloat(d, "port")),
+				Target:   cloudflare.F(dataString(d, "target")),
+			}),
+		}, true
+	case "CAA":
+		return dns.CAARecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.CAARecordTypeCAA),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.CAARecordDataParam{
+				Flags: cloudflare.F(dataFloat(d, "flags")),
+				Tag:   cloudflare.F(dataString(d, "tag")),
+				Value: cloudflare.F(dataString(d, "value")),
+			}),
+		}, true
+	case "HTTPS":
+		return dns.HTTPSRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.HTTPSRecordTypeHTTPS),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.HTTPSRecordDataParam{
+				Priority: cloudflare.F(dataFloat(d, "priority")),
+				Target:   cloudflare.F(dataString(d, "target")),
+				Value:    cloudflare.F(dataString(d, "value")),
+			}),
+		}, true
+	case "SVCB":
+		return dns.SVCBRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.SVCBRecordTypeSVCB),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.SVCBRecordDataParam{
+				Priority: cloudflare.F(dataFloat(d, "priority")),
+				Target:   cloudflare.F(dataString(d, "target")),
+				Value:    cloudflare.F(dataString(d, "value")),
+			}),
+		}, true
+	case "TLSA":
+		return dns.TLSARecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.TLSARecordTypeTLSA),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.TLSARecordDataParam{
+				Usage:        cloudflare.F(dataFloat(d, "usage")),
+				Selector:     cloudflare.F(dataFloat(d, "selector")),
+				MatchingType: cloudflare.F(dataFloat(d, "matching_type")),
+				Certificate:  cloudflare.F(dataString(d, "certificate")),
+			}),
+		}, true
+	case "SSHFP":
+		return dns.SSHFPRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.SSHFPRecordTypeSSHFP),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.SSHFPRecordDataParam{
+				Algorithm:   cloudflare.F(dataFloat(d, "algorithm")),
+				Type:        cloudflare.F(dataFloat(d, "type")),
+				Fingerprint: cloudflare.F(dataString(d, "fingerprint")),
+			}),
+		}, true
+	case "NAPTR":
+		return dns.NAPTRRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.NAPTRRecordTypeNAPTR),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.NAPTRRecordDataParam{
+				Order:       cloudflare.F(dataFloat(d, "order")),
+				Preference:  cloudflare.F(dataFloat(d, "preference")),
+				Flags:       cloudflare.F(dataString(d, "flags")),
+				Service:     cloudflare.F(dataString(d, "service")),
+				Regex:       cloudflare.F(dataString(d, "regex")),
+				Replacement: cloudflare.F(dataString(d, "replacement")),
+			}),
+		}, true
+	case "LOC":
+		return dns.LOCRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.LOCRecordTypeLOC),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Data: cloudflare.F(dns.LOCRecordDataParam{
+				LatDegrees:    cloudflare.F(dataFloat(d, "lat_degrees")),
+				LatMinutes:    cloudflare.F(dataFloat(d, "lat_minutes")),
+				LatSeconds:    cloudflare.F(dataFloat(d, "lat_seconds")),
+				LatDirection:  cloudflare.F(dns.LOCRecordDataLatDirection(dataString(d, "lat_direction"))),
+				LongDegrees:   cloudflare.F(dataFloat(d, "long_degrees")),
+				LongMinutes:   cloudflare.F(dataFloat(d, "long_minutes")),
+				LongSeconds:   cloudflare.F(dataFloat(d, "long_seconds")),
+				LongDirection: cloudflare.F(dns.LOCRecordDataLongDirection(dataString(d, "long_direction"))),
+				Altitude:      cloudflare.F(dataFloat(d, "altitude")),
+				Size:          cloudflare.F(dataFloat(d, "size")),
+				PrecisionHorz: cloudflare.F(dataFloat(d, "precision_horz")),
+				PrecisionVert: cloudflare.F(dataFloat(d, "precision_vert")),
+			}),
+		}, true
+	case "URI":
+		return dns.URIRecordParam{
+			Name: name, TTL: ttl, Type: cloudflare.F(dns.URIRecordTypeURI),
+			Comment: comment, Proxied: proxied, Tags: tags,
+			Priority: cloudflare.F(float64(params.Priority)),
+			Data: cloudflare.F(dns.URIRecordDataParam{
+				Weight: cloudflare.F(dataFloat(d, "weight")),
+				Target: cloudflare.F(dataString(d, "target")),
+			}),
+		}, true
+	}
+	return nil, false
+}
+
+// dataFloat reads a numeric component from structured record data. Values
+// decoded from JSON are float64; strings are parsed as a convenience.
+func dataFloat(data map[string]any, key string) float64 {
+	switch v := data[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+// dataString reads a string component from structured record data.
+func dataString(data map[string]any, key string) string {
+	switch v := data[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
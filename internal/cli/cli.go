@@ -0,0 +1,271 @@
+// Package cli implements the non-interactive subcommands, which reuse the
+// same credentials and API client as the TUI but print their results for
+// scripts.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+)
+
+// Exit codes returned by Run. Scripts can tell a rejected token from a
+// missing zone or record and from a bad flag or record value.
+const (
+	ExitOK       = 0
+	ExitError    = 1 // any other failure, e.g. the network
+	ExitInvalid  = 2 // bad arguments or record values
+	ExitAuth     = 3 // Cloudflare rejected the credentials
+	ExitNotFound = 4 // the zone or record does not exist
+)
+
+// callTimeout is the default --timeout. A command's deadline covers all of
+// its API calls, so import and apply, which may send many changes and look
+// the created records up again, get plan.ApplyTimeout instead.
+const callTimeout = 30 * time.Second
+
+// Connect builds an API client from the credential flags.
+type Connect func(ctx context.Context, secret, kubeconfig, secretKey string) (*api.Client, error)
+
+// command is one subcommand, such as "records list". Commands with an
+// empty name are a single word, such as "export".
+type command struct {
+	group, name string
+	summary     string
+	run         func(ctx context.Context, r *runner, args []string) error
+}
+
+var commands = []command{
+	{"zones", "list", "list the zones the token can see", runZonesList},
+	{"records", "list", "list the records of a zone", runRecordsList},
+	{"records", "get", "show a record by ID, or the records with a name", runRecordsGet},
+	{"records", "update", "change fields of a record", runRecordsUpdate},
+	{"export", "", "write a zone as a BIND zone file or Terraform configuration", runExport},
+	{"import", "", "make a zone match a BIND zone file", runImport},
+	{"plan", "", "show the changes that would make a zone match a desired-state file", runPlan},
+	{"apply", "", "make a zone match a desired-state file", runApply},
+}
+
+// IsCommand reports whether arg starts a subcommand rather than a flag for
+// the TUI.
+func IsCommand(arg string) bool {
+	for _, c := range commands {
+		if c.group == arg {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the subcommand in args, which start after the program name,
+// and returns the process exit code. Results go to stdout and errors to
+// stderr.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer, connect Connect) int {
+	r := &runner{stdout: stdout, stderr: stderr, connect: connect}
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		r.usage(args)
+		return ExitInvalid
+	}
+	defer r.stop()
+	err := cmd.run(ctx, r, rest)
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+	}
+	return exitCode(err)
+}
+
+// findCommand returns the command named by the first one or two arguments,
+// and the arguments after its name.
+func findCommand(args []string) (command, []string, bool) {
+	for _, c := range commands {
+		switch {
+		case len(args) == 0 || c.group != args[0]:
+		case c.name == "":
+			return c, args[1:], true
+		case len(args) > 1 && c.name == args[1]:
+			return c, args[2:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// usage lists the commands of the group in args, or every command.
+func (r *runner) usage(args []string) {
+	if len(args) > 1 {
+		fmt.Fprintf(r.stderr, "error: unknown command %q\n", strings.Join(args[:2], " "))
+	} else if len(args) == 1 {
+		fmt.Fprintf(r.stderr, "error: %q needs a subcommand\n", args[0])
+	}
+	fmt.Fprintln(r.stderr, "Commands:")
+	for _, c := range commands {
+		if len(args) == 0 || !IsCommand(args[0]) || c.group == args[0] {
+			fmt.Fprintf(r.stderr, "  %-16s %s\n", strings.TrimSpace(c.group+" "+c.name), c.summary)
+		}
+	}
+	fmt.Fprintln(r.stderr, "Run a command with -h for its flags.")
+}
+
+// exitError carries the exit code for an error that the API status does not
+// already imply.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// invalidf reports bad arguments or record values.
+func invalidf(format string, args ...any) error {
+	return &exitError{code: ExitInvalid, err: fmt.Errorf(format, args...)}
+}
+
+// notFoundf reports a zone or record that does not exist.
+func notFoundf(format string, args ...any) error {
+	return &exitError{code: ExitNotFound, err: fmt.Errorf(format, args...)}
+}
+
+// exitCode maps err to an exit code, using the HTTP status of API errors.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+	switch api.StatusCode(err) {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitAuth
+	case http.StatusNotFound:
+		return ExitNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ExitInvalid
+	}
+	return ExitError
+}
+
+// runner holds what every command needs.
+type runner struct {
+	stdout, stderr io.Writer
+	connect        Connect
+	// cancel ends the deadline started by start.
+	cancel context.CancelFunc
+}
+
+// stop ends the command's deadline, if it was started.
+func (r *runner) stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// commonFlags are accepted by every command, except that output is only
+// registered by commands that print zones or records.
+type commonFlags struct {
+	secret     string
+	secretKey  string
+	kubeconfig string
+	output     string
+	timeout    time.Duration
+}
+
+// flagSet creates the flags for the named command, starting with the
+// credential flags.
+func (r *runner) flagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(r.stderr)
+	c := &commonFlags{}
+	fs.StringVar(&c.secret, "secret", "", "Kubernetes secret in namespace/secret-name format (required)")
+	fs.StringVar(&c.secretKey, "secret-key", "cloudflare_api_token", "key within the Kubernetes secret that holds the Cloudflare API token")
+	fs.StringVar(&c.kubeconfig, "kubeconfig", "", "path to kubeconfig file (optional, uses default context if omitted)")
+	fs.DurationVar(&c.timeout, "timeout", callTimeout, "give up after this long, such as 90s or 10m")
+	return fs, c
+}
+
+// longTimeout raises the default --timeout for commands that send many
+// changes.
+func (c *commonFlags) longTimeout(fs *flag.FlagSet) {
+	c.timeout = plan.ApplyTimeout
+	fs.Lookup("timeout").DefValue = plan.ApplyTimeout.String()
+}
+
+// addOutput adds the --output flag for commands that print zones or
+// records.
+func (c *commonFlags) addOutput(fs *flag.FlagSet) {
+	fs.StringVar(&c.output, "output", string(formatTable), "output format: json, yaml, csv or table")
+}
+
+// session is a parsed command ready to call the API.
+type session struct {
+	client *api.Client
+	format format
+}
+
+// start parses args, starts the command's deadline and connects to the
+// API. The returned context carries the deadline, which Run ends.
+func (r *runner) start(ctx context.Context, fs *flag.FlagSet, c *commonFlags, args []string) (context.Context, session, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ctx, session{}, err
+		}
+		return ctx, session{}, &exitError{code: ExitInvalid, err: err}
+	}
+	if fs.NArg() > 0 {
+		return ctx, session{}, invalidf("unexpected argument %q", fs.Arg(0))
+	}
+	if c.secret == "" {
+		return ctx, session{}, invalidf("--secret flag is required (format: namespace/secret-name)")
+	}
+	if c.timeout <= 0 {
+		return ctx, session{}, invalidf("--timeout must be positive")
+	}
+	var f format
+	if c.output != "" {
+		var err error
+		if f, err = parseFormat(c.output); err != nil {
+			return ctx, session{}, err
+		}
+	}
+	ctx, r.cancel = context.WithTimeout(ctx, c.timeout)
+	client, err := r.connect(ctx, c.secret, c.kubeconfig, c.secretKey)
+	if err != nil {
+		// A credential Cloudflare rejected maps to ExitAuth through its
+		// status; anything else is a secret or kubeconfig that is wrong.
+		if api.StatusCode(err) != 0 {
+			return ctx, session{}, err
+		}
+		return ctx, session{}, &exitError{code: ExitInvalid, err: err}
+	}
+	return ctx, session{client: client, format: f}, nil
+}
+
+// findZone looks a zone up by ID or name.
+func findZone(ctx context.Context, client *api.Client, ref string) (api.Zone, error) {
+	if ref == "" {
+		return api.Zone{}, invalidf("--zone flag is required (zone name or ID)")
+	}
+	zones, err := client.ListZones(ctx)
+	if err != nil {
+		return api.Zone{}, err
+	}
+	name := strings.TrimSuffix(ref, ".")
+	for _, z := range zones {
+		if z.ID == ref || strings.EqualFold(z.Name, name) {
+			return z, nil
+		}
+	}
+	return api.Zone{}, notFoundf("zone %q not found", ref)
+}
@@ -0,0 +1,470 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/config"
+)
+
+const recordJSON = `{"id":"rec-1","type":"A","name":"www.example.com","content":"192.0.2.1","ttl":300,"proxied":false,"proxiable":true,"comment":"web, primary","tags":["env:prod"]}`
+
+// writeList serves a one-page list response, and an empty page after it for
+// the auto-pager.
+func writeList(w http.ResponseWriter, r *http.Request, result string) {
+	w.Header().Set("Content-Type", "application/json")
+	if p := r.URL.Query().Get("page"); p != "" && p != "1" {
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[],"result_info":{"page":2,"per_page":20,"total_count":1,"total_pages":1}}`)
+		return
+	}
+	fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":%s,"result_info":{"page":1,"per_page":20,"total_count":1,"total_pages":1}}`, result)
+}
+
+// newServer fakes one zone holding one A record. PUT, POST and batch bodies
+// are recorded in *put.
+func newServer(t *testing.T, put *map[string]any) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"zone-1","name":"example.com"}]`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(put); err != nil {
+				t.Errorf("decoding POST body: %v", err)
+			}
+			created := map[string]any{"id": "rec-2"}
+			for k, v := range *put {
+				created[k] = v
+			}
+			b, _ := json.Marshal(created)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":%s}`, b)
+			return
+		}
+		writeList(w, r, "["+recordJSON+"]")
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		result := recordJSON
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(put); err != nil {
+				t.Errorf("decoding PUT body: %v", err)
+			}
+			b, _ := json.Marshal(map[string]any{
+				"id": "rec-1", "type": "A", "name": "www.example.com",
+				"content": (*put)["content"], "ttl": (*put)["ttl"], "proxied": (*put)["proxied"],
+			})
+			result = string(b)
+		}
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":%s}`, result)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Posts []map[string]any `json:"posts"`
+			Puts  []map[string]any `json:"puts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding batch body: %v", err)
+		}
+		(*put)["posts"], (*put)["puts"] = body.Posts, body.Puts
+		for i, p := range body.Posts {
+			p["id"] = fmt.Sprintf("rec-%d", i+2)
+		}
+		b, _ := json.Marshal(body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":%s}`, b)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "www.example.com.\t300\tIN\tA\t192.0.2.1\n")
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":81044,"message":"Record does not exist."}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// connectTo returns a Connect that ignores the credential flags and talks
+// to baseURL.
+func connectTo(baseURL string) Connect {
+	return func(context.Context, string, string, string) (*api.Client, error) {
+		return api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, baseURL), nil
+	}
+}
+
+// run runs a command line against connect and returns its exit code and
+// output.
+func run(connect Connect, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = Run(context.Background(), args, &out, &errOut, connect)
+	return code, out.String(), errOut.String()
+}
+
+func TestZonesListFormats(t *testing.T) {
+	connect := connectTo(newServer(t, nil).URL)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", "[\n  {\n    \"id\": \"zone-1\",\n    \"name\": \"example.com\"\n  }\n]\n"},
+		{"yaml", "- id: zone-1\n  name: example.com\n"},
+		{"csv", "id,name\nzone-1,example.com\n"},
+		{"table", "ID      NAME\nzone-1  example.com\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			code, out, errOut := run(connect, "zones", "list", "--secret", "ns/creds", "--output", tt.format)
+			if code != ExitOK {
+				t.Fatalf("exit code = %d, want %d (%s)", code, ExitOK, errOut)
+			}
+			if out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordsListCSV(t *testing.T) {
+	connect := connectTo(newServer(t, nil).URL)
+	code, out, errOut := run(connect, "records", "list", "--secret", "ns/creds", "--zone", "example.com", "--output", "csv")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	want := "id,type,name,content,ttl,proxied,priority,comment,tags\n" +
+		"rec-1,A,www.example.com,192.0.2.1,300,false,,\"web, primary\",env:prod\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestRecordsGetByName(t *testing.T) {
+	connect := connectTo(newServer(t, nil).URL)
+	code, out, errOut := run(connect, "records", "get", "--secret", "ns/creds", "--zone", "zone-1", "--name", "www", "--type", "a", "--output", "json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	var got []recordOut
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not a JSON list: %v\n%s", err, out)
+	}
+	if len(got) != 1 || got[0].ID != "rec-1" || got[0].Priority != nil {
+		t.Errorf("got %+v, want rec-1 without a priority", got)
+	}
+}
+
+func TestRecordsUpdateKeepsUnsetFields(t *testing.T) {
+	var put map[string]any
+	connect := connectTo(newServer(t, &put).URL)
+	code, out, errOut := run(connect, "records", "update", "--secret", "ns/creds", "--zone", "example.com", "--id", "rec-1",
+		"--content", "192.0.2.9", "--proxied", "--output", "yaml")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	if put["content"] != "192.0.2.9" || put["proxied"] != true || put["ttl"] != float64(1) {
+		t.Errorf("PUT body = %v, want new content, proxied and an Auto TTL", put)
+	}
+	if put["name"] != "www.example.com" || put["comment"] != "web, primary" {
+		t.Errorf("PUT body = %v, want the name and comment kept", put)
+	}
+	if !strings.Contains(out, "content: 192.0.2.9\n") {
+		t.Errorf("output does not show the updated record:\n%s", out)
+	}
+}
+
+func TestExport(t *testing.T) {
+	connect := connectTo(newServer(t, nil).URL)
+	code, out, errOut := run(connect, "export", "--secret", "ns/creds", "--zone", "example.com")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	if out != "www.example.com.\t300\tIN\tA\t192.0.2.1\n" {
+		t.Errorf("output = %q, want the exported zone file", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	code, out, errOut = run(connect, "export", "--secret", "ns/creds", "--zone", "example.com", "--local", "--file", path)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	if out != "" {
+		t.Errorf("unexpected output with --file: %q", out)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "www.example.com. 300 IN A 192.0.2.1 ; web, primary\n"; !strings.Contains(string(b), want) {
+		t.Errorf("rendered file does not contain %q:\n%s", want, b)
+	}
+}
+
+func TestExportTerraform(t *testing.T) {
+	connect := connectTo(newServer(t, nil).URL)
+	code, out, errOut := run(connect, "export", "--secret", "ns/creds", "--zone", "example.com", "--format", "terraform", "--ids", "rec-1")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	for _, want := range []string{
+		`resource "cloudflare_dns_record" "www_a" {`,
+		`  content = "192.0.2.1"`,
+		`  tags    = ["env:prod"]`,
+		"import {\n  to = cloudflare_dns_record.www_a\n  id = \"zone-1/rec-1\"\n}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportForbiddenSuggestsLocal(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			writeList(w, r, `[{"id":"zone-1","name":"example.com"}]`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`)
+	}))
+	defer srv.Close()
+
+	code, _, errOut := run(connectTo(srv.URL), "export", "--secret", "ns/creds", "--zone", "example.com")
+	if code != ExitAuth {
+		t.Errorf("exit code = %d, want %d", code, ExitAuth)
+	}
+	if !strings.Contains(errOut, "--local") {
+		t.Errorf("error does not suggest --local: %s", errOut)
+	}
+}
+
+func TestImport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "example.com.zone")
+	zone := "$ORIGIN example.com.\nwww 300 A 192.0.2.1 ; web, primary\napi 300 A 192.0.2.9\n"
+	if err := os.WriteFile(file, []byte(zone), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	put := map[string]any{}
+	connect := connectTo(newServer(t, &put).URL)
+	code, out, errOut := run(connect, "import", "--secret", "ns/creds", "--zone", "example.com", "--file", file, "--dry-run", "--output", "csv")
+	if code != ExitOK {
+		t.Fatalf("dry run exit code = %d (%s)", code, errOut)
+	}
+	want := "action,type,name,changes,result\ncreate,A,api.example.com,192.0.2.9,planned\n"
+	if out != want {
+		t.Errorf("dry run output = %q, want %q", out, want)
+	}
+	if len(put) != 0 {
+		t.Errorf("dry run sent %v", put)
+	}
+
+	code, out, errOut = run(connect, "import", "--secret", "ns/creds", "--zone", "example.com", "--file", file, "--output", "csv")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	if !strings.Contains(out, "api.example.com,192.0.2.9,applied") {
+		t.Errorf("output = %q, want the create applied", out)
+	}
+	posts, _ := put["posts"].([]map[string]any)
+	if len(posts) != 1 || posts[0]["name"] != "api.example.com" || posts[0]["content"] != "192.0.2.9" {
+		t.Errorf("batch body = %v, want the create posted", put)
+	}
+}
+
+func TestImportKeepsUnlistedRecords(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "example.com.zone")
+	if err := os.WriteFile(file, []byte("api.example.com. 300 IN A 192.0.2.9\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	put := map[string]any{}
+	connect := connectTo(newServer(t, &put).URL)
+	code, out, errOut := run(connect, "import", "--secret", "ns/creds", "--zone", "example.com", "--file", file, "--output", "csv")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (%s)", code, errOut)
+	}
+	if !strings.Contains(out, "delete,A,www.example.com,192.0.2.1,skipped") {
+		t.Errorf("output = %q, want the delete skipped without --delete", out)
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dns.yaml")
+	src := "zone: example.com\nrecords:\n  - {name: www, type: A, content: 192.0.2.1, ttl: 60, comment: \"web, primary\", tags: [env:prod]}\n  - {name: api, type: A, content: 192.0.2.9, ttl: 300}\n"
+	if err := os.WriteFile(file, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	put := map[string]any{}
+	connect := connectTo(newServer(t, &put).URL)
+	code, out, errOut := run(connect, "plan", "--secret", "ns/creds", "--file", file)
+	if code != ExitOK {
+		t.Fatalf("plan exit code = %d (%s)", code, errOut)
+	}
+	want := "~ update A     www.example.com\n      TTL: 300 → 60\n" +
+		"+ create A     api.example.com\n      192.0.2.9\n" +
+		"\nPlan: 1 to create, 1 to update, 0 to delete.\n"
+	if out != want {
+		t.Errorf("plan output = %q, want %q", out, want)
+	}
+	if len(put) != 0 {
+		t.Errorf("plan sent %v", put)
+	}
+
+	code, out, errOut = run(connect, "apply", "--secret", "ns/creds", "--file", file, "--output", "json")
+	if code != ExitOK {
+		t.Fatalf("apply exit code = %d (%s)", code, errOut)
+	}
+	var got []importOut
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not a JSON list: %v\n%s", err, out)
+	}
+	if len(got) != 2 || got[0].Result != "applied" || got[1].Result != "applied" {
+		t.Errorf("unexpected results: %+v", got)
+	}
+	puts, _ := put["puts"].([]map[string]any)
+	posts, _ := put["posts"].([]map[string]any)
+	if len(puts) != 1 || len(posts) != 1 {
+		t.Errorf("batch body = %v, want the update and the create in one batch", put)
+	}
+}
+
+func TestApplyTimeout(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dns.yaml")
+	src := "zone: example.com\nrecords:\n  - {name: www, type: A, content: 192.0.2.1, ttl: 60, comment: \"web, primary\", tags: [env:prod]}\n"
+	if err := os.WriteFile(file, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(t, nil)
+	mux := http.NewServeMux()
+	mux.Handle("/", srv.Config.Handler)
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		// Hold the batch until the client gives up. The body is read first so
+		// that the server notices the connection closing.
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	srv.Config.Handler = mux
+
+	code, out, errOut := run(connectTo(srv.URL), "apply", "--secret", "ns/creds", "--file", file, "--timeout", "200ms", "--output", "csv")
+	if code != ExitError {
+		t.Errorf("exit code = %d, want %d", code, ExitError)
+	}
+	if !strings.Contains(errOut, "update www.example.com") || !strings.Contains(errOut, "context deadline exceeded") {
+		t.Errorf("stderr = %q, want the update reported as timed out", errOut)
+	}
+	if !strings.Contains(out, "update,A,www.example.com,TTL: 300 → 60,failed: ") {
+		t.Errorf("output = %q, want the update listed as failed", out)
+	}
+
+	code, _, errOut = run(connectTo(srv.URL), "apply", "--secret", "ns/creds", "--file", file, "--timeout", "0s")
+	if code != ExitInvalid {
+		t.Errorf("exit code = %d for --timeout 0s, want %d (%s)", code, ExitInvalid, errOut)
+	}
+	if _, _, errOut = run(connectTo(srv.URL), "apply", "-h"); !strings.Contains(errOut, "(default 5m0s)") {
+		t.Errorf("apply help = %q, want the longer default timeout", errOut)
+	}
+}
+
+func TestPlanIgnoresUnmanaged(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dns.json")
+	if err := os.WriteFile(file, []byte(`{"zone": "example.com", "records": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	connect := connectTo(newServer(t, nil).URL)
+	_, out, _ := run(connect, "plan", "--secret", "ns/creds", "--file", file, "--output", "csv")
+	if out != "action,type,name,changes,result\ndelete,A,www.example.com,192.0.2.1,planned\n" {
+		t.Errorf("plan output = %q, want the unlisted record deleted", out)
+	}
+	_, out, _ = run(connect, "plan", "--secret", "ns/creds", "--file", file, "--ignore-names", "www")
+	if out != "No changes: the zone matches the file. Unmanaged records ignored: 1.\n" {
+		t.Errorf("plan output = %q, want the record ignored", out)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	var put map[string]any
+	srv := newServer(t, &put)
+	connect := connectTo(srv.URL)
+
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":9109,"message":"Unauthorized to access requested resource"}],"messages":[],"result":null}`)
+	}))
+	defer unauthorized.Close()
+	badZone := filepath.Join(t.TempDir(), "bad.zone")
+	if err := os.WriteFile(badZone, []byte("mail MX mx1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	otherZone := filepath.Join(t.TempDir(), "other.yaml")
+	if err := os.WriteFile(otherZone, []byte("zone: example.org\nrecords: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	noSecret := func(context.Context, string, string, string) (*api.Client, error) {
+		return nil, errors.New(`fetching secret ns/creds: secrets "creds" not found`)
+	}
+
+	tests := []struct {
+		name    string
+		connect Connect
+		args    []string
+		want    int
+	}{
+		{"rejected token", connectTo(unauthorized.URL), []string{"zones", "list", "--secret", "ns/creds"}, ExitAuth},
+		{"missing secret", noSecret, []string{"zones", "list", "--secret", "ns/creds"}, ExitInvalid},
+		{"unknown zone", connect, []string{"records", "list", "--secret", "ns/creds", "--zone", "example.org"}, ExitNotFound},
+		{"unknown record", connect, []string{"records", "get", "--secret", "ns/creds", "--zone", "example.com", "--id", "missing"}, ExitNotFound},
+		{"no record with name", connect, []string{"records", "get", "--secret", "ns/creds", "--zone", "example.com", "--name", "api"}, ExitNotFound},
+		{"invalid content", connect, []string{"records", "update", "--secret", "ns/creds", "--zone", "example.com", "--id", "rec-1", "--content", "not-an-ip"}, ExitInvalid},
+		{"invalid ttl", connect, []string{"records", "update", "--secret", "ns/creds", "--zone", "example.com", "--id", "rec-1", "--ttl", "5"}, ExitInvalid},
+		{"unknown format", connect, []string{"zones", "list", "--secret", "ns/creds", "--output", "xml"}, ExitInvalid},
+		{"no output flag on export", connect, []string{"export", "--secret", "ns/creds", "--zone", "example.com", "--output", "json"}, ExitInvalid},
+		{"unparsable zone file", connect, []string{"import", "--secret", "ns/creds", "--zone", "example.com", "--file", badZone}, ExitInvalid},
+		{"no file flag on import", connect, []string{"import", "--secret", "ns/creds", "--zone", "example.com"}, ExitInvalid},
+		{"state file for another zone", connect, []string{"plan", "--secret", "ns/creds", "--zone", "example.com", "--file", otherZone}, ExitInvalid},
+		{"state file zone not found", connect, []string{"apply", "--secret", "ns/creds", "--file", otherZone}, ExitNotFound},
+		{"invalid state file", connect, []string{"plan", "--secret", "ns/creds", "--zone", "example.com", "--file", badZone}, ExitInvalid},
+		{"unknown export format", connect, []string{"export", "--secret", "ns/creds", "--zone", "example.com", "--format", "pulumi"}, ExitInvalid},
+		{"ids without terraform", connect, []string{"export", "--secret", "ns/creds", "--zone", "example.com", "--ids", "rec-1"}, ExitInvalid},
+		{"unknown id for terraform", connect, []string{"export", "--secret", "ns/creds", "--zone", "example.com", "--format", "terraform", "--ids", "nope"}, ExitNotFound},
+		{"unknown flag", connect, []string{"zones", "list", "--secret", "ns/creds", "--bogus"}, ExitInvalid},
+		{"no secret flag", connect, []string{"zones", "list"}, ExitInvalid},
+		{"no zone flag", connect, []string{"records", "list", "--secret", "ns/creds"}, ExitInvalid},
+		{"unknown command", connect, []string{"zones", "delete"}, ExitInvalid},
+		{"help", connect, []string{"zones", "list", "-h"}, ExitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, errOut := run(tt.connect, tt.args...)
+			if code != tt.want {
+				t.Errorf("exit code = %d, want %d (%s)", code, tt.want, errOut)
+			}
+		})
+	}
+	if put != nil {
+		t.Errorf("an invalid update was sent: %v", put)
+	}
+}
+
+func TestTableCellReplacesControlCharacters(t *testing.T) {
+	got := tableCell("v=spf1\x1b[31m red\r\n\ttail\x00\u200bé")
+	if want := "v=spf1 [31m red   tail  é"; got != want {
+		t.Errorf("tableCell = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,493 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+	"github.com/Azahorscak/cloudflare-tui/internal/state"
+	"github.com/Azahorscak/cloudflare-tui/internal/terraform"
+	"github.com/Azahorscak/cloudflare-tui/internal/validate"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// runZonesList prints every zone the token can see.
+func runZonesList(ctx context.Context, r *runner, args []string) error {
+	fs, common := r.flagSet("zones list")
+	common.addOutput(fs)
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	zones, err := s.client.ListZones(ctx)
+	if err != nil {
+		return err
+	}
+	out := make([]zoneOut, len(zones))
+	rows := make([][]string, len(zones))
+	for i, z := range zones {
+		out[i] = zoneOut{ID: z.ID, Name: z.Name}
+		rows[i] = zoneRow(z)
+	}
+	return write(r.stdout, s.format, out, zoneColumns, rows)
+}
+
+// runRecordsList prints every record of a zone.
+func runRecordsList(ctx context.Context, r *runner, args []string) error {
+	fs, common := r.flagSet("records list")
+	common.addOutput(fs)
+	zoneRef := fs.String("zone", "", "zone name or ID (required)")
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	zone, err := findZone(ctx, s.client, *zoneRef)
+	if err != nil {
+		return err
+	}
+	records, err := s.client.ListDNSRecords(ctx, zone.ID)
+	if err != nil {
+		return err
+	}
+	return writeRecords(r.stdout, s.format, records)
+}
+
+// runRecordsGet prints one record by ID, or every record with a name,
+// optionally of one type.
+func runRecordsGet(ctx context.Context, r *runner, args []string) error {
+	fs, common := r.flagSet("records get")
+	common.addOutput(fs)
+	zoneRef := fs.String("zone", "", "zone name or ID (required)")
+	id := fs.String("id", "", "record ID")
+	name := fs.String("name", "", "record name, absolute or relative to the zone (instead of --id)")
+	recordType := fs.String("type", "", "record type to narrow --name to")
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	if (*id == "") == (*name == "") {
+		return invalidf("exactly one of --id or --name is required")
+	}
+	zone, err := findZone(ctx, s.client, *zoneRef)
+	if err != nil {
+		return err
+	}
+	if *id != "" {
+		rec, err := getRecord(ctx, s.client, zone, *id)
+		if err != nil {
+			return err
+		}
+		return writeRecord(r.stdout, s.format, rec)
+	}
+
+	records, err := s.client.ListDNSRecords(ctx, zone.ID)
+	if err != nil {
+		return err
+	}
+	want := api.QualifyName(*name, zone.Name)
+	var matched []api.DNSRecord
+	for _, rec := range records {
+		if strings.EqualFold(rec.Name, want) && (*recordType == "" || strings.EqualFold(rec.Type, *recordType)) {
+			matched = append(matched, rec)
+		}
+	}
+	if len(matched) == 0 {
+		if *recordType != "" {
+			return notFoundf("no %s record named %s in zone %s", strings.ToUpper(*recordType), want, zone.Name)
+		}
+		return notFoundf("no record named %s in zone %s", want, zone.Name)
+	}
+	return writeRecords(r.stdout, s.format, matched)
+}
+
+// runRecordsUpdate changes the fields given as flags on a record and leaves
+// the rest as they are. The result is checked with the edit form's rules
+// before it is sent, and the updated record is printed.
+func runRecordsUpdate(ctx context.Context, r *runner, args []string) error {
+	fs, common := r.flagSet("records update")
+	common.addOutput(fs)
+	zoneRef := fs.String("zone", "", "zone name or ID (required)")
+	id := fs.String("id", "", "record ID (required)")
+	name := fs.String("name", "", "new record name")
+	content := fs.String("content", "", "new content")
+	ttl := fs.String("ttl", "", `new TTL in seconds, or "auto"`)
+	proxied := fs.Bool("proxied", false, "proxy the record through Cloudflare (use --proxied=false to stop)")
+	priority := fs.Int("priority", 0, "new priority (MX, SRV and URI records)")
+	comment := fs.String("comment", "", "new comment (an empty value removes it)")
+	tags := fs.String("tags", "", "new comma-separated tags (an empty value removes them)")
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	if *id == "" {
+		return invalidf("--id flag is required")
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	zone, err := findZone(ctx, s.client, *zoneRef)
+	if err != nil {
+		return err
+	}
+	rec, err := getRecord(ctx, s.client, zone, *id)
+	if err != nil {
+		return err
+	}
+
+	params := api.UpdateDNSRecordParams{
+		Name:     rec.Name,
+		Type:     rec.Type,
+		Content:  rec.Content,
+		TTL:      rec.TTL,
+		Proxied:  rec.Proxied,
+		Priority: rec.Priority,
+		Data:     rec.Data,
+		Comment:  rec.Comment,
+		Tags:     rec.Tags,
+	}
+	if set["name"] {
+		params.Name = api.QualifyName(*name, zone.Name)
+	}
+	if set["content"] {
+		if len(rec.Data) > 0 {
+			return invalidf("content of %s records is structured and cannot be set with --content", rec.Type)
+		}
+		params.Content = *content
+	}
+	if set["ttl"] {
+		n, err := parseTTL(*ttl)
+		if err != nil {
+			return err
+		}
+		params.TTL = n
+	}
+	if set["proxied"] {
+		params.Proxied = *proxied
+		// Proxied records must use an automatic TTL. Switch to it unless a
+		// TTL was given, so --proxied works on its own.
+		if *proxied && !set["ttl"] {
+			params.TTL = 1
+		}
+	}
+	if set["priority"] {
+		if !api.UsesPriority(rec.Type) {
+			return invalidf("%s records have no priority", rec.Type)
+		}
+		params.Priority = *priority
+	}
+	if set["comment"] {
+		params.Comment = *comment
+	}
+	if set["tags"] {
+		params.Tags = splitList(*tags)
+	}
+	if err := validate.Record(params); err != nil {
+		return invalidf("%v", err)
+	}
+
+	updated, err := s.client.UpdateDNSRecord(ctx, zone.ID, rec.ID, params)
+	if err != nil {
+		return err
+	}
+	return writeRecord(r.stdout, s.format, updated)
+}
+
+// runExport writes a zone as a BIND zone file to --file, or to stdout. The
+// file comes from Cloudflare's export endpoint, or with --local is rendered
+// from the record list for tokens that may not use the endpoint. With
+// --format terraform the records, or those listed by --ids, are written as
+// Terraform resources instead.
+func runExport(ctx context.Context, r *runner, args []string) error {
+	fs, common := r.flagSet("export")
+	zoneRef := fs.String("zone", "", "zone name or ID (required)")
+	file := fs.String("file", "", "file to write (default: standard output)")
+	local := fs.Bool("local", false, "render the zone file from the record list instead of calling the export endpoint")
+	exportFormat := fs.String("format", "bind", "bind for a zone file, or terraform for cloudflare_dns_record resources with import blocks")
+	ids := fs.String("ids", "", "comma-separated record IDs to export (terraform only; default: every record)")
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	switch *exportFormat {
+	case "bind":
+		if *ids != "" {
+			return invalidf("--ids only applies to --format terraform")
+		}
+	case "terraform":
+	default:
+		return invalidf("unknown export format %q (want bind or terraform)", *exportFormat)
+	}
+	zone, err := findZone(ctx, s.client, *zoneRef)
+	if err != nil {
+		return err
+	}
+
+	var text string
+	switch {
+	case *exportFormat == "terraform":
+		records, err := s.client.ListDNSRecords(ctx, zone.ID)
+		if err != nil {
+			return err
+		}
+		if *ids != "" {
+			if records, err = pickRecords(records, splitList(*ids), zone); err != nil {
+				return err
+			}
+		}
+		text = terraform.Render(zone.ID, zone.Name, records)
+	case *local:
+		records, err := s.client.ListDNSRecords(ctx, zone.ID)
+		if err != nil {
+			return err
+		}
+		text = zonefile.Render(zone.Name, records)
+	default:
+		text, err = s.client.ExportZoneFile(ctx, zone.ID)
+		if code := api.StatusCode(err); code == http.StatusUnauthorized || code == http.StatusForbidden {
+			return fmt.Errorf("%w (--local renders the file from the record list instead)", err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if *file == "" {
+		_, err = io.WriteString(r.stdout, text)
+		return err
+	}
+	return os.WriteFile(*file, []byte(text), 0o644)
+}
+
+// pickRecords returns the records with the given IDs, in the order given.
+func pickRecords(records []api.DNSRecord, ids []string, zone api.Zone) ([]api.DNSRecord, error) {
+	byID := make(map[string]api.DNSRecord, len(records))
+	for _, rec := range records {
+		byID[rec.ID] = rec
+	}
+	picked := make([]api.DNSRecord, 0, len(ids))
+	for _, id := range ids {
+		rec, ok := byID[id]
+		if !ok {
+			return nil, notFoundf("record %s not found in zone %s", id, zone.Name)
+		}
+		picked = append(picked, rec)
+	}
+	return picked, nil
+}
+
+// runImport makes a zone match a BIND zone file. The plan is printed with
+// each change's result; with --dry-run nothing is sent. Records missing from
+// the file are only deleted with --delete.
+func runImport(ctx context.Context, r *runner, args []string) error {
+	fs, common := r.flagSet("import")
+	common.addOutput(fs)
+	zoneRef := fs.String("zone", "", "zone name or ID (required)")
+	file := fs.String("file", "", "zone file to import (required)")
+	dryRun := fs.Bool("dry-run", false, "print the plan without changing anything")
+	del := fs.Bool("delete", false, "delete records that are not in the file")
+	endpoint := fs.Bool("import-endpoint", false, "create records through Cloudflare's import endpoint instead of in the batch")
+	common.longTimeout(fs)
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return invalidf("--file is required")
+	}
+	src, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	zone, err := findZone(ctx, s.client, *zoneRef)
+	if err != nil {
+		return err
+	}
+	desired, err := zonefile.Parse(string(src), zone.Name)
+	if err != nil {
+		return invalidf("%s: %v", *file, err)
+	}
+	current, err := s.client.ListDNSRecords(ctx, zone.ID)
+	if err != nil {
+		return err
+	}
+
+	changes := plan.Import(zone.Name, current, desired)
+	results := make([]string, len(changes))
+	for i, c := range changes {
+		switch {
+		case c.Action() == "delete" && !*del:
+			results[i] = "skipped"
+		case *dryRun:
+			results[i] = "planned"
+		}
+	}
+	failure := applyChanges(ctx, s.client, zone, changes, results, *endpoint)
+	if err := writeChanges(r.stdout, s.format, changes, results); err != nil {
+		return err
+	}
+	return failure
+}
+
+// runPlan prints the changes that would make a zone match a desired-state
+// file, without making them.
+func runPlan(ctx context.Context, r *runner, args []string) error {
+	return runState(ctx, r, "plan", args)
+}
+
+// runApply makes a zone match a desired-state file and prints each change
+// with its result.
+func runApply(ctx context.Context, r *runner, args []string) error {
+	return runState(ctx, r, "apply", args)
+}
+
+// runState runs plan or apply. The ignore flags add to the rules in the
+// file.
+func runState(ctx context.Context, r *runner, name string, args []string) error {
+	fs, common := r.flagSet(name)
+	common.addOutput(fs)
+	zoneRef := fs.String("zone", "", "zone name or ID (default: the zone the file names)")
+	file := fs.String("file", "", "desired-state file, YAML or JSON (required)")
+	ignoreAll := fs.Bool("ignore-unmanaged", false, "keep every record the file does not list")
+	ignoreExternalDNS := fs.Bool("ignore-external-dns", false, "keep records owned by external-dns")
+	ignoreNames := fs.String("ignore-names", "", "comma-separated name patterns to keep, such as \"_acme-challenge*\"")
+	ignoreTypes := fs.String("ignore-types", "", "comma-separated record types to keep")
+	var endpoint *bool
+	if name == "apply" {
+		endpoint = fs.Bool("import-endpoint", false, "create records through Cloudflare's import endpoint instead of in the batch")
+		common.longTimeout(fs)
+	}
+	ctx, s, err := r.start(ctx, fs, common, args)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return invalidf("--file is required")
+	}
+	src, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	f, err := state.Load(src)
+	if err != nil {
+		return invalidf("%s: %v", *file, err)
+	}
+	if *zoneRef == "" && f.Zone == "" {
+		return invalidf("--zone is required when the file names no zone")
+	}
+	zone, err := findZone(ctx, s.client, cmp.Or(*zoneRef, f.Zone))
+	if err != nil {
+		return err
+	}
+	if f.Zone != "" && !strings.EqualFold(strings.TrimSuffix(f.Zone, "."), zone.Name) {
+		return invalidf("%s describes %s, not %s", *file, f.Zone, zone.Name)
+	}
+	current, err := s.client.ListDNSRecords(ctx, zone.ID)
+	if err != nil {
+		return err
+	}
+
+	ignore := f.Ignore.Merge(state.Ignore{
+		All:         *ignoreAll,
+		ExternalDNS: *ignoreExternalDNS,
+		Names:       splitList(*ignoreNames),
+		Types:       splitList(*ignoreTypes),
+	})
+	changes, ignored := plan.State(zone.Name, current, f.DNSRecords(zone.Name), ignore)
+	results := make([]string, len(changes))
+	if name == "plan" {
+		if s.format == formatTable {
+			_, err := io.WriteString(r.stdout, plan.Render(changes, ignored))
+			return err
+		}
+		for i := range results {
+			results[i] = "planned"
+		}
+		return writeChanges(r.stdout, s.format, changes, results)
+	}
+	failure := applyChanges(ctx, s.client, zone, changes, results, *endpoint)
+	if err := writeChanges(r.stdout, s.format, changes, results); err != nil {
+		return err
+	}
+	return failure
+}
+
+// applyChanges applies the changes that have no result yet and records
+// "applied" or the failure for each. It returns the first failure.
+func applyChanges(ctx context.Context, client *api.Client, zone api.Zone, changes []plan.Change, results []string, endpoint bool) error {
+	var apply []plan.Change
+	var indexes []int
+	for i, c := range changes {
+		if results[i] == "" {
+			apply = append(apply, c)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(apply) == 0 {
+		return nil
+	}
+	var failure error
+	for n, o := range plan.Apply(ctx, client, zone.ID, zone.Name, apply, endpoint) {
+		results[indexes[n]] = "applied"
+		if o.Err != nil {
+			results[indexes[n]] = "failed: " + o.Err.Error()
+			if failure == nil {
+				failure = fmt.Errorf("%s %s: %w", o.Change.Action(), o.Change.Record().Name, o.Err)
+			}
+		}
+	}
+	return failure
+}
+
+// writeChanges prints a plan with the result of each change.
+func writeChanges(w io.Writer, f format, changes []plan.Change, results []string) error {
+	out := make([]importOut, len(changes))
+	rows := make([][]string, len(changes))
+	for i, c := range changes {
+		rec := c.Record()
+		out[i] = importOut{Action: c.Action(), Type: rec.Type, Name: rec.Name, Changes: c.Summary(), Result: results[i]}
+		rows[i] = []string{out[i].Action, out[i].Type, out[i].Name, out[i].Changes, out[i].Result}
+	}
+	return write(w, f, out, importColumns, rows)
+}
+
+// getRecord fetches a record, reporting a missing one as not found.
+func getRecord(ctx context.Context, client *api.Client, zone api.Zone, id string) (api.DNSRecord, error) {
+	rec, err := client.GetDNSRecord(ctx, zone.ID, id)
+	if api.StatusCode(err) == http.StatusNotFound {
+		return api.DNSRecord{}, notFoundf("record %s not found in zone %s", id, zone.Name)
+	}
+	return rec, err
+}
+
+// parseTTL reads a --ttl value: seconds, or "auto" for Cloudflare's
+// automatic TTL.
+func parseTTL(s string) (int, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "auto") {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return 0, invalidf("--ttl must be a positive integer or \"auto\", got %q", s)
+	}
+	return n, nil
+}
+
+// splitList splits a comma-separated flag value, such as --tags or --ids,
+// dropping empty entries.
+func splitList(s string) []string {
+	var tags []string
+	for t := range strings.SplitSeq(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
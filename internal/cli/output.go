@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// format is an --output value.
+type format string
+
+const (
+	formatJSON  format = "json"
+	formatYAML  format = "yaml"
+	formatCSV   format = "csv"
+	formatTable format = "table"
+)
+
+// parseFormat checks an --output value.
+func parseFormat(s string) (format, error) {
+	switch f := format(strings.ToLower(s)); f {
+	case formatJSON, formatYAML, formatCSV, formatTable:
+		return f, nil
+	}
+	return "", invalidf("unknown output format %q (want json, yaml, csv or table)", s)
+}
+
+// zoneOut is the JSON and YAML shape of a zone.
+type zoneOut struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// recordOut is the JSON and YAML shape of a record. Field names follow the
+// Cloudflare API.
+type recordOut struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Content    string         `json:"content"`
+	TTL        int            `json:"ttl"`
+	Proxied    bool           `json:"proxied"`
+	Priority   *int           `json:"priority,omitempty"`
+	Data       map[string]any `json:"data,omitempty"`
+	Comment    string         `json:"comment,omitempty"`
+	Tags       []string       `json:"tags,omitempty"`
+	CreatedOn  time.Time      `json:"created_on"`
+	ModifiedOn time.Time      `json:"modified_on"`
+}
+
+// newRecordOut converts r for output. Priority is only shown for the types
+// that use it, where 0 is a valid value.
+func newRecordOut(r api.DNSRecord) recordOut {
+	out := recordOut{
+		ID:         r.ID,
+		Type:       r.Type,
+		Name:       r.Name,
+		Content:    r.Content,
+		TTL:        r.TTL,
+		Proxied:    r.Proxied,
+		Data:       r.Data,
+		Comment:    r.Comment,
+		Tags:       r.Tags,
+		CreatedOn:  r.CreatedOn,
+		ModifiedOn: r.ModifiedOn,
+	}
+	if api.UsesPriority(r.Type) {
+		out.Priority = &r.Priority
+	}
+	return out
+}
+
+// zoneColumns is the CSV and table layout of zones.
+var zoneColumns = []string{"id", "name"}
+
+func zoneRow(z api.Zone) []string {
+	return []string{z.ID, z.Name}
+}
+
+// recordColumns is the CSV and table layout of records. Tags are joined
+// with commas, as --tags takes them.
+var recordColumns = []string{"id", "type", "name", "content", "ttl", "proxied", "priority", "comment", "tags"}
+
+func recordRow(r api.DNSRecord) []string {
+	priority := ""
+	if api.UsesPriority(r.Type) {
+		priority = strconv.Itoa(r.Priority)
+	}
+	return []string{
+		r.ID, r.Type, r.Name, r.Content, strconv.Itoa(r.TTL), strconv.FormatBool(r.Proxied),
+		priority, r.Comment, strings.Join(r.Tags, ","),
+	}
+}
+
+// importOut is the JSON and YAML shape of one change of an import or
+// desired-state plan.
+// Result is "planned", "skipped", "applied" or "failed: " and the error.
+type importOut struct {
+	Action  string `json:"action"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Changes string `json:"changes"`
+	Result  string `json:"result"`
+}
+
+// importColumns is the CSV and table layout of a plan.
+var importColumns = []string{"action", "type", "name", "changes", "result"}
+
+// write prints v as JSON or YAML, or columns and rows as CSV or an aligned
+// table.
+func write(w io.Writer, f format, v any, columns []string, rows [][]string) error {
+	switch f {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		return cw.WriteAll(rows) // WriteAll flushes
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = tableCell(c)
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// tableCell keeps a value on one line of its column and safe to print:
+// tabs and line breaks would break the alignment, and other control
+// characters, such as the ESC of a terminal escape sequence in a TXT record,
+// would reach the terminal. Every rune that is not printable becomes a space.
+func tableCell(s string) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// writeRecords prints a list of records.
+func writeRecords(w io.Writer, f format, records []api.DNSRecord) error {
+	out := make([]recordOut, len(records))
+	rows := make([][]string, len(records))
+	for i, r := range records {
+		out[i] = newRecordOut(r)
+		rows[i] = recordRow(r)
+	}
+	return write(w, f, out, recordColumns, rows)
+}
+
+// writeRecord prints one record: an object rather than a list for JSON and
+// YAML.
+func writeRecord(w io.Writer, f format, r api.DNSRecord) error {
+	return write(w, f, newRecordOut(r), recordColumns, [][]string{recordRow(r)})
+}
@@ -0,0 +1,130 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/validate"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// Outcome is the result of applying one change. After is the record as
+// Cloudflare returned it, or the zero record for a delete.
+type Outcome struct {
+	Change Change
+	After  api.DNSRecord
+	Err    error
+}
+
+// ApplyTimeout is how long applying a plan may take. It covers the batch
+// and, with the import endpoint, looking the created records up again, so
+// it is well above a single call's.
+const ApplyTimeout = 5 * time.Minute
+
+// errNotSent marks the changes left out because another change of the plan
+// is invalid.
+var errNotSent = errors.New("not sent: another change is invalid")
+
+// Apply applies changes to the zone as one batch, which Cloudflare applies
+// as a single transaction: deletes first, so a name can move to another
+// type, then updates, then creates. If any change is invalid or the batch is
+// rejected, nothing is applied. With endpoint set, the batch holds only the
+// deletes and updates; once it is applied, the creates are sent as one zone
+// file through Cloudflare's import endpoint and looked up again afterwards,
+// so a failed create leaves the deletes and updates in place. Outcomes are
+// returned in the order of changes.
+func Apply(ctx context.Context, client *api.Client, zoneID, zoneName string, changes []Change, endpoint bool) []Outcome {
+	outcomes := make([]Outcome, len(changes))
+	invalid := false
+	for i, c := range changes {
+		outcomes[i].Change = c
+		if c.Action() != "delete" {
+			outcomes[i].Err = validate.Record(c.After.Params())
+			invalid = invalid || outcomes[i].Err != nil
+		}
+	}
+	if invalid {
+		for i := range outcomes {
+			if outcomes[i].Err == nil {
+				outcomes[i].Err = errNotSent
+			}
+		}
+		return outcomes
+	}
+
+	var req api.BatchRequest
+	var puts, posts, creates []int
+	for i, c := range changes {
+		switch c.Action() {
+		case "delete":
+			req.Deletes = append(req.Deletes, c.Before.ID)
+		case "update":
+			req.Puts = append(req.Puts, api.BatchPut{ID: c.Before.ID, Params: c.After.Params()})
+			puts = append(puts, i)
+		case "create":
+			if endpoint {
+				creates = append(creates, i)
+				continue
+			}
+			req.Posts = append(req.Posts, c.After.Params())
+			posts = append(posts, i)
+		}
+	}
+	if req.Len() > 0 {
+		res, err := client.BatchDNSRecords(ctx, zoneID, req)
+		if err != nil {
+			for i := range outcomes {
+				outcomes[i].Err = err
+			}
+			return outcomes
+		}
+		for n, i := range puts {
+			if n < len(res.Puts) {
+				outcomes[i].After = res.Puts[n]
+			}
+		}
+		for n, i := range posts {
+			if n < len(res.Posts) {
+				outcomes[i].After = res.Posts[n]
+			}
+		}
+	}
+	if len(creates) == 0 {
+		return outcomes
+	}
+
+	records := make([]api.DNSRecord, len(creates))
+	for n, i := range creates {
+		records[n] = changes[i].After
+	}
+	_, err := client.ImportZoneFile(ctx, zoneID, zonefile.RenderForImport(zoneName, records))
+	var after []api.DNSRecord
+	if err == nil {
+		after, err = client.ListDNSRecords(ctx, zoneID)
+	}
+	if err != nil {
+		for _, i := range creates {
+			outcomes[i].Err = err
+		}
+		return outcomes
+	}
+	// The endpoint only reports counts, so find each created record by its
+	// name, type and data.
+	found := make(map[string]bool)
+	for _, i := range creates {
+		want := changes[i].After
+		outcomes[i].Err = fmt.Errorf("%s %s was not added by the import endpoint", want.Type, want.Name)
+		for _, r := range after {
+			if !found[r.ID] && strings.EqualFold(r.Name, want.Name) && r.Type == want.Type && rdataKey(r) == rdataKey(want) {
+				found[r.ID] = true
+				outcomes[i].After, outcomes[i].Err = r, nil
+				break
+			}
+		}
+	}
+	return outcomes
+}
@@ -0,0 +1,102 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/config"
+)
+
+// applyChanges is a plan with one change of each kind.
+var applyChanges = []Change{
+	{Before: api.DNSRecord{ID: "old", Type: "A", Name: "old.example.com", Content: "192.0.2.9", TTL: 300}},
+	{
+		Before: api.DNSRecord{ID: "www", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 300},
+		After:  api.DNSRecord{ID: "www", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 60},
+	},
+	{After: api.DNSRecord{Type: "A", Name: "api.example.com", Content: "192.0.2.7", TTL: 300}},
+}
+
+// batchServer fakes the batch endpoint, counting its calls in *calls, and
+// the import endpoint, which fails.
+func batchServer(t *testing.T, calls *int, body *map[string][]map[string]any) *api.Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Errorf("decoding batch body: %v", err)
+		}
+		for i, p := range (*body)["posts"] {
+			p["id"] = fmt.Sprintf("new-%d", i)
+		}
+		b, _ := json.Marshal(body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":%s}`, b)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/import", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":1000,"message":"bad zone file"}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+}
+
+func TestApply(t *testing.T) {
+	var calls int
+	var body map[string][]map[string]any
+	client := batchServer(t, &calls, &body)
+
+	outcomes := Apply(context.Background(), client, "zone-1", "example.com", applyChanges, false)
+	if calls != 1 {
+		t.Fatalf("batch calls = %d, want every change in one batch", calls)
+	}
+	if len(body["deletes"]) != 1 || len(body["puts"]) != 1 || len(body["posts"]) != 1 {
+		t.Errorf("batch body = %v", body)
+	}
+	for _, o := range outcomes {
+		if o.Err != nil {
+			t.Errorf("%s failed: %v", o.Change.Action(), o.Err)
+		}
+	}
+	if outcomes[1].After.TTL != 60 || outcomes[2].After.ID != "new-0" {
+		t.Errorf("outcomes do not carry the returned records: %+v", outcomes)
+	}
+
+	// One invalid change keeps the whole plan from being sent.
+	invalid := append([]Change(nil), applyChanges...)
+	invalid[2].After.Content = "not-an-ip"
+	outcomes = Apply(context.Background(), client, "zone-1", "example.com", invalid, false)
+	if calls != 1 {
+		t.Error("expected nothing to be sent for a plan with an invalid change")
+	}
+	for _, o := range outcomes {
+		if o.Err == nil {
+			t.Errorf("%s has no error", o.Change.Action())
+		}
+	}
+}
+
+func TestApplyEndpointFailedCreate(t *testing.T) {
+	var calls int
+	var body map[string][]map[string]any
+	client := batchServer(t, &calls, &body)
+
+	outcomes := Apply(context.Background(), client, "zone-1", "example.com", applyChanges, true)
+	if calls != 1 || len(body["posts"]) != 0 {
+		t.Errorf("batch body = %v, want the delete and update only", body)
+	}
+	if outcomes[0].Err != nil || outcomes[1].Err != nil {
+		t.Errorf("expected the delete and update to be reported applied: %+v", outcomes)
+	}
+	if outcomes[2].Err == nil {
+		t.Error("expected the create to fail")
+	}
+}
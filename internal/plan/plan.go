@@ -0,0 +1,300 @@
+// Package plan works out the changes that make a zone match a zone file or
+// a desired-state file, renders them for review and applies them. The
+// terminal UI and the command line both plan through it, so a change
+// reviewed in one reads the same in the other.
+package plan
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/state"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// Change is one step of a plan. Before is the zero record for a create and
+// After is the zero record for a delete.
+type Change struct {
+	Before api.DNSRecord
+	After  api.DNSRecord
+}
+
+// Action describes the change: "create", "update" or "delete".
+func (c Change) Action() string {
+	switch {
+	case c.Before.ID == "":
+		return "create"
+	case c.After.Type == "":
+		return "delete"
+	}
+	return "update"
+}
+
+// Record returns the record the change is about: the record as it will be,
+// or as it was for a delete.
+func (c Change) Record() api.DNSRecord {
+	if c.Action() == "delete" {
+		return c.Before
+	}
+	return c.After
+}
+
+// Summary describes the change: the record data of a create or delete, or
+// the fields an update changes as "field: old → new".
+func (c Change) Summary() string {
+	if c.Action() != "update" {
+		r := c.Record()
+		return zonefile.RData(r.Type, r.Content, r.Priority, r.Data)
+	}
+	from, to := Fields(c.Before), Fields(c.After)
+	var parts []string
+	for i, label := range FieldLabels {
+		if from[i] != to[i] {
+			parts = append(parts, fmt.Sprintf("%s: %s → %s", label, from[i], to[i]))
+		}
+	}
+	if len(parts) == 0 {
+		return "no field changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FieldLabels names the editable record fields, in the order diffs list
+// them.
+var FieldLabels = []string{"Name", "Content", "Priority", "TTL", "Proxied", "Comment", "Tags"}
+
+// Fields returns the editable fields of r as display strings, indexed like
+// FieldLabels. Content covers structured data too.
+func Fields(r api.DNSRecord) []string {
+	ttl := strconv.Itoa(r.TTL)
+	if r.TTL == 1 {
+		ttl = "Auto"
+	}
+	priority := ""
+	if api.UsesPriority(r.Type) {
+		priority = strconv.Itoa(r.Priority)
+	}
+	return []string{
+		r.Name,
+		zonefile.RData(r.Type, r.Content, 0, r.Data),
+		priority,
+		ttl,
+		strconv.FormatBool(r.Proxied),
+		r.Comment,
+		strings.Join(r.Tags, ", "),
+	}
+}
+
+// differs reports whether any editable field of a and b differs.
+func differs(a, b api.DNSRecord) bool {
+	x, y := Fields(a), Fields(b)
+	for i := range x {
+		if x[i] != y[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Import compares the records parsed from a zone file with the zone's
+// current records and returns the changes that make the zone match the file.
+// Records are paired by name and type, those with the same data first, so a
+// name with several records only shows the ones that differ. Updates keep the
+// current comment and tags unless the file sets them, and for a record
+// without cf_tags the current proxy status and automatic TTL. The apex NS
+// records are left alone: Cloudflare assigns them.
+func Import(zoneName string, current []api.DNSRecord, desired []zonefile.Record) []Change {
+	return pair(zoneName, current, desired, true)
+}
+
+// pair works out the changes for Import and State. keepNotes keeps the
+// current comment and tags where the desired record has none.
+func pair(zoneName string, current []api.DNSRecord, desired []zonefile.Record, keepNotes bool) []Change {
+	apex := strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	key := func(r api.DNSRecord) string {
+		return strings.ToLower(strings.TrimSuffix(r.Name, ".")) + " " + r.Type
+	}
+	skip := func(r api.DNSRecord) bool {
+		return r.Type == "SOA" || r.Type == "NS" && strings.ToLower(strings.TrimSuffix(r.Name, ".")) == apex
+	}
+
+	var keys []string
+	want := make(map[string][]zonefile.Record)
+	have := make(map[string][]api.DNSRecord)
+	for _, r := range desired {
+		if skip(r.DNSRecord) {
+			continue
+		}
+		k := key(r.DNSRecord)
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+		want[k] = append(want[k], r)
+	}
+	for _, r := range current {
+		if skip(r) {
+			continue
+		}
+		k := key(r)
+		if _, ok := want[k]; !ok {
+			if _, ok := have[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		have[k] = append(have[k], r)
+	}
+
+	var changes []Change
+	for _, k := range keys {
+		files, zone := want[k], have[k]
+		fileUsed := make([]bool, len(files))
+		zoneUsed := make([]bool, len(zone))
+		update := func(before api.DNSRecord, f zonefile.Record, sameData bool) {
+			after := f.DNSRecord
+			after.ID = before.ID
+			after.Name = before.Name
+			if sameData {
+				after.Content, after.Priority, after.Data = before.Content, before.Priority, before.Data
+			}
+			if keepNotes && after.Tags == nil {
+				after.Tags = before.Tags
+			}
+			if keepNotes && after.Comment == "" {
+				after.Comment = before.Comment
+			}
+			if !f.Tagged {
+				after.Proxied = before.Proxied
+				if before.TTL == 1 {
+					after.TTL = 1
+				}
+			}
+			if !differs(before, after) {
+				return
+			}
+			changes = append(changes, Change{Before: before, After: after})
+		}
+
+		// Pair records with the same data, which at most change TTL, proxying
+		// or comment.
+		for i, f := range files {
+			for j, z := range zone {
+				if !zoneUsed[j] && rdataKey(f.DNSRecord) == rdataKey(z) {
+					fileUsed[i], zoneUsed[j] = true, true
+					update(z, f, true)
+					break
+				}
+			}
+		}
+		// Pair the rest in order; what is left over is created or deleted.
+		j := 0
+		for i, f := range files {
+			if fileUsed[i] {
+				continue
+			}
+			for j < len(zone) && zoneUsed[j] {
+				j++
+			}
+			if j < len(zone) {
+				zoneUsed[j] = true
+				update(zone[j], f, false)
+				continue
+			}
+			changes = append(changes, Change{After: f.DNSRecord})
+		}
+		for j, z := range zone {
+			if !zoneUsed[j] {
+				changes = append(changes, Change{Before: z})
+			}
+		}
+	}
+	return changes
+}
+
+// rdataKey identifies the data of r for comparison, so that differently
+// written forms of the same data, such as a TXT record quoted or not, match.
+func rdataKey(r api.DNSRecord) string {
+	switch r.Type {
+	case "TXT":
+		return strings.Join(zonefile.TXTStrings(r.Content), "\x00")
+	case "A", "AAAA":
+		if addr, err := netip.ParseAddr(r.Content); err == nil {
+			return addr.String()
+		}
+	}
+	return strings.ToLower(zonefile.RData(r.Type, r.Content, r.Priority, r.Data))
+}
+
+// State plans making the zone match a desired-state file. Unlike a zone
+// file import, the file lists every record the zone should have, including
+// its comment and tags, so records it does not list are deleted and a
+// comment or tag it leaves out is cleared. Records ignore matches are left
+// out of the plan whether the file lists them or not, and with ignore.All
+// nothing is deleted. ignored counts the records left alone that way.
+func State(zoneName string, current, desired []api.DNSRecord, ignore state.Ignore) (changes []Change, ignored int) {
+	rules := ignore
+	rules.All = false
+	skip := rules.Matcher(zoneName, current)
+	var live []api.DNSRecord
+	for _, r := range current {
+		if skip(r) {
+			ignored++
+			continue
+		}
+		live = append(live, r)
+	}
+	// A state file always says whether a record is proxied and its TTL.
+	var want []zonefile.Record
+	for _, r := range desired {
+		if !skip(r) {
+			want = append(want, zonefile.Record{DNSRecord: r, Tagged: true})
+		}
+	}
+	for _, c := range pair(zoneName, live, want, false) {
+		if ignore.All && c.Action() == "delete" {
+			ignored++
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, ignored
+}
+
+// symbols marks each action in a plan.
+var symbols = map[string]string{"create": "+", "update": "~", "delete": "-"}
+
+// Line describes a change in one line, such as "+ create A www.example.com".
+func Line(c Change) string {
+	r := c.Record()
+	return fmt.Sprintf("%s %-6s %-5s %s", symbols[c.Action()], c.Action(), r.Type, r.Name)
+}
+
+// Counts totals a plan, as in "1 to create, 2 to update, 0 to delete".
+func Counts(changes []Change) string {
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[c.Action()]++
+	}
+	return fmt.Sprintf("%d to create, %d to update, %d to delete", counts["create"], counts["update"], counts["delete"])
+}
+
+// Render renders a plan for review: each change with its diff, then the
+// totals. ignored is the number of unmanaged records left alone.
+func Render(changes []Change, ignored int) string {
+	var b strings.Builder
+	for _, c := range changes {
+		b.WriteString(Line(c) + "\n")
+		b.WriteString("      " + c.Summary() + "\n")
+	}
+	if len(changes) == 0 {
+		b.WriteString("No changes: the zone matches the file.")
+	} else {
+		b.WriteString("\nPlan: " + Counts(changes) + ".")
+	}
+	if ignored > 0 {
+		fmt.Fprintf(&b, " Unmanaged records ignored: %d.", ignored)
+	}
+	return b.String() + "\n"
+}
@@ -0,0 +1,141 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/state"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+func TestImport(t *testing.T) {
+	current := []api.DNSRecord{
+		{ID: "ns", Type: "NS", Name: "example.com", Content: "anna.ns.cloudflare.com", TTL: 86400},
+		{ID: "a1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300, Comment: "origin", Tags: []string{"env:prod"}},
+		{ID: "a2", Type: "A", Name: "example.com", Content: "192.0.2.2", TTL: 300},
+		{ID: "txt", Type: "TXT", Name: "example.com", Content: `"v=spf1 -all"`, TTL: 1},
+		{ID: "www", Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 1, Proxied: true},
+		{ID: "old", Type: "A", Name: "old.example.com", Content: "192.0.2.9", TTL: 1},
+		{ID: "api", Type: "A", Name: "api.example.com", Content: "192.0.2.4", TTL: 1, Proxied: true},
+	}
+	desired := []zonefile.Record{
+		{DNSRecord: api.DNSRecord{Type: "NS", Name: "example.com", Content: "ns1.oldhost.net", TTL: 3600}},
+		{DNSRecord: api.DNSRecord{Type: "A", Name: "example.com", Content: "192.0.2.2", TTL: 300}},
+		{DNSRecord: api.DNSRecord{Type: "A", Name: "example.com", Content: "192.0.2.3", TTL: 300}},
+		{DNSRecord: api.DNSRecord{Type: "TXT", Name: "example.com", Content: "v=spf1 -all", TTL: 1}},
+		{DNSRecord: api.DNSRecord{Type: "CNAME", Name: "WWW.example.com", Content: "example.com", TTL: 1}, Tagged: true},
+		{DNSRecord: api.DNSRecord{Type: "MX", Name: "example.com", Content: "mx1.example.com", Priority: 10, TTL: 3600}},
+		// Without cf_tags, api stays proxied with an automatic TTL.
+		{DNSRecord: api.DNSRecord{Type: "A", Name: "api.example.com", Content: "192.0.2.4", TTL: 3600}},
+	}
+
+	changes := Import("example.com", current, desired)
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Action()+" "+c.Record().ID+" "+c.Summary())
+	}
+	want := []string{
+		"update a1 Content: 192.0.2.1 → 192.0.2.3",
+		"update www Proxied: true → false",
+		"create  10 mx1.example.com.",
+		"delete old 192.0.2.9",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plan = %q\nwant %q", got, want)
+	}
+	if a1 := changes[0].After; a1.Comment != "origin" || !reflect.DeepEqual(a1.Tags, []string{"env:prod"}) {
+		t.Errorf("expected the update to keep the comment and tags, got %+v", a1)
+	}
+	if name := changes[1].After.Name; name != "www.example.com" {
+		t.Errorf("expected the current name to be kept, got %q", name)
+	}
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	records := []api.DNSRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: true, Comment: "web"},
+		{ID: "2", Type: "AAAA", Name: "example.com", Content: "2001:db8:0::1", TTL: 300},
+		{ID: "3", Type: "TXT", Name: "example.com", Content: `"part one" "part \"two\""`, TTL: 1},
+		{ID: "4", Type: "MX", Name: "example.com", Content: "mx.example.net", Priority: 10, TTL: 3600},
+		{ID: "5", Type: "SRV", Name: "_sip._tcp.example.com", TTL: 300, Data: map[string]any{
+			"priority": 10.0, "weight": 60.0, "port": 5060.0, "target": "sip.example.com"}},
+		{ID: "6", Type: "CAA", Name: "example.com", TTL: 1, Data: map[string]any{"flags": 0.0, "tag": "issue", "value": "letsencrypt.org"}},
+	}
+	desired, err := zonefile.Parse(zonefile.Render("example.com", records), "example.com")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if changes := Import("example.com", records, desired); len(changes) != 0 {
+		t.Errorf("expected an exported zone to import without changes, got %+v", changes)
+	}
+}
+
+func TestStateAndRender(t *testing.T) {
+	current := []api.DNSRecord{
+		{ID: "a", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1},
+		{ID: "owner", Type: "TXT", Name: "a-app.example.com", Content: `"heritage=external-dns,external-dns/owner=k8s"`, TTL: 1},
+		{ID: "app", Type: "A", Name: "app.example.com", Content: "192.0.2.5", TTL: 1},
+		{ID: "old", Type: "A", Name: "old.example.com", Content: "192.0.2.9", TTL: 1},
+	}
+	desired := []api.DNSRecord{
+		{Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+		{Type: "AAAA", Name: "example.com", Content: "2001:db8::1", TTL: 1},
+	}
+	changes, ignored := State("example.com", current, desired, state.Ignore{ExternalDNS: true})
+	if ignored != 2 {
+		t.Errorf("ignored = %d, want the external-dns records", ignored)
+	}
+	want := "~ update A     example.com\n" +
+		"      TTL: Auto → 300\n" +
+		"+ create AAAA  example.com\n" +
+		"      2001:db8::1\n" +
+		"- delete A     old.example.com\n" +
+		"      192.0.2.9\n" +
+		"\nPlan: 1 to create, 1 to update, 1 to delete. Unmanaged records ignored: 2.\n"
+	if got := Render(changes, ignored); got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+	if got := Render(nil, 0); got != "No changes: the zone matches the file.\n" {
+		t.Errorf("empty plan = %q", got)
+	}
+}
+
+func TestStateIsAuthoritative(t *testing.T) {
+	current := []api.DNSRecord{
+		{ID: "www", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Comment: "legacy", Tags: []string{"team:web"}},
+		{ID: "vpn", Type: "A", Name: "vpn.example.com", Content: "192.0.2.2", TTL: 1},
+		{ID: "old", Type: "A", Name: "old.example.com", Content: "192.0.2.9", TTL: 1},
+	}
+	desired := []api.DNSRecord{
+		{Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1},
+		{Type: "A", Name: "vpn.example.com", Content: "192.0.2.3", TTL: 1},
+	}
+
+	// The file leaves out the comment and tags of www, so they are cleared;
+	// vpn is ignored, so it is not updated although the file changes it.
+	changes, ignored := State("example.com", current, desired, state.Ignore{Names: []string{"vpn"}})
+	if ignored != 1 {
+		t.Errorf("ignored = %d, want 1", ignored)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want an update of www and a delete of old", changes)
+	}
+	if c := changes[0]; c.Action() != "update" || c.After.Name != "www.example.com" || c.After.Comment != "" || c.After.Tags != nil {
+		t.Errorf("changes[0] = %+v, want www with its comment and tags cleared", c)
+	}
+	if c := changes[1]; c.Action() != "delete" || c.Before.ID != "old" {
+		t.Errorf("changes[1] = %+v, want old deleted", c)
+	}
+
+	// All keeps the unlisted record but still updates the listed ones.
+	changes, ignored = State("example.com", current, desired, state.Ignore{All: true})
+	if ignored != 1 || len(changes) != 2 {
+		t.Errorf("with All: ignored = %d, changes = %+v, want two updates and old kept", ignored, changes)
+	}
+	for _, c := range changes {
+		if c.Action() != "update" {
+			t.Errorf("with All: unexpected %s of %s", c.Action(), c.Record().Name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package prefs persists UI preferences between runs in a small JSON file
+// under the user's configuration directory. It never stores credentials.
+package prefs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Prefs holds the user's saved UI choices.
+type Prefs struct {
+	// HiddenColumns lists the titles of records table columns the user hid.
+	HiddenColumns []string `json:"hidden_columns,omitempty"`
+	// DetailPane shows the selected record beside the table on wide terminals.
+	DetailPane bool `json:"detail_pane,omitempty"`
+	// Queries are the saved filter expressions, keyed by name.
+	Queries map[string]string `json:"queries,omitempty"`
+}
+
+// DefaultPath returns the preferences file location, e.g.
+// ~/.config/cloudflare-tui/prefs.json on Linux.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config directory: %w", err)
+	}
+	return filepath.Join(dir, "cloudflare-tui", "prefs.json"), nil
+}
+
+// Load reads preferences from path. A missing file yields the defaults.
+func Load(path string) (Prefs, error) {
+	var p Prefs
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("reading preferences: %w", err)
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return Prefs{}, fmt.Errorf("parsing preferences %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save writes preferences to path, creating its directory if needed. The file
+// is replaced atomically so a crash never leaves it half-written.
+func Save(path string, p Prefs) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding preferences: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating preferences directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".prefs-*.json")
+	if err != nil {
+		return fmt.Errorf("writing preferences: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing preferences: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing preferences: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing preferences: %w", err)
+	}
+	return nil
+}
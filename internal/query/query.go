@@ -0,0 +1,437 @@
+// Package query parses and evaluates filter expressions over DNS records,
+// such as
+//
+//	type == "A" && !proxied && ttl < 300 && content ~ "^10\."
+//
+// An expression combines comparisons with &&, || and !, and parentheses.
+// String fields compare with == and != ignoring case, or with ~ and !~
+// against a regular expression. Number fields take ==, !=, <, <=, > and >=;
+// boolean fields can be used on their own or compared with true and false.
+// Inside a string literal only \" and \\ are escapes, so regular expressions
+// can be written without doubling their backslashes.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// Error is a syntax or type error in an expression. Pos and End are the
+// byte offsets of the offending text, so a caller can highlight it.
+type Error struct {
+	Pos int
+	End int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos+1, e.Msg)
+}
+
+// kind is the type of a record field.
+type kind int
+
+const (
+	kindString kind = iota
+	kindNumber
+	kindBool
+	kindList
+)
+
+// field reads one record field; only the getter matching its kind is set.
+type field struct {
+	kind kind
+	str  func(api.DNSRecord) string
+	num  func(api.DNSRecord) int
+	flag func(api.DNSRecord) bool
+	list func(api.DNSRecord) []string
+}
+
+// fields are the record fields an expression can refer to.
+var fields = map[string]field{
+	"type":      {kind: kindString, str: func(r api.DNSRecord) string { return r.Type }},
+	"name":      {kind: kindString, str: func(r api.DNSRecord) string { return r.Name }},
+	"content":   {kind: kindString, str: func(r api.DNSRecord) string { return r.Content }},
+	"comment":   {kind: kindString, str: func(r api.DNSRecord) string { return r.Comment }},
+	"ttl":       {kind: kindNumber, num: func(r api.DNSRecord) int { return r.TTL }},
+	"priority":  {kind: kindNumber, num: func(r api.DNSRecord) int { return r.Priority }},
+	"proxied":   {kind: kindBool, flag: func(r api.DNSRecord) bool { return r.Proxied }},
+	"proxiable": {kind: kindBool, flag: func(r api.DNSRecord) bool { return r.Proxiable }},
+	"tags":      {kind: kindList, list: func(r api.DNSRecord) []string { return r.Tags }},
+}
+
+// Fields lists the field names an expression can use, for help text.
+var Fields = []string{"type", "name", "content", "comment", "ttl", "priority", "proxied", "proxiable", "tags"}
+
+// Query is a parsed expression.
+type Query struct {
+	src  string
+	root node
+}
+
+// Parse parses src into a query. Errors are of type *Error.
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, t.errorf("unexpected %s after the expression", t.describe())
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// Match reports whether r satisfies the query.
+func (q *Query) Match(r api.DNSRecord) bool {
+	return q.root.eval(r)
+}
+
+// String returns the expression as written.
+func (q *Query) String() string {
+	return q.src
+}
+
+// node is one part of a parsed expression.
+type node interface {
+	eval(api.DNSRecord) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(r api.DNSRecord) bool { return n.left.eval(r) && n.right.eval(r) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(r api.DNSRecord) bool { return n.left.eval(r) || n.right.eval(r) }
+
+type notNode struct{ x node }
+
+func (n notNode) eval(r api.DNSRecord) bool { return !n.x.eval(r) }
+
+// compareNode compares a field with a literal. re is set for ~ and !~.
+type compareNode struct {
+	field field
+	op    string
+	str   string
+	num   int
+	flag  bool
+	re    *regexp.Regexp
+}
+
+func (n compareNode) eval(r api.DNSRecord) bool {
+	switch n.field.kind {
+	case kindString:
+		return n.matchString(n.field.str(r))
+	case kindNumber:
+		v := n.field.num(r)
+		switch n.op {
+		case "==":
+			return v == n.num
+		case "!=":
+			return v != n.num
+		case "<":
+			return v < n.num
+		case "<=":
+			return v <= n.num
+		case ">":
+			return v > n.num
+		case ">=":
+			return v >= n.num
+		}
+	case kindBool:
+		return (n.field.flag(r) == n.flag) == (n.op == "==")
+	case kindList:
+		// A list matches if any element does; the negated operators hold
+		// only if no element matches.
+		positive, negate := n, false
+		switch n.op {
+		case "!=":
+			positive.op, negate = "==", true
+		case "!~":
+			positive.op, negate = "~", true
+		}
+		found := false
+		for _, s := range n.field.list(r) {
+			if positive.matchString(s) {
+				found = true
+				break
+			}
+		}
+		return found != negate
+	}
+	return false
+}
+
+// matchString applies a string operator to s.
+func (n compareNode) matchString(s string) bool {
+	switch n.op {
+	case "==":
+		return strings.EqualFold(s, n.str)
+	case "!=":
+		return !strings.EqualFold(s, n.str)
+	case "~":
+		return n.re.MatchString(s)
+	case "!~":
+		return !n.re.MatchString(s)
+	}
+	return false
+}
+
+// parser is a recursive-descent parser over the token list:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" or ")" | field [ op literal ]
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("||") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("&&") {
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	t := p.next()
+	switch {
+	case t.is("!"):
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	case t.is("("):
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); !closing.is(")") {
+			return nil, closing.errorf("expected ) to close the ( at column %d, found %s", t.pos+1, closing.describe())
+		}
+		return x, nil
+	case t.kind == tokIdent:
+		return p.comparison(t)
+	}
+	return nil, t.errorf("expected a field name, ( or !, found %s", t.describe())
+}
+
+// comparison parses the rest of a comparison whose field is name.
+func (p *parser) comparison(name token) (node, error) {
+	f, ok := fields[name.text]
+	if !ok {
+		return nil, name.errorf("unknown field %q; fields are %s", name.text, strings.Join(Fields, ", "))
+	}
+	op := p.peek()
+	if op.kind != tokOp || !isComparison(op.text) {
+		if f.kind == kindBool {
+			return compareNode{field: f, op: "==", flag: true}, nil
+		}
+		return nil, op.errorf("expected a comparison after %s, found %s", name.text, op.describe())
+	}
+	p.next()
+	lit := p.next()
+	n := compareNode{field: f, op: op.text}
+
+	switch f.kind {
+	case kindString, kindList:
+		if op.text != "==" && op.text != "!=" && op.text != "~" && op.text != "!~" {
+			return nil, op.errorf("%s cannot be compared with %s; use ==, !=, ~ or !~", name.text, op.text)
+		}
+		if lit.kind != tokString {
+			return nil, lit.errorf("expected a quoted string, found %s", lit.describe())
+		}
+		n.str = lit.text
+		if op.text == "~" || op.text == "!~" {
+			re, err := regexp.Compile(lit.text)
+			if err != nil {
+				return nil, lit.errorf("invalid regular expression: %v", err)
+			}
+			n.re = re
+		}
+	case kindNumber:
+		if op.text == "~" || op.text == "!~" {
+			return nil, op.errorf("%s is a number; use ==, !=, <, <=, > or >=", name.text)
+		}
+		if lit.kind != tokNumber {
+			return nil, lit.errorf("expected a number, found %s", lit.describe())
+		}
+		v, err := strconv.Atoi(lit.text)
+		if err != nil {
+			return nil, lit.errorf("number %s is out of range", lit.text)
+		}
+		n.num = v
+	case kindBool:
+		if op.text != "==" && op.text != "!=" {
+			return nil, op.errorf("%s is true or false; use == or !=", name.text)
+		}
+		if !lit.is("true") && !lit.is("false") {
+			return nil, lit.errorf("expected true or false, found %s", lit.describe())
+		}
+		n.flag = lit.text == "true"
+	}
+	return n, nil
+}
+
+// isComparison reports whether op compares a field with a literal.
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "~", "!~":
+		return true
+	}
+	return false
+}
+
+// tokenKind classifies a token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+// token is one lexical element; text is unquoted for strings.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// is reports whether t is the operator or identifier s.
+func (t token) is(s string) bool {
+	return (t.kind == tokOp || t.kind == tokIdent) && t.text == s
+}
+
+// describe names t for an error message.
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// errorf returns an Error covering t.
+func (t token) errorf(format string, args ...any) error {
+	end := t.end
+	if end == t.pos {
+		end = t.pos + 1 // highlight the place where something is missing
+	}
+	return &Error{Pos: t.pos, End: end, Msg: fmt.Sprintf(format, args...)}
+}
+
+// operators are tried longest first so "!=" is not read as "!" "=".
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "<", ">", "~", "!", "(", ")"}
+
+// lex splits src into tokens, ending with a tokEOF.
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, &Error{Pos: start, End: len(src), Msg: "unterminated string"}
+				}
+				if src[i] == '"' {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) && (src[i+1] == '"' || src[i+1] == '\\') {
+					i++
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{kind: tokString, text: b.String(), pos: start, end: i})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], pos: start, end: i})
+		case c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			start := i
+			for i < len(src) && (src[i] == '_' || (src[i]|0x20 >= 'a' && src[i]|0x20 <= 'z') || (src[i] >= '0' && src[i] <= '9')) {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: strings.ToLower(src[start:i]), pos: start, end: i})
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				if c == '=' || c == '&' || c == '|' {
+					return nil, &Error{Pos: i, End: i + 1, Msg: fmt.Sprintf("unexpected %q; did you mean %q?", c, strings.Repeat(string(c), 2))}
+				}
+				return nil, &Error{Pos: i, End: i + 1, Msg: fmt.Sprintf("unexpected character %q", src[i:i+1])}
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i, end: i + len(op)})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src), end: len(src)}), nil
+}
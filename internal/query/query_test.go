@@ -0,0 +1,117 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+func TestMatch(t *testing.T) {
+	internal := api.DNSRecord{Type: "A", Name: "db.example.com", Content: "10.0.0.5", TTL: 120}
+	proxied := api.DNSRecord{Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true, Proxiable: true}
+	cname := api.DNSRecord{Type: "CNAME", Name: "shop.example.com", Content: "shops.myshopify.com", TTL: 3600, Tags: []string{"owner:web", "env:prod"}}
+	mx := api.DNSRecord{Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 300, Priority: 10, Comment: "Primary MX"}
+	records := []api.DNSRecord{internal, proxied, cname, mx}
+
+	tests := []struct {
+		query string
+		want  []api.DNSRecord
+	}{
+		{`type == "A" && !proxied && ttl < 300 && content ~ "^10\."`, []api.DNSRecord{internal}},
+		{`type == "a"`, []api.DNSRecord{internal, proxied}},
+		{`type != "A"`, []api.DNSRecord{cname, mx}},
+		{`proxied`, []api.DNSRecord{proxied}},
+		{`proxied == false && proxiable != true`, []api.DNSRecord{internal, cname, mx}},
+		{`ttl >= 300 || priority == 10`, []api.DNSRecord{cname, mx}},
+		{`ttl <= 1`, []api.DNSRecord{proxied}},
+		{`ttl > 120 && ttl != 3600`, []api.DNSRecord{mx}},
+		{`type == "CNAME" && content !~ "example\.com$"`, []api.DNSRecord{cname}},
+		{`!(type == "A" || type == "MX")`, []api.DNSRecord{cname}},
+		{`comment ~ "(?i)primary"`, []api.DNSRecord{mx}},
+		{`tags == "env:prod"`, []api.DNSRecord{cname}},
+		{`tags ~ "^owner:"`, []api.DNSRecord{cname}},
+		{`tags !~ "^owner:"`, []api.DNSRecord{internal, proxied, mx}},
+		{`name == "example.com" || name ~ "^www\."`, []api.DNSRecord{proxied, mx}},
+		{`content ~ "\"quoted\""`, nil},
+		{`TYPE == "MX"`, []api.DNSRecord{mx}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var got []api.DNSRecord
+			for _, r := range records {
+				if q.Match(r) {
+					got = append(got, r)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %d records, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Name != tt.want[i].Name || got[i].Type != tt.want[i].Type {
+					t.Errorf("match %d = %s %s, want %s %s", i, got[i].Type, got[i].Name, tt.want[i].Type, tt.want[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestAndBindsTighterThanOr(t *testing.T) {
+	q, err := Parse(`type == "A" || type == "MX" && ttl > 1000`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !q.Match(api.DNSRecord{Type: "A", TTL: 1}) {
+		t.Error("expected an A record to match regardless of TTL")
+	}
+	if q.Match(api.DNSRecord{Type: "MX", TTL: 1}) {
+		t.Error("expected the TTL to apply to the MX alternative only")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		pos     int
+		wantMsg string
+	}{
+		{``, 0, "expected a field name, ( or !, found end of query"},
+		{`typ == "A"`, 0, `unknown field "typ"`},
+		{`type = "A"`, 5, `unexpected '='; did you mean "=="?`},
+		{`type == A`, 8, "expected a quoted string"},
+		{`ttl < "300"`, 6, "expected a number"},
+		{`type < "A"`, 5, "type cannot be compared with <"},
+		{`ttl ~ "1"`, 4, "ttl is a number"},
+		{`proxied == "yes"`, 11, "expected true or false"},
+		{`content ~ "(10"`, 10, "invalid regular expression"},
+		{`name == "x`, 8, "unterminated string"},
+		{`(type == "A"`, 12, "expected ) to close the ( at column 1"},
+		{`type == "A" ttl`, 12, `unexpected "ttl" after the expression`},
+		{`type == "A" && `, 15, "expected a field name"},
+		{`name`, 4, "expected a comparison after name"},
+		{`type == "A" # x`, 12, "unexpected character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var qe *Error
+			if !errors.As(err, &qe) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if qe.Pos != tt.pos {
+				t.Errorf("Pos = %d, want %d (%s)", qe.Pos, tt.pos, qe.Msg)
+			}
+			if qe.End <= qe.Pos {
+				t.Errorf("End = %d, want past Pos %d", qe.End, qe.Pos)
+			}
+			if !strings.HasPrefix(qe.Msg, tt.wantMsg) {
+				t.Errorf("Msg = %q, want prefix %q", qe.Msg, tt.wantMsg)
+			}
+		})
+	}
+}
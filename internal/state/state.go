@@ -0,0 +1,226 @@
+// Package state reads desired-state files: YAML or JSON documents that list
+// the records a zone should have, so that DNS can be kept in git and
+// compared with the zone before it is changed.
+//
+// A file names its zone, lists its records, and may say which records that
+// the file does not list are left alone rather than deleted:
+//
+//	zone: example.com
+//	ignore:
+//	  external_dns: true       # records owned by external-dns
+//	  names: ["_acme-challenge*"]
+//	  types: [TXT]
+//	records:
+//	  - name: "@"
+//	    type: A
+//	    content: 192.0.2.1
+//	    proxied: true
+//	  - name: mail
+//	    type: MX
+//	    content: mx1.example.com
+//	    priority: 10
+//	    ttl: 3600
+//
+// Names are relative to the zone unless they end with it or with a dot, and
+// "@" is the apex. A record without a TTL, or with "ttl: auto", gets
+// Cloudflare's automatic TTL. Structured types such as SRV and CAA give
+// their fields under data, named as in the Cloudflare API.
+package state
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// File is a desired-state file.
+type File struct {
+	Zone    string   `json:"zone"`
+	Ignore  Ignore   `json:"ignore,omitempty"`
+	Records []Record `json:"records"`
+}
+
+// Record is one record of a desired-state file.
+type Record struct {
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Content  string         `json:"content,omitempty"`
+	TTL      TTL            `json:"ttl,omitempty"`
+	Proxied  bool           `json:"proxied,omitempty"`
+	Priority int            `json:"priority,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+	Comment  string         `json:"comment,omitempty"`
+	Tags     []string       `json:"tags,omitempty"`
+}
+
+// TTL is a record TTL in seconds. It is written as a number or as "auto",
+// which like an omitted TTL means Cloudflare's automatic TTL.
+type TTL int
+
+// UnmarshalJSON accepts a number of seconds or "auto".
+func (t *TTL) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		if !strings.EqualFold(s, "auto") {
+			return fmt.Errorf("ttl must be a number of seconds or \"auto\", got %q", s)
+		}
+		*t = 1
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("ttl must be a number of seconds or \"auto\", got %s", b)
+	}
+	*t = TTL(n)
+	return nil
+}
+
+// Ignore says which records of the zone a file leaves alone: they are
+// neither updated nor deleted. A record is ignored if it matches any of the
+// rules.
+type Ignore struct {
+	// All keeps every record the file does not list, so applying the file
+	// only creates and updates.
+	All bool `json:"all,omitempty"`
+	// ExternalDNS keeps the records external-dns owns: its TXT ownership
+	// records and the records they mark.
+	ExternalDNS bool `json:"external_dns,omitempty"`
+	// Names are shell patterns matched against the name relative to the
+	// zone ("@" for the apex) and the full name, ignoring case.
+	Names []string `json:"names,omitempty"`
+	// Types are record types, such as TXT.
+	Types []string `json:"types,omitempty"`
+}
+
+// Merge returns the rules of ig and other together.
+func (ig Ignore) Merge(other Ignore) Ignore {
+	return Ignore{
+		All:         ig.All || other.All,
+		ExternalDNS: ig.ExternalDNS || other.ExternalDNS,
+		Names:       append(append([]string{}, ig.Names...), other.Names...),
+		Types:       append(append([]string{}, ig.Types...), other.Types...),
+	}
+}
+
+// Load reads a desired-state file. JSON is read as YAML, of which it is a
+// subset. Unknown fields are errors, so a misspelt key is not silently
+// dropped.
+func Load(src []byte) (File, error) {
+	var f File
+	if len(bytes.TrimSpace(src)) == 0 {
+		return f, errors.New("the file is empty")
+	}
+	if err := yaml.UnmarshalStrict(src, &f); err != nil {
+		return f, err
+	}
+	for _, pattern := range f.Ignore.Names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return f, fmt.Errorf("ignore: bad name pattern %q", pattern)
+		}
+	}
+	for i, r := range f.Records {
+		switch {
+		case strings.TrimSpace(r.Name) == "":
+			return f, fmt.Errorf("records[%d]: name is required", i)
+		case strings.TrimSpace(r.Type) == "":
+			return f, fmt.Errorf("records[%d] (%s): type is required", i, r.Name)
+		case r.Content != "" && len(r.Data) > 0:
+			return f, fmt.Errorf("records[%d] (%s): give content or data, not both", i, r.Name)
+		}
+	}
+	return f, nil
+}
+
+// DNSRecords returns the records of f in zone, with full names. An empty
+// zone uses the one the file names.
+func (f File) DNSRecords(zone string) []api.DNSRecord {
+	zone = strings.TrimSuffix(cmp.Or(zone, f.Zone), ".")
+	records := make([]api.DNSRecord, len(f.Records))
+	for i, r := range f.Records {
+		ttl := int(r.TTL)
+		if ttl == 0 {
+			ttl = 1
+		}
+		records[i] = api.DNSRecord{
+			Type:     strings.ToUpper(strings.TrimSpace(r.Type)),
+			Name:     api.QualifyName(r.Name, zone),
+			Content:  r.Content,
+			TTL:      ttl,
+			Proxied:  r.Proxied,
+			Priority: r.Priority,
+			Data:     r.Data,
+			Comment:  r.Comment,
+			Tags:     r.Tags,
+		}
+	}
+	return records
+}
+
+// Matcher returns a function reporting whether a record of zone is ignored.
+// current is every record of the zone, in which external-dns ownership
+// records are looked for.
+func (ig Ignore) Matcher(zone string, current []api.DNSRecord) func(api.DNSRecord) bool {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	owned := make(map[string]bool)
+	if ig.ExternalDNS {
+		for _, r := range current {
+			if r.Type != "TXT" || !strings.Contains(r.Content, "heritage=external-dns") {
+				continue
+			}
+			name := strings.ToLower(r.Name)
+			owned[name] = true
+			// Newer registries prefix the owned name with its record type,
+			// as in "a-www.example.com" or "cname-www.example.com".
+			label, rest, _ := strings.Cut(name, ".")
+			if kind, owner, ok := strings.Cut(label, "-"); ok && isRecordType(kind) {
+				owned[strings.TrimPrefix(owner+"."+rest, ".")] = true
+			}
+		}
+	}
+	return func(r api.DNSRecord) bool {
+		if ig.All {
+			return true
+		}
+		name := strings.ToLower(strings.TrimSuffix(r.Name, "."))
+		if owned[name] {
+			return true
+		}
+		for _, t := range ig.Types {
+			if strings.EqualFold(t, r.Type) {
+				return true
+			}
+		}
+		relative := strings.TrimSuffix(name, "."+zone)
+		if name == zone {
+			relative = "@"
+		}
+		for _, pattern := range ig.Names {
+			pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+			if ok, _ := path.Match(pattern, relative); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isRecordType reports whether s is the lower-case prefix external-dns
+// gives the ownership record of a record type.
+func isRecordType(s string) bool {
+	switch s {
+	case "a", "aaaa", "cname", "ns", "mx", "srv", "txt", "naptr", "ptr":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+func TestLoad(t *testing.T) {
+	yamlFile := `
+zone: example.com
+ignore:
+  external_dns: true
+  names: ["_acme-challenge*"]
+records:
+  - name: "@"
+    type: a
+    content: 192.0.2.1
+    proxied: true
+  - name: mail
+    type: MX
+    content: mx1.example.com
+    priority: 10
+    ttl: 3600
+  - name: _sip._tcp.example.com.
+    type: SRV
+    ttl: auto
+    data: {priority: 10, weight: 5, port: 5060, target: sip.example.com}
+    tags: [env:prod]
+`
+	jsonFile := `{"zone": "example.com", "ignore": {"external_dns": true, "names": ["_acme-challenge*"]}, "records": [
+  {"name": "@", "type": "a", "content": "192.0.2.1", "proxied": true},
+  {"name": "mail", "type": "MX", "content": "mx1.example.com", "priority": 10, "ttl": 3600},
+  {"name": "_sip._tcp.example.com.", "type": "SRV", "ttl": "auto",
+   "data": {"priority": 10, "weight": 5, "port": 5060, "target": "sip.example.com"}, "tags": ["env:prod"]}]}`
+
+	want := []api.DNSRecord{
+		{Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: true},
+		{Type: "MX", Name: "mail.example.com", Content: "mx1.example.com", TTL: 3600, Priority: 10},
+		{Type: "SRV", Name: "_sip._tcp.example.com", TTL: 1, Tags: []string{"env:prod"}, Data: map[string]any{
+			"priority": 10.0, "weight": 5.0, "port": 5060.0, "target": "sip.example.com"}},
+	}
+	for name, src := range map[string]string{"yaml": yamlFile, "json": jsonFile} {
+		t.Run(name, func(t *testing.T) {
+			f, err := Load([]byte(src))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !f.Ignore.ExternalDNS || !reflect.DeepEqual(f.Ignore.Names, []string{"_acme-challenge*"}) {
+				t.Errorf("unexpected ignore rules: %+v", f.Ignore)
+			}
+			if got := f.DNSRecords(""); !reflect.DeepEqual(got, want) {
+				t.Errorf("records = %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantMsg string
+	}{
+		{"", "empty"},
+		{"zone: example.com\nrecrods: []\n", `unknown field "recrods"`},
+		{"records:\n  - type: A\n    content: 192.0.2.1\n", "records[0]: name is required"},
+		{"records:\n  - name: www\n    content: 192.0.2.1\n", "records[0] (www): type is required"},
+		{"records:\n  - name: www\n    type: A\n    ttl: soon\n", `"auto"`},
+		{"records:\n  - name: s\n    type: SRV\n    content: x\n    data: {port: 1}\n", "content or data"},
+		{"ignore:\n  names: ['[a-']\n", "bad name pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantMsg, func(t *testing.T) {
+			_, err := Load([]byte(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	current := []api.DNSRecord{
+		{Type: "TXT", Name: "a-app.example.com", Content: `"heritage=external-dns,external-dns/owner=k8s"`},
+		{Type: "A", Name: "app.example.com", Content: "192.0.2.1"},
+		{Type: "TXT", Name: "legacy.example.com", Content: `"heritage=external-dns,external-dns/owner=k8s"`},
+		{Type: "CNAME", Name: "legacy.example.com", Content: "lb.example.net"},
+		{Type: "TXT", Name: "_acme-challenge.www.example.com", Content: "token"},
+		{Type: "CAA", Name: "example.com"},
+		{Type: "A", Name: "www.example.com", Content: "192.0.2.2"},
+	}
+	ignore := Ignore{ExternalDNS: true, Names: []string{"_ACME-challenge*"}}.Merge(Ignore{Types: []string{"caa"}})
+	match := ignore.Matcher("example.com", current)
+	var got []string
+	for _, r := range current {
+		if match(r) {
+			got = append(got, r.Type+" "+r.Name)
+		}
+	}
+	want := []string{
+		"TXT a-app.example.com", "A app.example.com",
+		"TXT legacy.example.com", "CNAME legacy.example.com",
+		"TXT _acme-challenge.www.example.com", "CAA example.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ignored %q\nwant %q", got, want)
+	}
+	if !(Ignore{All: true}).Matcher("example.com", nil)(current[6]) {
+		t.Error("expected All to ignore every record")
+	}
+	if (Ignore{Names: []string{"@"}}).Matcher("example.com", nil)(current[6]) {
+		t.Error("expected @ to match only the apex")
+	}
+}
@@ -0,0 +1,210 @@
+// Package terraform renders DNS records as Terraform configuration for the
+// Cloudflare provider: one cloudflare_dns_record resource per record, each
+// with an import block so that `terraform plan` adopts the existing record
+// instead of creating a new one.
+//
+// Resource names come from the record name relative to the zone and the
+// record type, such as "www_a" or "apex_mx", so exporting the same records
+// again gives the same names. Records that share a name and type are told
+// apart by the start of their record ID, which stays the same when their
+// content changes or other records are left out of the export.
+package terraform
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// resourceType is the provider's DNS record resource.
+const resourceType = "cloudflare_dns_record"
+
+// proxiableTypes are the record types whose proxied setting is written even
+// when off, as the provider tracks it for them.
+var proxiableTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true}
+
+// Render returns the resources and import blocks for records of the zone
+// with the given ID and name.
+func Render(zoneID, zoneName string, records []api.DNSRecord) string {
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted, func(a, b api.DNSRecord) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Content, b.Content),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	names := resourceNames(zoneName, sorted)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# DNS records of %s, exported for the Cloudflare Terraform provider.\n", zoneName)
+	for i, r := range sorted {
+		b.WriteByte('\n')
+		writeResource(&b, zoneID, names[i], r)
+		fmt.Fprintf(&b, "\nimport {\n  to = %s.%s\n  id = %s\n}\n", resourceType, names[i], quote(zoneID+"/"+r.ID))
+	}
+	return b.String()
+}
+
+// idPrefixLen is how much of a record ID tells apart records that share a
+// resource name. Cloudflare's IDs are random hex, so 8 digits do not clash.
+const idPrefixLen = 8
+
+// resourceNames returns a resource name for each record, in order. Records
+// that would share a name all get the start of their ID after it, as in
+// "www_a_023e105f".
+func resourceNames(zoneName string, records []api.DNSRecord) []string {
+	names := make([]string, len(records))
+	count := make(map[string]int)
+	for i, r := range records {
+		names[i] = resourceName(zoneName, r)
+		count[names[i]]++
+	}
+	for i, r := range records {
+		if count[names[i]] > 1 {
+			id := strings.ToLower(r.ID)
+			names[i] += "_" + identifier(id[:min(len(id), idPrefixLen)])
+		}
+	}
+	return names
+}
+
+// resourceName derives a Terraform identifier from the record name relative
+// to the zone and its type: "@" becomes "apex", "*" becomes "wildcard", and
+// any other character that is not a letter, digit, dash or underscore
+// becomes an underscore.
+func resourceName(zoneName string, r api.DNSRecord) string {
+	name := strings.ToLower(strings.TrimSuffix(r.Name, "."))
+	zone := strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	switch {
+	case name == zone:
+		name = "apex"
+	case strings.HasSuffix(name, "."+zone):
+		name = strings.TrimSuffix(name, "."+zone)
+	}
+	name = identifier(strings.ReplaceAll(name, "*", "wildcard"))
+	// Identifiers must start with a letter or underscore.
+	if name == "" || name[0] >= '0' && name[0] <= '9' || name[0] == '-' {
+		name = "_" + name
+	}
+	return name + "_" + strings.ToLower(r.Type)
+}
+
+// identifier replaces each character of s that is not a lowercase letter,
+// digit, dash or underscore with an underscore.
+func identifier(s string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' {
+			return c
+		}
+		return '_'
+	}, s)
+}
+
+// writeResource writes the resource block of r.
+func writeResource(b *strings.Builder, zoneID, name string, r api.DNSRecord) {
+	fmt.Fprintf(b, "resource %q %q {\n", resourceType, name)
+	attrs := [][2]string{
+		{"zone_id", quote(zoneID)},
+		{"name", quote(r.Name)},
+		{"type", quote(r.Type)},
+	}
+	if len(r.Data) == 0 {
+		attrs = append(attrs, [2]string{"content", quote(r.Content)})
+	}
+	attrs = append(attrs, [2]string{"ttl", strconv.Itoa(r.TTL)})
+	if proxiableTypes[r.Type] || r.Proxied {
+		attrs = append(attrs, [2]string{"proxied", strconv.FormatBool(r.Proxied)})
+	}
+	if api.UsesPriority(r.Type) {
+		attrs = append(attrs, [2]string{"priority", strconv.Itoa(r.Priority)})
+	}
+	if r.Comment != "" {
+		attrs = append(attrs, [2]string{"comment", quote(r.Comment)})
+	}
+	if len(r.Tags) > 0 {
+		tags := make([]string, len(r.Tags))
+		for i, t := range r.Tags {
+			tags[i] = quote(t)
+		}
+		attrs = append(attrs, [2]string{"tags", "[" + strings.Join(tags, ", ") + "]"})
+	}
+	writeAttrs(b, "  ", attrs)
+
+	if len(r.Data) > 0 {
+		keys := make([]string, 0, len(r.Data))
+		for k := range r.Data {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		data := make([][2]string, len(keys))
+		for i, k := range keys {
+			data[i] = [2]string{k, value(r.Data[k])}
+		}
+		b.WriteString("\n  data = {\n")
+		writeAttrs(b, "    ", data)
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+}
+
+// writeAttrs writes key = value lines with the equals signs aligned, as
+// terraform fmt does.
+func writeAttrs(b *strings.Builder, indent string, attrs [][2]string) {
+	width := 0
+	for _, a := range attrs {
+		width = max(width, len(a[0]))
+	}
+	for _, a := range attrs {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, a[0], a[1])
+	}
+}
+
+// value renders a Data value: numbers and booleans bare, anything else as
+// a string.
+func value(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return quote(v)
+	}
+	return quote(fmt.Sprint(v))
+}
+
+// quote renders s as an HCL string. Besides the usual escapes, "${" and
+// "%{" are doubled so that record content is never read as a template.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case (c == '$' || c == '%') && i+1 < len(s) && s[i+1] == '{':
+			b.WriteByte(c)
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
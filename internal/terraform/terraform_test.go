@@ -0,0 +1,152 @@
+package terraform
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+func TestRender(t *testing.T) {
+	records := []api.DNSRecord{
+		{ID: "r3", Type: "MX", Name: "example.com", Content: "mx1.example.com", TTL: 3600, Priority: 10},
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true,
+			Comment: `says "hi"`, Tags: []string{"env:prod", "team:web"}},
+		{ID: "r2", Type: "SRV", Name: "_sip._tcp.example.com", TTL: 300, Data: map[string]any{
+			"priority": 10.0, "weight": 5.0, "port": 5060.0, "target": "sip.example.com"}},
+		{ID: "r4", Type: "TXT", Name: "example.com", Content: `"v=spf1 ${x} -all"`, TTL: 1},
+	}
+	want := `# DNS records of example.com, exported for the Cloudflare Terraform provider.
+
+resource "cloudflare_dns_record" "_sip__tcp_srv" {
+  zone_id = "zone-1"
+  name    = "_sip._tcp.example.com"
+  type    = "SRV"
+  ttl     = 300
+
+  data = {
+    port     = 5060
+    priority = 10
+    target   = "sip.example.com"
+    weight   = 5
+  }
+}
+
+import {
+  to = cloudflare_dns_record._sip__tcp_srv
+  id = "zone-1/r2"
+}
+
+resource "cloudflare_dns_record" "apex_mx" {
+  zone_id  = "zone-1"
+  name     = "example.com"
+  type     = "MX"
+  content  = "mx1.example.com"
+  ttl      = 3600
+  priority = 10
+}
+
+import {
+  to = cloudflare_dns_record.apex_mx
+  id = "zone-1/r3"
+}
+
+resource "cloudflare_dns_record" "apex_txt" {
+  zone_id = "zone-1"
+  name    = "example.com"
+  type    = "TXT"
+  content = "\"v=spf1 $${x} -all\""
+  ttl     = 1
+}
+
+import {
+  to = cloudflare_dns_record.apex_txt
+  id = "zone-1/r4"
+}
+
+resource "cloudflare_dns_record" "www_a" {
+  zone_id = "zone-1"
+  name    = "www.example.com"
+  type    = "A"
+  content = "192.0.2.1"
+  ttl     = 1
+  proxied = true
+  comment = "says \"hi\""
+  tags    = ["env:prod", "team:web"]
+}
+
+import {
+  to = cloudflare_dns_record.www_a
+  id = "zone-1/r1"
+}
+`
+	if got := Render("zone-1", "example.com", records); got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestResourceNamesAreStable(t *testing.T) {
+	records := []api.DNSRecord{
+		{ID: "b", Type: "A", Name: "www.example.com", Content: "192.0.2.2"},
+		{ID: "a", Type: "A", Name: "WWW.example.com", Content: "192.0.2.1"},
+		{ID: "c", Type: "CNAME", Name: "*.example.com", Content: "example.com"},
+		{ID: "d", Type: "A", Name: "1st.example.com", Content: "192.0.2.3"},
+	}
+	first := Render("zone-1", "example.com", records)
+	records[0], records[1] = records[1], records[0]
+	if again := Render("zone-1", "example.com", records); again != first {
+		t.Errorf("expected the same output whatever the record order:\n%s\n---\n%s", first, again)
+	}
+
+	var got []string
+	for _, line := range strings.Split(first, "\n") {
+		if rest, ok := strings.CutPrefix(line, "  to = cloudflare_dns_record."); ok {
+			got = append(got, rest)
+		}
+	}
+	want := []string{"wildcard_cname", "_1st_a", "www_a_a", "www_a_b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resource names = %q, want %q", got, want)
+	}
+}
+
+func TestResourceNamesSurviveChanges(t *testing.T) {
+	records := []api.DNSRecord{
+		{ID: "023e105f4ecef8ad9ca31a8372d0c353", Type: "A", Name: "www.example.com", Content: "192.0.2.1"},
+		{ID: "372e67954025e0ba6aaa6d586b9e0b59", Type: "A", Name: "www.example.com", Content: "192.0.2.2"},
+		{ID: "9a7806061c88ada191ed06f989cc3dac", Type: "A", Name: "www.example.com", Content: "192.0.2.3"},
+		{ID: "b5e0a1f2c3d4e5f60718293a4b5c6d7e", Type: "MX", Name: "example.com", Content: "mx1.example.com"},
+	}
+	names := func(records []api.DNSRecord) map[string]string {
+		byID := make(map[string]string)
+		var name string
+		for _, line := range strings.Split(Render("zone-1", "example.com", records), "\n") {
+			if rest, ok := strings.CutPrefix(line, "  to = cloudflare_dns_record."); ok {
+				name = rest
+			} else if rest, ok := strings.CutPrefix(line, "  id = \"zone-1/"); ok {
+				byID[strings.TrimSuffix(rest, "\"")] = name
+			}
+		}
+		return byID
+	}
+	first := names(records)
+	if first["372e67954025e0ba6aaa6d586b9e0b59"] != "www_a_372e6795" || first["b5e0a1f2c3d4e5f60718293a4b5c6d7e"] != "apex_mx" {
+		t.Fatalf("resource names = %v", first)
+	}
+
+	// New content reorders the www records, but each keeps its name.
+	changed := slices.Clone(records)
+	changed[0].Content = "192.0.2.9"
+	// Exporting some of the records, such as the marked ones, keeps the
+	// names of those exported.
+	subset := []api.DNSRecord{records[2], records[1]}
+	for _, again := range []map[string]string{names(changed), names(subset)} {
+		for id, name := range again {
+			if name != first[id] {
+				t.Errorf("record %s renamed from %s to %s", id, first[id], name)
+			}
+		}
+	}
+}
@@ -0,0 +1,533 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/validate"
+)
+
+// bulkEdit is a partial change applied to many records. Nil fields are left
+// as they are on each record.
+type bulkEdit struct {
+	content *string
+	ttl     *int
+	proxied *bool
+	comment *string
+}
+
+// empty reports whether the edit changes nothing.
+func (b bulkEdit) empty() bool {
+	return b.content == nil && b.ttl == nil && b.proxied == nil && b.comment == nil
+}
+
+// apply returns the full update parameters for r with the edit's fields
+// replaced. An update replaces the whole record, so the untouched fields are
+// carried over from r.
+func (b bulkEdit) apply(r api.DNSRecord) api.UpdateDNSRecordParams {
+	p := r.Params()
+	if b.content != nil {
+		p.Content = *b.content
+	}
+	if b.ttl != nil {
+		p.TTL = *b.ttl
+	}
+	if b.proxied != nil {
+		p.Proxied = *b.proxied
+	}
+	if b.comment != nil {
+		p.Comment = *b.comment
+	}
+	return p
+}
+
+// patch returns the batch operation that applies the edit to r.
+func (b bulkEdit) patch(r api.DNSRecord) api.BatchPatch {
+	return api.BatchPatch{
+		ID:      r.ID,
+		Type:    r.Type,
+		Content: b.content,
+		TTL:     b.ttl,
+		Proxied: b.proxied,
+		Comment: b.comment,
+	}
+}
+
+// check validates the edit against one record, returning "" if it can be
+// applied. Records with structured data cannot take a new content string.
+func (b bulkEdit) check(r api.DNSRecord) string {
+	p := b.apply(r)
+	if b.content != nil {
+		if len(dataLayouts[r.Type]) > 0 {
+			return fmt.Sprintf("content of %s records cannot be bulk-edited", r.Type)
+		}
+		if msg := validate.Content(r.Type, p.Content); msg != "" {
+			return msg
+		}
+	}
+	if msg := validate.Proxied(r.Type, p.Proxied); msg != "" {
+		return msg
+	}
+	return validate.TTL(p.TTL, p.Proxied)
+}
+
+// bulkField identifies which bulk-edit form field is focused.
+type bulkField int
+
+const (
+	bulkContent bulkField = iota
+	bulkTTL
+	bulkProxied
+	bulkComment
+	bulkSubmit
+)
+
+// bulkStatus is the progress of one record in a bulk edit.
+type bulkStatus int
+
+const (
+	bulkPending bulkStatus = iota
+	bulkRunning
+	bulkUpdated
+	bulkFailed
+)
+
+// bulkItem tracks one selected record through a bulk edit. before is the
+// copy the change was applied to and after the saved result.
+type bulkItem struct {
+	record api.DNSRecord
+	status bulkStatus
+	before api.DNSRecord
+	after  api.DNSRecord
+	err    string
+}
+
+// bulkEditMsg signals that the user wants to edit the selected records.
+type bulkEditMsg struct {
+	records []api.DNSRecord
+}
+
+// bulkBatchMsg carries the records returned by the batch update, or the
+// error that rejected the whole batch and the messages it gave for single
+// records, by record ID.
+type bulkBatchMsg struct {
+	records []api.DNSRecord
+	err     error
+	errs    map[string]string
+}
+
+// bulkDoneMsg signals that the user left the bulk edit. items is nil if the
+// form was cancelled before anything was sent.
+type bulkDoneMsg struct {
+	zoneID   string
+	zoneName string
+	items    []bulkItem
+}
+
+// BulkModel is a form that applies the same partial change to several
+// records, followed by a per-record result list. Records the change is not
+// valid for are reported and skipped; the rest are updated in one batch.
+type BulkModel struct {
+	client   *api.Client
+	zoneID   string
+	zoneName string
+	items    []bulkItem
+
+	contentInput textinput.Model
+	ttlInput     textinput.Model
+	commentInput textinput.Model
+	// proxied is nil to leave proxying unchanged.
+	proxied *bool
+
+	focused bulkField
+	formErr string
+	// batchErr is the error that rejected the whole batch, if any.
+	batchErr string
+	running  bool
+	done     bool
+	spinner  spinner.Model
+	width    int
+	height   int
+}
+
+// NewBulkModel creates a bulk-edit form for records.
+func NewBulkModel(client *api.Client, zoneID, zoneName string, records []api.DNSRecord, width, height int) BulkModel {
+	newInput := func(placeholder string, limit, width int) textinput.Model {
+		in := textinput.New()
+		in.Placeholder = placeholder
+		in.CharLimit = limit
+		in.Width = width
+		return in
+	}
+	items := make([]bulkItem, len(records))
+	for i, r := range records {
+		items[i] = bulkItem{record: r}
+	}
+
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	m := BulkModel{
+		client:       client,
+		zoneID:       zoneID,
+		zoneName:     zoneName,
+		items:        items,
+		contentInput: newInput("unchanged", 2048, 60),
+		ttlInput:     newInput("unchanged (Auto or seconds)", 10, 30),
+		commentInput: newInput("unchanged", 500, 60),
+		spinner:      sp,
+		width:        width,
+		height:       height,
+	}
+	m.updateFocus()
+	return m
+}
+
+// Init returns the text input blink command.
+func (m BulkModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the bulk-edit view.
+func (m BulkModel) Update(msg tea.Msg) (BulkModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.running {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case bulkBatchMsg:
+		m.running = false
+		m.done = true
+		if msg.err != nil {
+			m.batchErr = msg.err.Error()
+		}
+		after := make(map[string]api.DNSRecord, len(msg.records))
+		for _, r := range msg.records {
+			after[r.ID] = r
+		}
+		for i := range m.items {
+			item := &m.items[i]
+			if item.status != bulkRunning {
+				continue
+			}
+			r, ok := after[item.record.ID]
+			switch {
+			case msg.err != nil:
+				item.status = bulkFailed
+				item.err = cmp.Or(msg.errs[item.record.ID], "not applied: batch rejected")
+			case !ok:
+				item.status = bulkFailed
+				item.err = "missing from the batch result"
+			default:
+				item.status = bulkUpdated
+				item.before = item.record
+				item.after = r
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.running {
+			return m, nil
+		}
+		if m.done {
+			switch msg.String() {
+			case "enter", "esc", "q":
+				return m, m.finish()
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "tab", "down":
+			m.focused = (m.focused + 1) % (bulkSubmit + 1)
+			m.updateFocus()
+			return m, nil
+		case "shift+tab", "up":
+			m.focused = (m.focused + bulkSubmit) % (bulkSubmit + 1)
+			m.updateFocus()
+			return m, nil
+		case "esc":
+			zoneID, zoneName := m.zoneID, m.zoneName
+			return m, func() tea.Msg { return bulkDoneMsg{zoneID: zoneID, zoneName: zoneName} }
+		case "enter":
+			if m.focused == bulkSubmit {
+				return m.start()
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focused {
+	case bulkContent:
+		m.contentInput, cmd = m.contentInput.Update(msg)
+	case bulkTTL:
+		m.ttlInput, cmd = m.ttlInput.Update(msg)
+	case bulkProxied:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && (keyMsg.String() == " " || keyMsg.String() == "enter") {
+			switch {
+			case m.proxied == nil:
+				on := true
+				m.proxied = &on
+			case *m.proxied:
+				off := false
+				m.proxied = &off
+			default:
+				m.proxied = nil
+			}
+		}
+	case bulkComment:
+		m.commentInput, cmd = m.commentInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// edit parses the form into a bulkEdit. Blank inputs leave the field
+// unchanged; a comment of "-" clears it. Turning proxying on with the TTL
+// left blank sets the TTL to Auto, which proxied records require.
+func (m BulkModel) edit() (bulkEdit, error) {
+	var b bulkEdit
+	if v := strings.TrimSpace(m.contentInput.Value()); v != "" {
+		b.content = &v
+	}
+	if v := strings.TrimSpace(m.ttlInput.Value()); v != "" {
+		ttl := validate.TTLAuto
+		if !strings.EqualFold(v, "auto") {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return b, errors.New("TTL must be a positive integer or \"Auto\"")
+			}
+			ttl = n
+		}
+		b.ttl = &ttl
+	}
+	b.proxied = m.proxied
+	if b.proxied != nil && *b.proxied && b.ttl == nil {
+		ttl := validate.TTLAuto
+		b.ttl = &ttl
+	}
+	if v := strings.TrimSpace(m.commentInput.Value()); v != "" {
+		if v == "-" {
+			v = ""
+		}
+		b.comment = &v
+	}
+	if b.empty() {
+		return b, errors.New("change at least one field")
+	}
+	return b, nil
+}
+
+// start validates the edit against every record and sends the update for
+// the rest. Records the edit is not valid for are marked failed up front.
+func (m BulkModel) start() (BulkModel, tea.Cmd) {
+	b, err := m.edit()
+	if err != nil {
+		m.formErr = err.Error()
+		return m, nil
+	}
+	m.formErr = ""
+	m.updateFocus()
+	for i := range m.items {
+		if msg := b.check(m.items[i].record); msg != "" {
+			m.items[i].status = bulkFailed
+			m.items[i].err = msg
+		}
+	}
+	m.running = true
+	cmd := m.send()
+	return m, tea.Batch(m.spinner.Tick, cmd)
+}
+
+// send applies the edit to every pending record as one batch, so either all
+// of them change or none does. It finishes the run at once if no record is
+// left to send.
+func (m *BulkModel) send() tea.Cmd {
+	b, _ := m.edit() // already validated
+	var req api.BatchRequest
+	for i, item := range m.items {
+		if item.status != bulkPending {
+			continue
+		}
+		m.items[i].status = bulkRunning
+		req.Patches = append(req.Patches, b.patch(item.record))
+	}
+	if len(req.Patches) == 0 {
+		m.running = false
+		m.done = true
+		return nil
+	}
+
+	client := m.client
+	zoneID := m.zoneID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		result, err := client.BatchDNSRecords(ctx, zoneID, req)
+		errs := make(map[string]string)
+		for op, text := range api.BatchErrors(err) {
+			if op.List == "patches" && op.Index < len(req.Patches) {
+				errs[req.Patches[op.Index].ID] = text
+			}
+		}
+		return bulkBatchMsg{records: result.Patches, err: err, errs: errs}
+	}
+}
+
+// finish reports the results so the root model can journal them.
+func (m BulkModel) finish() tea.Cmd {
+	zoneID, zoneName := m.zoneID, m.zoneName
+	items := append([]bulkItem{}, m.items...)
+	return func() tea.Msg { return bulkDoneMsg{zoneID: zoneID, zoneName: zoneName, items: items} }
+}
+
+// counts returns how many records were updated and how many failed.
+func (m BulkModel) counts() (updated, failed int) {
+	for _, item := range m.items {
+		switch item.status {
+		case bulkUpdated:
+			updated++
+		case bulkFailed:
+			failed++
+		}
+	}
+	return updated, failed
+}
+
+// updateFocus sets the focused state on each text input.
+func (m *BulkModel) updateFocus() {
+	m.contentInput.Blur()
+	m.ttlInput.Blur()
+	m.commentInput.Blur()
+	if m.running || m.done {
+		return
+	}
+	switch m.focused {
+	case bulkContent:
+		m.contentInput.Focus()
+	case bulkTTL:
+		m.ttlInput.Focus()
+	case bulkComment:
+		m.commentInput.Focus()
+	}
+}
+
+// View renders the form and the per-record progress list.
+func (m BulkModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(11).Padding(0, 1, 0, 2)
+	focusedLabelStyle := labelStyle.Foreground(lipgloss.Color("205"))
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 0, 0, 2)
+	helpStyle := lipgloss.NewStyle().Faint(true).Padding(1, 0, 0, 2)
+
+	title := titleStyle.Render(fmt.Sprintf(" Bulk Edit %d Records ", len(m.items)))
+	sections := []string{headerStyle.Render(fmt.Sprintf("%s  %s", title, sanitize(m.zoneName)))}
+
+	label := func(f bulkField, text string) string {
+		if f == m.focused && !m.running && !m.done {
+			return focusedLabelStyle.Render(text)
+		}
+		return labelStyle.Render(text)
+	}
+	proxied := "[-] Unchanged"
+	if m.proxied != nil && *m.proxied {
+		proxied = "[x] Yes"
+	} else if m.proxied != nil {
+		proxied = "[ ] No"
+	}
+	submit := "[ Apply ]"
+	if m.focused == bulkSubmit && !m.running && !m.done {
+		submit = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Background(lipgloss.Color("57")).
+			Render(submit)
+	}
+	sections = append(sections,
+		label(bulkContent, "Content")+m.contentInput.View(),
+		label(bulkTTL, "TTL")+m.ttlInput.View(),
+		label(bulkProxied, "Proxied")+" "+proxied,
+		label(bulkComment, "Comment")+m.commentInput.View(),
+		"",
+		rowStyle.Bold(true).Render(submit),
+	)
+	if m.formErr != "" {
+		sections = append(sections, errorStyle.Render("! "+m.formErr))
+	}
+
+	sections = append(sections, "")
+	for _, item := range m.items {
+		r := item.record
+		var mark string
+		switch item.status {
+		case bulkPending:
+			mark = lipgloss.NewStyle().Faint(true).Render("·")
+		case bulkRunning:
+			mark = m.spinner.View()
+		case bulkUpdated:
+			mark = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓")
+		case bulkFailed:
+			mark = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("✗")
+		}
+		line := fmt.Sprintf("%-5s %s  %s", r.Type, r.Name, r.Content)
+		if item.err != "" {
+			line += "  " + item.err
+		}
+		sections = append(sections, rowStyle.Render(mark+" "+truncate(sanitize(line), m.width-6)))
+	}
+
+	updated, failed := m.counts()
+	switch {
+	case m.running:
+		sections = append(sections, rowStyle.Render(fmt.Sprintf("\nSending %d of %d records…", len(m.items)-failed, len(m.items))))
+	case m.done:
+		summary := fmt.Sprintf("\n%d updated, %d failed", updated, failed)
+		style := rowStyle.Foreground(lipgloss.Color("42"))
+		if failed > 0 {
+			style = rowStyle.Foreground(lipgloss.Color("196"))
+		}
+		sections = append(sections, style.Bold(true).Render(summary))
+		if m.batchErr != "" {
+			sections = append(sections, errorStyle.Width(max(m.width-4, 40)).Render("Error: "+m.batchErr))
+		}
+	}
+
+	helpText := "Tab/Shift+Tab: navigate | Space: cycle proxied | Enter: apply | Esc: cancel | blank fields are left unchanged, \"-\" clears the comment"
+	if m.running {
+		helpText = "Applying changes…"
+	} else if m.done {
+		helpText = "Enter/Esc: back to records"
+	}
+	sections = append(sections, helpStyle.Render(truncate(helpText, m.width-2)))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// Running returns whether updates are being sent.
+func (m BulkModel) Running() bool {
+	return m.running
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/table"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// recordColumn describes one column of the records table. Columns with a
+// share split the space left over by the fixed-width ones in proportion to
+// it, but never shrink below min.
+type recordColumn struct {
+	title string
+	width int
+	share int
+	min   int
+	value func(api.DNSRecord) string
+}
+
+// recordColumns lists the records table columns in display order. The order
+// matches sortColumn.
+var recordColumns = []recordColumn{
+	{title: "Type", width: 8, value: func(r api.DNSRecord) string { return sanitize(r.Type) }},
+	{title: "Name", share: 2, min: 12, value: func(r api.DNSRecord) string { return sanitize(r.Name) }},
+	{title: "Content", share: 3, min: 12, value: func(r api.DNSRecord) string { return sanitize(r.Content) }},
+	{title: "TTL", width: 6, value: func(r api.DNSRecord) string {
+		if r.TTL == 1 {
+			return "Auto"
+		}
+		return strconv.Itoa(r.TTL)
+	}},
+	{title: "Proxied", width: 7, value: func(r api.DNSRecord) string {
+		if r.Proxied {
+			return "Yes"
+		}
+		return "No"
+	}},
+	{title: "Modified", width: 16, value: func(r api.DNSRecord) string {
+		if r.ModifiedOn.IsZero() {
+			return ""
+		}
+		return r.ModifiedOn.Local().Format(timestampLayout)
+	}},
+}
+
+// cellPadding is the horizontal padding the table styles add to every cell.
+const cellPadding = 2
+
+// Detail pane sizing: the pane is shown beside the table only when the
+// terminal is at least detailPaneMinWidth columns wide.
+const (
+	detailPaneWidth    = 50
+	detailPaneMinWidth = 140
+)
+
+// layoutColumns sizes the visible columns to fill width. Hidden columns are
+// skipped; the returned indices map table columns back to recordColumns.
+func layoutColumns(width int, hidden map[string]bool) ([]table.Column, []int) {
+	var idx []int
+	fixed, shares := 0, 0
+	for i, c := range recordColumns {
+		if hidden[c.title] {
+			continue
+		}
+		idx = append(idx, i)
+		fixed += c.width + cellPadding
+		if c.share > 0 {
+			fixed += c.min
+			shares += c.share
+		}
+	}
+
+	spare := width - fixed
+	if spare < 0 {
+		spare = 0
+	}
+	columns := make([]table.Column, len(idx))
+	for n, i := range idx {
+		c := recordColumns[i]
+		w := c.width
+		if c.share > 0 {
+			extra := spare * c.share / shares
+			shares -= c.share
+			spare -= extra
+			w = c.min + extra
+		}
+		columns[n] = table.Column{Title: c.title, Width: w}
+	}
+	return columns, idx
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/validate"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// conflict describes an existing zone record that clashes with a pending
+// change. Blocking conflicts would be rejected by Cloudflare or break
+// resolution outright; the others are suspicious and need confirmation.
+type conflict struct {
+	record   api.DNSRecord
+	reason   string
+	blocking bool
+}
+
+// findConflicts checks params, the pending create or update of the record with
+// the given ID (empty when creating), against the other records of the zone.
+func findConflicts(zoneName, recordID string, params api.UpdateDNSRecordParams, records []api.DNSRecord) []conflict {
+	name := api.QualifyName(params.Name, zoneName)
+	rdata := zonefile.RData(params.Type, params.Content, params.Priority, params.Data)
+
+	var conflicts []conflict
+	for _, r := range records {
+		if r.ID == recordID || !strings.EqualFold(api.QualifyName(r.Name, zoneName), name) {
+			continue
+		}
+		switch {
+		case params.Type == "CNAME":
+			conflicts = append(conflicts, conflict{record: r, blocking: true,
+				reason: "A CNAME cannot share its name with any other record"})
+		case r.Type == "CNAME":
+			conflicts = append(conflicts, conflict{record: r, blocking: true,
+				reason: "This name already holds a CNAME"})
+		case r.Type == params.Type && zonefile.RData(r.Type, r.Content, r.Priority, r.Data) == rdata:
+			conflicts = append(conflicts, conflict{record: r, blocking: true,
+				reason: "An identical record already exists"})
+		case r.Type == params.Type && validate.Proxiable(r.Type) && r.Proxied != params.Proxied:
+			conflicts = append(conflicts, conflict{record: r,
+				reason: "Records at this name disagree on whether they are proxied"})
+		case !strings.EqualFold(name, api.QualifyName(zoneName, zoneName)) && (params.Type == "NS") != (r.Type == "NS"):
+			conflicts = append(conflicts, conflict{record: r,
+				reason: "NS records delegate this name; other records here are shadowed"})
+		}
+	}
+	return conflicts
+}
+
+// hasBlockingConflict reports whether any conflict must stop the save.
+func hasBlockingConflict(conflicts []conflict) bool {
+	for _, c := range conflicts {
+		if c.blocking {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// deleteRecordMsg signals that the user wants to delete a DNS record.
+type deleteRecordMsg struct {
+	record api.DNSRecord
+}
+
+// cancelDeleteMsg signals that the user dismissed the delete confirmation.
+type cancelDeleteMsg struct{}
+
+// deleteResultMsg carries the result of the API delete call.
+type deleteResultMsg struct {
+	err error
+}
+
+// deleteDoneMsg signals that a record was deleted successfully.
+type deleteDoneMsg struct {
+	record api.DNSRecord
+}
+
+// DeleteModel is a confirmation modal shown before a record is deleted.
+// The user must type the record name exactly before the delete is sent.
+type DeleteModel struct {
+	client   *api.Client
+	zoneID   string
+	zoneName string
+	record   api.DNSRecord
+
+	confirmInput textinput.Model
+	mismatch     bool
+	deleting     bool
+	deleteErr    error
+	spinner      spinner.Model
+	width        int
+	height       int
+}
+
+// NewDeleteModel creates a confirmation modal for deleting the given record.
+func NewDeleteModel(client *api.Client, zoneID, zoneName string, record api.DNSRecord, width, height int) DeleteModel {
+	confirmInput := textinput.New()
+	confirmInput.Placeholder = sanitize(record.Name)
+	confirmInput.CharLimit = 253
+	confirmInput.Width = 50
+	confirmInput.Focus()
+
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	return DeleteModel{
+		client:       client,
+		zoneID:       zoneID,
+		zoneName:     zoneName,
+		record:       record,
+		confirmInput: confirmInput,
+		spinner:      sp,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init returns the text input blink command.
+func (m DeleteModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the delete confirmation modal.
+func (m DeleteModel) Update(msg tea.Msg) (DeleteModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case deleteResultMsg:
+		m.deleting = false
+		if msg.err != nil {
+			m.deleteErr = msg.err
+			return m, nil
+		}
+		record := m.record
+		return m, func() tea.Msg { return deleteDoneMsg{record: record} }
+
+	case spinner.TickMsg:
+		if m.deleting {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		// Block all key input while the delete is in flight.
+		if m.deleting {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return cancelDeleteMsg{} }
+		case "enter":
+			if !m.Confirmed() {
+				m.mismatch = true
+				return m, nil
+			}
+			m.mismatch = false
+			m.deleting = true
+			m.deleteErr = nil
+			return m, tea.Batch(m.spinner.Tick, m.deleteCmd())
+		}
+	}
+
+	var cmd tea.Cmd
+	m.confirmInput, cmd = m.confirmInput.Update(msg)
+	return m, cmd
+}
+
+// deleteCmd fires the API delete call and returns a deleteResultMsg.
+func (m DeleteModel) deleteCmd() tea.Cmd {
+	client := m.client
+	zoneID := m.zoneID
+	recordID := m.record.ID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return deleteResultMsg{err: client.DeleteDNSRecord(ctx, zoneID, recordID)}
+	}
+}
+
+// View renders the confirmation modal centred on the screen.
+func (m DeleteModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("160")).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Width(10)
+
+	valueStyle := lipgloss.NewStyle().
+		Width(60)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196"))
+
+	apiErrorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Faint(true)
+
+	ttl := strconv.Itoa(m.record.TTL)
+	if m.record.TTL == 1 {
+		ttl = "Auto"
+	}
+	row := func(label, value string) string {
+		return lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render(label),
+			valueStyle.Render(value),
+		)
+	}
+
+	sections := []string{
+		titleStyle.Render(fmt.Sprintf(" Delete %s Record ", sanitize(m.record.Type))),
+		"",
+		row("Zone", sanitize(m.zoneName)),
+		row("Type", sanitize(m.record.Type)),
+		row("Name", sanitize(m.record.Name)),
+		row("Content", sanitize(m.record.Content)),
+		row("TTL", ttl),
+		"",
+		"This cannot be undone. Type the record name to confirm:",
+		m.confirmInput.View(),
+	}
+
+	if m.mismatch {
+		sections = append(sections, errorStyle.Render("! Name does not match"))
+	}
+	if m.deleting {
+		sections = append(sections, "", m.spinner.View()+" Deleting…")
+	}
+	if m.deleteErr != nil {
+		sections = append(sections, "", apiErrorStyle.Render("Error: "+m.deleteErr.Error()))
+	}
+	sections = append(sections, "", helpStyle.Render("Enter: delete | Esc: cancel"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("160")).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// Confirmed reports whether the typed name matches the record name.
+func (m DeleteModel) Confirmed() bool {
+	return m.confirmInput.Value() == m.record.Name
+}
+
+// Deleting returns whether a delete is in progress.
+func (m DeleteModel) Deleting() bool {
+	return m.deleting
+}
+
+// DeleteErr returns the last API delete error, if any.
+func (m DeleteModel) DeleteErr() error {
+	return m.deleteErr
+}
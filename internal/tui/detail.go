@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// showDetailMsg signals that the user wants to inspect a record.
+type showDetailMsg struct {
+	record api.DNSRecord
+}
+
+// closeDetailMsg signals that the user left the detail view.
+type closeDetailMsg struct{}
+
+// DetailModel is a read-only, scrollable view of every field of one record.
+// It is available in read-only mode.
+type DetailModel struct {
+	zoneName string
+	record   api.DNSRecord
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewDetailModel creates a detail view for record.
+func NewDetailModel(zoneName string, record api.DNSRecord, width, height int) DetailModel {
+	m := DetailModel{zoneName: zoneName, record: record, width: width, height: height}
+	m.viewport = viewport.New(m.contentWidth(), m.viewportHeight())
+	m.viewport.SetContent(m.renderFields())
+	return m
+}
+
+// Init is a no-op; the record is already loaded.
+func (m DetailModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles scrolling and leaving the view.
+func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = m.contentWidth()
+		m.viewport.Height = m.viewportHeight()
+		m.viewport.SetContent(m.renderFields())
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "i":
+			return m, func() tea.Msg { return closeDetailMsg{} }
+		}
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// contentWidth returns the usable width inside the left margin.
+func (m DetailModel) contentWidth() int {
+	w := m.width
+	if w == 0 {
+		w = 80
+	}
+	return w - 2
+}
+
+// viewportHeight leaves room for the header and help lines.
+func (m DetailModel) viewportHeight() int {
+	h := m.height
+	if h == 0 {
+		h = 24
+	}
+	return max(h-6, 3)
+}
+
+// renderFields lays out every record field as a label and a wrapped value.
+// TXT content is additionally listed as its individual character-strings.
+func (m DetailModel) renderFields() string {
+	const labelWidth = 12
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(labelWidth)
+	faint := lipgloss.NewStyle().Faint(true)
+	valueWidth := max(m.contentWidth()-labelWidth, 20)
+	valueStyle := lipgloss.NewStyle().Width(valueWidth)
+
+	r := m.record
+	var lines []string
+	add := func(label, value string) {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render(label),
+			valueStyle.Render(sanitize(value)),
+		))
+	}
+	yesNo := func(b bool) string {
+		if b {
+			return "Yes"
+		}
+		return "No"
+	}
+
+	ttl := strconv.Itoa(r.TTL)
+	if r.TTL == 1 {
+		ttl = "Auto"
+	}
+	add("ID", r.ID)
+	add("Type", r.Type)
+	add("Name", r.Name)
+	add("Content", r.Content)
+	if r.Type == "TXT" {
+		chunks := zonefile.TXTStrings(r.Content)
+		add("Strings", fmt.Sprintf("%d (%d bytes total)", len(chunks), len(r.Content)))
+		for i, c := range chunks {
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+				labelStyle.Render(""),
+				valueStyle.Render(faint.Render(fmt.Sprintf("#%d, %d bytes", i+1, len(c)))+"\n"+sanitize(zonefile.Quote(c))),
+			))
+		}
+	}
+	if api.UsesPriority(r.Type) {
+		add("Priority", strconv.Itoa(r.Priority))
+	}
+	if len(r.Data) > 0 {
+		keys := make([]string, 0, len(r.Data))
+		for k := range r.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			label := ""
+			if i == 0 {
+				label = "Data"
+			}
+			add(label, k+" = "+zonefile.DataValue(r.Data, k))
+		}
+	}
+	add("TTL", ttl)
+	add("Proxied", yesNo(r.Proxied))
+	add("Proxiable", yesNo(r.Proxiable))
+	add("Comment", r.Comment)
+	add("Tags", strings.Join(r.Tags, ", "))
+	if !r.CreatedOn.IsZero() {
+		add("Created", r.CreatedOn.Local().Format(timestampLayout))
+	}
+	if !r.ModifiedOn.IsZero() {
+		add("Modified", r.ModifiedOn.Local().Format(timestampLayout))
+	}
+	add("Zone line", zonefile.Line(r.Name, r.TTL, r.Type, zonefile.RData(r.Type, r.Content, r.Priority, r.Data)))
+	return strings.Join(lines, "\n")
+}
+
+// View renders the detail view.
+func (m DetailModel) View() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 0, 1, 2).
+		Render(fmt.Sprintf("%s Record - %s", sanitize(m.record.Type), sanitize(m.zoneName)))
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Padding(1, 0, 0, 2).
+		Render(fmt.Sprintf("↑/↓/PgUp/PgDn: scroll (%d%%) | q/Esc: back | Ctrl+C: quit", int(m.viewport.ScrollPercent()*100)))
+	body := lipgloss.NewStyle().Padding(0, 0, 0, 2).Render(m.viewport.View())
+	return "\n" + header + "\n" + body + "\n" + help
+}
@@ -3,30 +3,48 @@ package tui
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/validate"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
 )
 
 // editField identifies which form field is focused.
 type editField int
 
 const (
-	fieldName editField = iota
+	fieldType editField = iota
+	fieldName
+	fieldPriority
 	fieldContent
 	fieldTTL
 	fieldProxied
+	fieldComment
+	fieldTags
 	fieldSubmit
+
+	// fieldData is the first structured data input; input i of the current
+	// layout is fieldData + i.
+	fieldData
 )
 
-const editFieldCount = 5
+// createRecordTypes lists the record types offered when creating a record,
+// in the order the Type selector cycles through them.
+var createRecordTypes = []string{
+	"A", "AAAA", "CNAME", "TXT", "NS", "PTR", "MX",
+	"SRV", "CAA", "HTTPS", "SVCB", "TLSA", "SSHFP", "NAPTR", "LOC", "URI",
+}
 
 // cancelEditMsg signals that the user cancelled editing.
 type cancelEditMsg struct{}
@@ -45,21 +63,65 @@ type saveResultMsg struct {
 }
 
 // editDoneMsg signals that a record was saved successfully.
+// created is true when the record did not exist before the save; otherwise
+// before is the record as it was on Cloudflare prior to the save.
 type editDoneMsg struct {
-	record api.DNSRecord
+	record  api.DNSRecord
+	before  api.DNSRecord
+	created bool
 }
 
-// EditModel represents a form for editing a single DNS record.
+// EditModel represents a form for editing a single DNS record, or for
+// creating a new one when opened with NewCreateModel.
 type EditModel struct {
 	client   *api.Client
 	zoneID   string
 	zoneName string
 	record   api.DNSRecord
 
-	nameInput    textinput.Model
-	contentInput textinput.Model
-	ttlInput     textinput.Model
+	// creating is true when the form creates a new record. In that mode the
+	// Type field is selectable and typeIndex points into createRecordTypes.
+	creating  bool
+	typeIndex int
+
+	nameInput     textinput.Model
+	priorityInput textinput.Model
+	contentInput  textinput.Model
+	ttlInput      textinput.Model
+
+	// txtInput replaces contentInput for TXT records, one string per line.
+	txtInput textarea.Model
+
 	proxied      bool
+	commentInput textinput.Model
+	tagsInput    textinput.Model
+
+	// layout is the structured form for the record type, if it has one; it
+	// replaces the Content input. dataInputs holds one input per entry.
+	layout     []dataField
+	dataInputs []textinput.Model
+
+	// zoneRecords are the zone's records as loaded by the records view. A
+	// pending change is checked against them before saving; conflicts holds
+	// the result, and confirmed the params the user chose to save anyway.
+	zoneRecords []api.DNSRecord
+	conflicts   []conflict
+	confirmed   *api.UpdateDNSRecordParams
+
+	// reviewing is set while the before/after diff of pending is shown.
+	reviewing bool
+
+	// staging sends the reviewed change to the pending-changes list instead
+	// of saving it.
+	staging bool
+
+	// stale is set when the record changed on Cloudflare after the form was
+	// opened. theirs is the current copy and pending the held-back submission,
+	// also used by the review screen; mergeNote reports the outcome of a merge.
+	stale     bool
+	theirs    api.DNSRecord
+	pending   submitEditMsg
+	mergeNote string
 
 	focused editField
 	errors  map[editField]string
@@ -79,6 +141,12 @@ func NewEditModel(client *api.Client, zoneID, zoneName string, record api.DNSRec
 	nameInput.Width = 60
 	nameInput.Focus()
 
+	priorityInput := textinput.New()
+	priorityInput.Placeholder = "0-65535"
+	priorityInput.SetValue(strconv.Itoa(record.Priority))
+	priorityInput.CharLimit = 5
+	priorityInput.Width = 20
+
 	contentInput := textinput.New()
 	contentInput.Placeholder = "Record content"
 	contentInput.SetValue(record.Content)
@@ -95,25 +163,53 @@ func NewEditModel(client *api.Client, zoneID, zoneName string, record api.DNSRec
 	ttlInput.CharLimit = 10
 	ttlInput.Width = 20
 
+	commentInput := textinput.New()
+	commentInput.Placeholder = "Optional note (not served in DNS)"
+	commentInput.SetValue(record.Comment)
+	commentInput.CharLimit = 500
+	commentInput.Width = 60
+
+	tagsInput := textinput.New()
+	tagsInput.Placeholder = "name:value, comma separated"
+	tagsInput.SetValue(strings.Join(record.Tags, ", "))
+	tagsInput.CharLimit = 1024
+	tagsInput.Width = 60
+
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
-	return EditModel{
-		client:       client,
-		zoneID:       zoneID,
-		zoneName:     zoneName,
-		record:       record,
-		nameInput:    nameInput,
-		contentInput: contentInput,
-		ttlInput:     ttlInput,
-		proxied:      record.Proxied,
-		focused:      fieldName,
-		errors:       make(map[editField]string),
-		spinner:      sp,
-		width:        width,
-		height:       height,
+	m := EditModel{
+		client:        client,
+		zoneID:        zoneID,
+		zoneName:      zoneName,
+		record:        record,
+		nameInput:     nameInput,
+		priorityInput: priorityInput,
+		contentInput:  contentInput,
+		ttlInput:      ttlInput,
+		txtInput:      newTXTEditor(record.Content),
+		proxied:       record.Proxied,
+		commentInput:  commentInput,
+		tagsInput:     tagsInput,
+		focused:       fieldName,
+		errors:        make(map[editField]string),
+		spinner:       sp,
+		width:         width,
+		height:        height,
 	}
+	m.setLayout(record.Type, record)
+	return m
+}
+
+// NewCreateModel creates an EditModel for adding a new record to the zone.
+// The form starts on the Type selector with an empty name and content.
+func NewCreateModel(client *api.Client, zoneID, zoneName string, width, height int) EditModel {
+	m := NewEditModel(client, zoneID, zoneName, api.DNSRecord{Type: createRecordTypes[0], TTL: 1}, width, height)
+	m.creating = true
+	m.focused = fieldType
+	m.updateFocus()
+	return m
 }
 
 // Init returns the text input blink command.
@@ -130,9 +226,10 @@ func (m EditModel) Update(msg tea.Msg) (EditModel, tea.Cmd) {
 		return m, nil
 
 	case submitEditMsg:
-		m.saving = true
+		m.reviewing = true
+		m.pending = msg
 		m.saveErr = nil
-		return m, tea.Batch(m.spinner.Tick, m.saveCmd(msg))
+		return m, nil
 
 	case saveResultMsg:
 		m.saving = false
@@ -141,7 +238,19 @@ func (m EditModel) Update(msg tea.Msg) (EditModel, tea.Cmd) {
 			return m, nil
 		}
 		record := msg.record
-		return m, func() tea.Msg { return editDoneMsg{record: record} }
+		created := m.creating
+		before := m.record
+		if created {
+			before = api.DNSRecord{}
+		}
+		return m, func() tea.Msg { return editDoneMsg{record: record, before: before, created: created} }
+
+	case staleRecordMsg:
+		m.saving = false
+		m.stale = true
+		m.theirs = msg.current
+		m.pending = msg.pending
+		return m, nil
 
 	case spinner.TickMsg:
 		if m.saving {
@@ -156,15 +265,19 @@ func (m EditModel) Update(msg tea.Msg) (EditModel, tea.Cmd) {
 		if m.saving {
 			return m, nil
 		}
+		if m.stale {
+			return m.updateStale(msg)
+		}
+		if m.reviewing {
+			return m.updateReview(msg)
+		}
 
 		switch msg.String() {
 		case "tab":
-			m.focused = (m.focused + 1) % editFieldCount
-			m.updateFocus()
+			m.moveFocus(1)
 			return m, nil
 		case "shift+tab":
-			m.focused = (m.focused - 1 + editFieldCount) % editFieldCount
-			m.updateFocus()
+			m.moveFocus(-1)
 			return m, nil
 		case "esc":
 			return m, func() tea.Msg { return cancelEditMsg{} }
@@ -176,6 +289,15 @@ func (m EditModel) Update(msg tea.Msg) (EditModel, tea.Cmd) {
 					return m, nil
 				}
 				m.errors = make(map[editField]string)
+				params := m.params()
+				m.conflicts = findConflicts(m.zoneName, m.record.ID, params, m.zoneRecords)
+				if hasBlockingConflict(m.conflicts) {
+					return m, nil
+				}
+				if len(m.conflicts) > 0 && (m.confirmed == nil || !reflect.DeepEqual(*m.confirmed, params)) {
+					m.confirmed = &params
+					return m, nil
+				}
 				return m, m.submitCmd()
 			}
 		}
@@ -184,10 +306,32 @@ func (m EditModel) Update(msg tea.Msg) (EditModel, tea.Cmd) {
 	// Delegate to the focused text input.
 	var cmd tea.Cmd
 	switch m.focused {
+	case fieldType:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			previous := m.recordType()
+			switch keyMsg.String() {
+			case "right", "l", " ":
+				m.typeIndex = (m.typeIndex + 1) % len(createRecordTypes)
+			case "left", "h":
+				m.typeIndex = (m.typeIndex - 1 + len(createRecordTypes)) % len(createRecordTypes)
+			}
+			// Only a new type gets a new layout, so other keys keep the
+			// structured values already entered.
+			if m.recordType() != previous {
+				m.carryContent(previous)
+				m.setLayout(m.recordType(), api.DNSRecord{})
+			}
+		}
 	case fieldName:
 		m.nameInput, cmd = m.nameInput.Update(msg)
+	case fieldPriority:
+		m.priorityInput, cmd = m.priorityInput.Update(msg)
 	case fieldContent:
-		m.contentInput, cmd = m.contentInput.Update(msg)
+		if m.isTXT() {
+			m.txtInput, cmd = m.txtInput.Update(msg)
+		} else {
+			m.contentInput, cmd = m.contentInput.Update(msg)
+		}
 	case fieldTTL:
 		m.ttlInput, cmd = m.ttlInput.Update(msg)
 	case fieldProxied:
@@ -196,6 +340,14 @@ func (m EditModel) Update(msg tea.Msg) (EditModel, tea.Cmd) {
 				m.proxied = !m.proxied
 			}
 		}
+	case fieldComment:
+		m.commentInput, cmd = m.commentInput.Update(msg)
+	case fieldTags:
+		m.tagsInput, cmd = m.tagsInput.Update(msg)
+	default:
+		if i, ok := m.dataIndex(m.focused); ok {
+			m.dataInputs[i], cmd = m.dataInputs[i].Update(msg)
+		}
 	}
 	return m, cmd
 }
@@ -206,75 +358,399 @@ func (m EditModel) validate() map[editField]string {
 	if strings.TrimSpace(m.nameInput.Value()) == "" {
 		errs[fieldName] = "Name must be non-empty"
 	}
-	if strings.TrimSpace(m.contentInput.Value()) == "" {
-		errs[fieldContent] = "Content must be non-empty"
+	if len(m.layout) == 0 {
+		content := m.contentValue()
+		if content == "" {
+			errs[fieldContent] = "Content must be non-empty"
+		} else if msg := validate.Content(m.recordType(), content); msg != "" {
+			errs[fieldContent] = msg
+		}
+	}
+	for i, f := range m.layout {
+		v := strings.TrimSpace(m.dataInputs[i].Value())
+		if v == "" {
+			errs[fieldData+editField(i)] = f.label + " must be non-empty"
+			continue
+		}
+		if _, err := strconv.ParseFloat(v, 64); f.numeric && err != nil {
+			errs[fieldData+editField(i)] = f.label + " must be a number"
+		}
+	}
+	if api.UsesPriority(m.recordType()) {
+		n, err := strconv.Atoi(strings.TrimSpace(m.priorityInput.Value()))
+		if err != nil || n < 0 || n > 65535 {
+			errs[fieldPriority] = "Priority must be an integer between 0 and 65535"
+		}
 	}
 	ttl := strings.TrimSpace(m.ttlInput.Value())
 	if strings.EqualFold(ttl, "auto") {
-		// valid — maps to TTL 1
-	} else {
-		n, err := strconv.Atoi(ttl)
-		if err != nil || n <= 0 {
-			errs[fieldTTL] = "TTL must be a positive integer or \"Auto\""
-		}
+		ttl = "1"
+	}
+	if n, err := strconv.Atoi(ttl); err != nil || n <= 0 {
+		errs[fieldTTL] = "TTL must be a positive integer or \"Auto\""
+	} else if msg := validate.TTL(n, m.proxied); msg != "" {
+		errs[fieldTTL] = msg
+	}
+	if msg := validate.Proxied(m.recordType(), m.proxied); msg != "" {
+		errs[fieldProxied] = msg
 	}
 	return errs
 }
 
 // submitCmd builds a command that emits a submitEditMsg with the current form values.
 func (m EditModel) submitCmd() tea.Cmd {
+	zoneID := m.zoneID
+	recordID := m.record.ID
+	params := m.params()
+	return func() tea.Msg {
+		return submitEditMsg{zoneID: zoneID, recordID: recordID, params: params}
+	}
+}
+
+// params builds the API parameters from the current, validated form values.
+func (m EditModel) params() api.UpdateDNSRecordParams {
 	ttl := 1
 	ttlStr := strings.TrimSpace(m.ttlInput.Value())
 	if !strings.EqualFold(ttlStr, "auto") {
 		ttl, _ = strconv.Atoi(ttlStr) // already validated
 	}
-	return func() tea.Msg {
-		return submitEditMsg{
-			zoneID:   m.zoneID,
-			recordID: m.record.ID,
-			params: api.UpdateDNSRecordParams{
-				Name:    strings.TrimSpace(m.nameInput.Value()),
-				Type:    m.record.Type,
-				Content: strings.TrimSpace(m.contentInput.Value()),
-				TTL:     ttl,
-				Proxied: m.proxied,
-			},
-		}
+	priority := 0
+	if api.UsesPriority(m.recordType()) {
+		priority, _ = strconv.Atoi(strings.TrimSpace(m.priorityInput.Value())) // already validated
+	}
+	name := strings.TrimSpace(m.nameInput.Value())
+	content := m.contentValue()
+	data := m.record.Data
+	if m.recordType() != m.record.Type {
+		data = nil
+	}
+	if len(m.layout) > 0 {
+		data = m.layoutData()
+		name = m.layoutName(name)
+		content = zonefile.RData(m.recordType(), content, priority, data)
+	}
+	return api.UpdateDNSRecordParams{
+		Name:     name,
+		Type:     m.recordType(),
+		Content:  content,
+		TTL:      ttl,
+		Proxied:  m.proxied,
+		Priority: priority,
+		Data:     data,
+		Comment:  strings.TrimSpace(m.commentInput.Value()),
+		Tags:     parseTags(m.tagsInput.Value()),
 	}
 }
 
-// saveCmd fires the API update call and returns a saveResultMsg.
+// saveCmd fires the API create or update call and returns a saveResultMsg.
+// A submitEditMsg without a record ID creates a new record. Before an update
+// the record is re-read, and a staleRecordMsg is returned instead if it no
+// longer matches the copy the form was opened with.
 func (m EditModel) saveCmd(msg submitEditMsg) tea.Cmd {
 	client := m.client
+	original := m.record
 	zoneID := msg.zoneID
 	recordID := msg.recordID
 	params := msg.params
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
+		if recordID == "" {
+			record, err := client.CreateDNSRecord(ctx, zoneID, params)
+			return saveResultMsg{record: record, err: err}
+		}
+		current, err := client.GetDNSRecord(ctx, zoneID, recordID)
+		if err != nil {
+			return saveResultMsg{err: err}
+		}
+		if recordChanged(original, current) {
+			return staleRecordMsg{current: current, pending: msg}
+		}
 		record, err := client.UpdateDNSRecord(ctx, zoneID, recordID, params)
 		return saveResultMsg{record: record, err: err}
 	}
 }
 
+// updateStale handles keys on the three-way view shown when the record changed
+// underneath the form. Overwriting re-bases on the current copy and saves the
+// held-back values; merging refills the form for review.
+func (m EditModel) updateStale(msg tea.KeyMsg) (EditModel, tea.Cmd) {
+	switch msg.String() {
+	case "o":
+		m.stale = false
+		m.record = m.theirs
+		m.saving = true
+		return m, tea.Batch(m.spinner.Tick, m.saveCmd(m.pending))
+	case "m":
+		merged, clashes := mergeRecords(m.record, m.theirs, paramsRecord(m.record.ID, m.pending.params))
+		n := NewEditModel(m.client, m.zoneID, m.zoneName, merged, m.width, m.height)
+		n.record = m.theirs
+		n.zoneRecords = m.zoneRecords
+		n.focused = fieldSubmit
+		n.updateFocus()
+		n.mergeNote = "Merged with the current Cloudflare copy; review and save."
+		if len(clashes) > 0 {
+			n.mergeNote = "Merged; both sides changed " + strings.Join(clashes, ", ") + " (kept yours). Review and save."
+		}
+		return n, nil
+	case "a", "esc":
+		m.stale = false
+		return m, nil
+	}
+	return m, nil
+}
+
+// isTXT reports whether the content is edited in the multi-line TXT editor.
+func (m EditModel) isTXT() bool {
+	return m.recordType() == "TXT"
+}
+
+// contentValue returns the content the form will submit. TXT editor text is
+// split into strings and quoted as needed.
+func (m EditModel) contentValue() string {
+	if m.isTXT() {
+		return encodeTXT(txtEditorStrings(m.txtInput.Value()))
+	}
+	return strings.TrimSpace(m.contentInput.Value())
+}
+
+// carryContent moves the content typed so far between the single-line input
+// and the TXT editor when the Type selector crosses between them.
+func (m *EditModel) carryContent(previous string) {
+	switch {
+	case previous == "TXT" && !m.isTXT():
+		m.contentInput.SetValue(encodeTXT(txtEditorStrings(m.txtInput.Value())))
+	case previous != "TXT" && m.isTXT():
+		m.txtInput.SetValue(txtEditorText(strings.TrimSpace(m.contentInput.Value())))
+	}
+}
+
+// recordType returns the record type the form will submit.
+func (m EditModel) recordType() string {
+	if m.creating {
+		return createRecordTypes[m.typeIndex]
+	}
+	return m.record.Type
+}
+
+// setLayout builds the structured data inputs for recordType, pre-filled from
+// record. For SRV records the service and protocol labels are split off the
+// name input.
+func (m *EditModel) setLayout(recordType string, record api.DNSRecord) {
+	m.layout = dataLayouts[recordType]
+	m.dataInputs = make([]textinput.Model, len(m.layout))
+
+	service, proto, host, split := splitServiceName(record.Name)
+	for i, f := range m.layout {
+		in := textinput.New()
+		in.Placeholder = f.placeholder
+		in.CharLimit = 2048
+		in.Width = 60
+		if f.numeric {
+			in.CharLimit = 12
+			in.Width = 20
+		}
+		switch {
+		case f.inName && f.key == "service":
+			in.SetValue(service)
+		case f.inName && f.key == "proto":
+			in.SetValue(proto)
+		default:
+			in.SetValue(zonefile.DataValue(record.Data, f.key))
+		}
+		m.dataInputs[i] = in
+	}
+	if split && m.hasNameFields() {
+		m.nameInput.SetValue(host)
+	}
+}
+
+// hasNameFields reports whether the layout encodes labels in the record name.
+func (m EditModel) hasNameFields() bool {
+	for _, f := range m.layout {
+		if f.inName {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutData collects the structured data inputs into an api.DNSRecord.Data
+// map, with numeric fields as float64 as the API returns them.
+func (m EditModel) layoutData() map[string]any {
+	data := make(map[string]any, len(m.layout))
+	for i, f := range m.layout {
+		if f.inName {
+			continue
+		}
+		v := strings.TrimSpace(m.dataInputs[i].Value())
+		if f.numeric {
+			n, _ := strconv.ParseFloat(v, 64) // already validated
+			data[f.key] = n
+		} else {
+			data[f.key] = v
+		}
+	}
+	return data
+}
+
+// layoutName returns the record name to submit, prefixing the SRV service and
+// protocol labels when the layout has them.
+func (m EditModel) layoutName(host string) string {
+	var service, proto string
+	for i, f := range m.layout {
+		switch {
+		case f.inName && f.key == "service":
+			service = strings.TrimSpace(m.dataInputs[i].Value())
+		case f.inName && f.key == "proto":
+			proto = strings.TrimSpace(m.dataInputs[i].Value())
+		}
+	}
+	if !m.hasNameFields() {
+		return host
+	}
+	return joinServiceName(service, proto, host)
+}
+
+// dataIndex maps a focus value onto an index into the current layout.
+func (m EditModel) dataIndex(f editField) (int, bool) {
+	i := int(f - fieldData)
+	if f < fieldData || i >= len(m.layout) {
+		return 0, false
+	}
+	return i, true
+}
+
+// previewLine renders the record as a zone file line from the current form
+// values.
+func (m EditModel) previewLine() string {
+	ttl := 1
+	if v := strings.TrimSpace(m.ttlInput.Value()); !strings.EqualFold(v, "auto") {
+		ttl, _ = strconv.Atoi(v)
+	}
+	priority, _ := strconv.Atoi(strings.TrimSpace(m.priorityInput.Value()))
+	data := make(map[string]any, len(m.layout))
+	for i, f := range m.layout {
+		data[f.key] = strings.TrimSpace(m.dataInputs[i].Value())
+	}
+	name := m.layoutName(strings.TrimSpace(m.nameInput.Value()))
+	rdata := zonefile.RData(m.recordType(), m.contentValue(), priority, data)
+	return zonefile.Line(name, ttl, m.recordType(), rdata)
+}
+
+// parseTags splits a comma-separated tag list, dropping empty entries.
+func parseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// formatData renders structured record data as sorted key=value pairs.
+func formatData(data map[string]any) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + zonefile.DataValue(data, k)
+	}
+	return strings.Join(parts, " ")
+}
+
+// focusOrder returns the focusable fields in tab order. The Type field is only
+// focusable while creating a record, and Priority only for types that use it.
+// Structured layouts replace Content; their name labels come before Name.
+func (m EditModel) focusOrder() []editField {
+	var fields []editField
+	if m.creating {
+		fields = append(fields, fieldType)
+	}
+	for i, f := range m.layout {
+		if f.inName {
+			fields = append(fields, fieldData+editField(i))
+		}
+	}
+	fields = append(fields, fieldName)
+	if api.UsesPriority(m.recordType()) {
+		fields = append(fields, fieldPriority)
+	}
+	if len(m.layout) == 0 {
+		fields = append(fields, fieldContent)
+	}
+	for i, f := range m.layout {
+		if !f.inName {
+			fields = append(fields, fieldData+editField(i))
+		}
+	}
+	return append(fields, fieldTTL, fieldProxied, fieldComment, fieldTags, fieldSubmit)
+}
+
+// moveFocus moves focus by delta positions in the tab order, wrapping around.
+func (m *EditModel) moveFocus(delta int) {
+	order := m.focusOrder()
+	pos := 0
+	for i, f := range order {
+		if f == m.focused {
+			pos = i
+			break
+		}
+	}
+	m.focused = order[(pos+delta+len(order))%len(order)]
+	m.updateFocus()
+}
+
 // updateFocus sets the focused state on each text input.
 func (m *EditModel) updateFocus() {
 	m.nameInput.Blur()
+	m.priorityInput.Blur()
 	m.contentInput.Blur()
+	m.txtInput.Blur()
 	m.ttlInput.Blur()
+	m.commentInput.Blur()
+	m.tagsInput.Blur()
+	for i := range m.dataInputs {
+		m.dataInputs[i].Blur()
+	}
 
 	switch m.focused {
 	case fieldName:
 		m.nameInput.Focus()
+	case fieldPriority:
+		m.priorityInput.Focus()
 	case fieldContent:
-		m.contentInput.Focus()
+		if m.isTXT() {
+			m.txtInput.Focus()
+		} else {
+			m.contentInput.Focus()
+		}
 	case fieldTTL:
 		m.ttlInput.Focus()
+	case fieldComment:
+		m.commentInput.Focus()
+	case fieldTags:
+		m.tagsInput.Focus()
+	default:
+		if i, ok := m.dataIndex(m.focused); ok {
+			m.dataInputs[i].Focus()
+		}
 	}
 }
 
 // View renders the edit form.
 func (m EditModel) View() string {
+	if m.stale {
+		return m.staleView()
+	}
+	if m.reviewing {
+		return m.reviewView()
+	}
+
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("229")).
@@ -287,7 +763,7 @@ func (m EditModel) View() string {
 
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Width(10).
+		Width(11).
 		Padding(0, 1, 0, 2)
 
 	readOnlyStyle := lipgloss.NewStyle().
@@ -305,7 +781,7 @@ func (m EditModel) View() string {
 
 	errorStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("196")).
-		Padding(0, 0, 0, 12)
+		Padding(0, 0, 0, 13)
 
 	submitStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -323,13 +799,30 @@ func (m EditModel) View() string {
 
 	// Header
 	title := titleStyle.Render(fmt.Sprintf(" Edit %s Record ", sanitize(m.record.Type)))
+	if m.creating {
+		title = titleStyle.Render(" New Record ")
+	}
 	subtitle := headerStyle.Render(fmt.Sprintf("%s  %s", title, sanitize(m.zoneName)))
 
-	// Type (read-only)
+	// Type (read-only when editing, selectable when creating)
 	typeRow := lipgloss.JoinHorizontal(lipgloss.Top,
 		labelStyle.Render("Type"),
 		readOnlyStyle.Render(sanitize(m.record.Type)+" (read-only)"),
 	)
+	if m.creating {
+		typeLbl := labelStyle
+		typeValue := "◀ " + m.recordType() + " ▶"
+		if m.focused == fieldType {
+			typeLbl = focusedLabelStyle
+			typeValue = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("205")).
+				Render(typeValue)
+		}
+		typeRow = lipgloss.JoinHorizontal(lipgloss.Top,
+			typeLbl.Render("Type"),
+			proxiedStyle.Render(typeValue),
+		)
+	}
 
 	// Name
 	nameLbl := labelStyle
@@ -341,6 +834,16 @@ func (m EditModel) View() string {
 		fieldStyle.Render(m.nameInput.View()),
 	)
 
+	// Priority (MX, SRV and URI only)
+	priorityLbl := labelStyle
+	if m.focused == fieldPriority {
+		priorityLbl = focusedLabelStyle
+	}
+	priorityRow := lipgloss.JoinHorizontal(lipgloss.Top,
+		priorityLbl.Render("Priority"),
+		fieldStyle.Render(m.priorityInput.View()),
+	)
+
 	// Content
 	contentLbl := labelStyle
 	if m.focused == fieldContent {
@@ -350,6 +853,23 @@ func (m EditModel) View() string {
 		contentLbl.Render("Content"),
 		fieldStyle.Render(m.contentInput.View()),
 	)
+	if m.isTXT() {
+		content := m.contentValue()
+		boundaries := readOnlyStyle.Render(txtBoundaries(txtEditorStrings(m.txtInput.Value()), content))
+		if len(content) > validate.TXTContentMax {
+			boundaries = readOnlyStyle.Foreground(lipgloss.Color("196")).Render(txtBoundaries(txtEditorStrings(m.txtInput.Value()), content))
+		}
+		contentRow = lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.JoinHorizontal(lipgloss.Top,
+				contentLbl.Render("Content"),
+				fieldStyle.Render(m.txtInput.View()),
+			),
+			lipgloss.JoinHorizontal(lipgloss.Top,
+				labelStyle.Render("Strings"),
+				boundaries,
+			),
+		)
+	}
 
 	// TTL
 	ttlLbl := labelStyle
@@ -380,6 +900,26 @@ func (m EditModel) View() string {
 		proxiedStyle.Render(proxiedValue),
 	)
 
+	// Comment
+	commentLbl := labelStyle
+	if m.focused == fieldComment {
+		commentLbl = focusedLabelStyle
+	}
+	commentRow := lipgloss.JoinHorizontal(lipgloss.Top,
+		commentLbl.Render("Comment"),
+		fieldStyle.Render(m.commentInput.View()),
+	)
+
+	// Tags
+	tagsLbl := labelStyle
+	if m.focused == fieldTags {
+		tagsLbl = focusedLabelStyle
+	}
+	tagsRow := lipgloss.JoinHorizontal(lipgloss.Top,
+		tagsLbl.Render("Tags"),
+		fieldStyle.Render(m.tagsInput.View()),
+	)
+
 	apiErrorStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("196")).
 		Bold(true).
@@ -391,24 +931,73 @@ func (m EditModel) View() string {
 		savingStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
 		submitText = savingStyle.Render(m.spinner.View() + " Saving…")
 	} else if m.focused == fieldSubmit {
-		submitText = focusedSubmitStyle.Render("[ Save ]")
+		submitText = focusedSubmitStyle.Render(m.submitLabel())
 	} else {
-		submitText = submitStyle.Render("[ Save ]")
+		submitText = submitStyle.Render(m.submitLabel())
 	}
 
-	help := helpStyle.Render("Tab/Shift+Tab: navigate | Space: toggle proxied | Enter: save | Esc: cancel")
+	helpText := "Tab/Shift+Tab: navigate | Space: toggle proxied | Enter: save | Esc: cancel"
+	if m.creating {
+		helpText = "Tab/Shift+Tab: navigate | ←/→: change type | Space: toggle proxied | Enter: create | Esc: cancel"
+	}
+	if m.isTXT() && m.focused == fieldContent {
+		helpText = "Tab/Shift+Tab: navigate | Enter: new string | Esc: cancel"
+	}
+	help := helpStyle.Render(helpText)
+
+	// Structured data rows, one per layout entry
+	dataRow := func(i int) []string {
+		lbl := labelStyle
+		if m.focused == fieldData+editField(i) {
+			lbl = focusedLabelStyle
+		}
+		rows := []string{lipgloss.JoinHorizontal(lipgloss.Top,
+			lbl.Render(m.layout[i].label),
+			fieldStyle.Render(m.dataInputs[i].View()),
+		)}
+		if err, ok := m.errors[fieldData+editField(i)]; ok {
+			rows = append(rows, errorStyle.Render("! "+err))
+		}
+		return rows
+	}
 
 	// Build the view with inline validation errors
 	sections := []string{subtitle, "", typeRow}
 
+	for i, f := range m.layout {
+		if f.inName {
+			sections = append(sections, dataRow(i)...)
+		}
+	}
+
 	sections = append(sections, nameRow)
 	if err, ok := m.errors[fieldName]; ok {
 		sections = append(sections, errorStyle.Render("! "+err))
 	}
 
-	sections = append(sections, contentRow)
-	if err, ok := m.errors[fieldContent]; ok {
-		sections = append(sections, errorStyle.Render("! "+err))
+	if api.UsesPriority(m.recordType()) {
+		sections = append(sections, priorityRow)
+		if err, ok := m.errors[fieldPriority]; ok {
+			sections = append(sections, errorStyle.Render("! "+err))
+		}
+	}
+
+	if len(m.layout) == 0 {
+		sections = append(sections, contentRow)
+		if err, ok := m.errors[fieldContent]; ok {
+			sections = append(sections, errorStyle.Render("! "+err))
+		}
+	}
+	for i, f := range m.layout {
+		if !f.inName {
+			sections = append(sections, dataRow(i)...)
+		}
+	}
+	if len(m.layout) > 0 {
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render("Preview"),
+			readOnlyStyle.Render(sanitize(m.previewLine())),
+		))
 	}
 
 	sections = append(sections, ttlRow)
@@ -416,7 +1005,52 @@ func (m EditModel) View() string {
 		sections = append(sections, errorStyle.Render("! "+err))
 	}
 
-	sections = append(sections, proxiedRow, "", submitText)
+	sections = append(sections, proxiedRow)
+	if err, ok := m.errors[fieldProxied]; ok {
+		sections = append(sections, errorStyle.Render("! "+err))
+	}
+
+	sections = append(sections, commentRow, tagsRow)
+
+	// Structured data and timestamps are shown for reference only.
+	if len(m.layout) == 0 && len(m.record.Data) > 0 && m.recordType() == m.record.Type {
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render("Data"),
+			readOnlyStyle.Render(sanitize(formatData(m.record.Data))),
+		))
+	}
+	if !m.creating && !m.record.ModifiedOn.IsZero() {
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render("Modified"),
+			readOnlyStyle.Render(fmt.Sprintf("%s (created %s)",
+				m.record.ModifiedOn.Local().Format(timestampLayout),
+				m.record.CreatedOn.Local().Format(timestampLayout))),
+		))
+	}
+
+	if len(m.conflicts) > 0 {
+		sections = append(sections, "")
+		for _, c := range m.conflicts {
+			r := c.record
+			line := zonefile.Line(r.Name, r.TTL, r.Type, zonefile.RData(r.Type, r.Content, r.Priority, r.Data))
+			sections = append(sections,
+				apiErrorStyle.Render("! "+c.reason),
+				readOnlyStyle.Render("    "+sanitize(line)),
+			)
+		}
+		if !hasBlockingConflict(m.conflicts) {
+			sections = append(sections, apiErrorStyle.Render("Press Enter again to save anyway"))
+		}
+	}
+
+	sections = append(sections, "", submitText)
+
+	if m.mergeNote != "" {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Padding(0, 0, 0, 2).
+			Render(m.mergeNote))
+	}
 
 	// Show API error prominently above help text
 	if m.saveErr != nil {
@@ -428,6 +1062,42 @@ func (m EditModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
+// submitLabel returns the text of the submit button.
+func (m EditModel) submitLabel() string {
+	if m.staging {
+		return "[ Stage ]"
+	}
+	if m.creating {
+		return "[ Create ]"
+	}
+	return "[ Save ]"
+}
+
+// DataValue returns the current value of the structured input for key.
+func (m EditModel) DataValue(key string) string {
+	for i, f := range m.layout {
+		if f.key == key {
+			return m.dataInputs[i].Value()
+		}
+	}
+	return ""
+}
+
+// PriorityValue returns the current value of the priority input.
+func (m EditModel) PriorityValue() string {
+	return m.priorityInput.Value()
+}
+
+// CommentValue returns the current value of the comment input.
+func (m EditModel) CommentValue() string {
+	return m.commentInput.Value()
+}
+
+// TagsValue returns the current value of the tags input.
+func (m EditModel) TagsValue() string {
+	return m.tagsInput.Value()
+}
+
 // Proxied returns the current proxied toggle value.
 func (m EditModel) Proxied() bool {
 	return m.proxied
@@ -443,8 +1113,12 @@ func (m EditModel) NameValue() string {
 	return m.nameInput.Value()
 }
 
-// ContentValue returns the current value of the content input.
+// ContentValue returns the current value of the content input. For TXT
+// records it is the content the editor text will be saved as.
 func (m EditModel) ContentValue() string {
+	if m.isTXT() {
+		return m.contentValue()
+	}
 	return m.contentInput.Value()
 }
 
@@ -458,6 +1132,21 @@ func (m EditModel) Errors() map[editField]string {
 	return m.errors
 }
 
+// Conflicts returns the zone records that clash with the last submission.
+func (m EditModel) Conflicts() []conflict {
+	return m.conflicts
+}
+
+// Stale returns whether the three-way concurrency view is showing.
+func (m EditModel) Stale() bool {
+	return m.stale
+}
+
+// Reviewing returns whether the pre-save diff is showing.
+func (m EditModel) Reviewing() bool {
+	return m.reviewing
+}
+
 // Saving returns whether a save is in progress.
 func (m EditModel) Saving() bool {
 	return m.saving
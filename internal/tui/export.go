@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/terraform"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// zoneExportedMsg reports the outcome of writing a zone file. local is set
+// when the file was rendered from the loaded records rather than fetched
+// from the export endpoint.
+type zoneExportedMsg struct {
+	path  string
+	local bool
+	err   error
+}
+
+// terraformExportedMsg reports the outcome of writing Terraform
+// configuration for count records.
+type terraformExportedMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// expandHome expands a leading "~/" in path to the home directory.
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// writeExportFile writes text to path, expanding a leading "~/".
+func writeExportFile(path, text string) error {
+	path, err := expandHome(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(text), 0o644)
+}
+
+// pathPrompt asks for a file path. When optionLabel is set, Ctrl+L toggles
+// an option shown beside the input, such as rendering an export locally.
+type pathPrompt struct {
+	input       textinput.Model
+	active      bool
+	option      bool
+	optionLabel string
+}
+
+// newPathPrompt creates a closed prompt.
+func newPathPrompt(prompt, optionLabel string) pathPrompt {
+	in := textinput.New()
+	in.Prompt = prompt
+	in.CharLimit = 1024
+	in.Width = 50
+	return pathPrompt{input: in, optionLabel: optionLabel}
+}
+
+// open shows the prompt with path as the suggested file.
+func (p *pathPrompt) open(path string) tea.Cmd {
+	p.active = true
+	p.input.SetValue(path)
+	p.input.CursorEnd()
+	return p.input.Focus()
+}
+
+// update handles keys while the prompt is open. Enter returns the path and
+// Esc closes the prompt without one.
+func (p pathPrompt) update(msg tea.KeyMsg) (pathPrompt, string, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.active = false
+		p.input.Blur()
+		return p, "", nil
+	case "ctrl+l":
+		if p.optionLabel != "" {
+			p.option = !p.option
+		}
+		return p, "", nil
+	case "enter":
+		path := strings.TrimSpace(p.input.Value())
+		if path == "" {
+			return p, "", nil
+		}
+		p.active = false
+		p.input.Blur()
+		return p, path, nil
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, "", cmd
+}
+
+// View renders the input and its option.
+func (p pathPrompt) View() string {
+	if p.optionLabel == "" {
+		return p.input.View()
+	}
+	mode := "[ ] " + p.optionLabel
+	if p.option {
+		mode = "[x] " + p.optionLabel
+	}
+	return p.input.View() + "  " + mode
+}
+
+// exportCmd writes the zone file to path, from the export endpoint or, when
+// local is set, from the loaded records.
+func (m RecordsModel) exportCmd(path string, local bool) tea.Cmd {
+	client := m.client
+	zone := m.zone
+	records := m.records
+	return func() tea.Msg {
+		var text string
+		if local {
+			text = zonefile.Render(zone.Name, records)
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			var err error
+			if text, err = client.ExportZoneFile(ctx, zone.ID); err != nil {
+				return zoneExportedMsg{path: path, err: err}
+			}
+		}
+		return zoneExportedMsg{path: path, local: local, err: writeExportFile(path, text)}
+	}
+}
+
+// terraformCmd writes the marked records, or every record of the zone when
+// none is marked, to path as Terraform resources with import blocks.
+func (m RecordsModel) terraformCmd(path string) tea.Cmd {
+	zone := m.zone
+	records := m.markedRecords()
+	if len(records) == 0 {
+		records = m.records
+	}
+	return func() tea.Msg {
+		text := terraform.Render(zone.ID, zone.Name, records)
+		return terraformExportedMsg{path: path, count: len(records), err: writeExportFile(path, text)}
+	}
+}
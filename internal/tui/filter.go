@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/query"
+)
+
+// recordFilter matches records whose type, name or content contain query.
+// By default the match is a case-insensitive substring; caseSensitive and
+// regex switch to an exact-case match and a regular expression respectively.
+// expr instead parses query as an expression over the record's fields.
+type recordFilter struct {
+	query         string
+	caseSensitive bool
+	regex         bool
+	expr          bool
+}
+
+// active reports whether the filter restricts the records shown.
+func (f recordFilter) active() bool {
+	return f.query != ""
+}
+
+// matcher compiles the filter into a predicate over strings. It returns an
+// error for an invalid regular expression.
+func (f recordFilter) matcher() (func(string) bool, error) {
+	if f.regex {
+		expr := f.query
+		if !f.caseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	if f.caseSensitive {
+		return func(s string) bool { return strings.Contains(s, f.query) }, nil
+	}
+	q := strings.ToLower(f.query)
+	return func(s string) bool { return strings.Contains(strings.ToLower(s), q) }, nil
+}
+
+// predicate compiles the filter into a predicate over records. It returns
+// an error for an invalid regular expression or expression.
+func (f recordFilter) predicate() (func(api.DNSRecord) bool, error) {
+	if f.expr {
+		q, err := query.Parse(f.query)
+		if err != nil {
+			return nil, err
+		}
+		return q.Match, nil
+	}
+	match, err := f.matcher()
+	if err != nil {
+		return nil, err
+	}
+	return func(r api.DNSRecord) bool {
+		return match(r.Type) || match(r.Name) || match(r.Content)
+	}, nil
+}
+
+// apply returns the indices of the records that match, in their original
+// order. An inactive filter matches every record.
+func (f recordFilter) apply(records []api.DNSRecord) ([]int, error) {
+	match := func(api.DNSRecord) bool { return true }
+	if f.active() {
+		var err error
+		if match, err = f.predicate(); err != nil {
+			return nil, err
+		}
+	}
+	var idx []int
+	for i, r := range records {
+		if match(r) {
+			idx = append(idx, i)
+		}
+	}
+	return idx, nil
+}
+
+// modeLabel summarises the matching options for the filter bar.
+func (f recordFilter) modeLabel() string {
+	if f.expr {
+		return "[x] query"
+	}
+	c, r := "[ ] case", "[ ] regex"
+	if f.caseSensitive {
+		c = "[x] case"
+	}
+	if f.regex {
+		r = "[x] regex"
+	}
+	return c + "  " + r + "  [ ] query"
+}
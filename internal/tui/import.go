@@ -0,0 +1,371 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+	"github.com/Azahorscak/cloudflare-tui/internal/state"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// isStateFile reports whether path names a desired-state file rather than
+// a zone file, by its extension.
+func isStateFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// importPlanMsg carries the plan for importing the file at path, or the
+// error that stopped it. state is set for a desired-state file, whose plan
+// leaves out the ignored deletes it counts in ignored.
+type importPlanMsg struct {
+	path    string
+	changes []plan.Change
+	state   bool
+	ignored int
+	err     error
+}
+
+// importAppliedMsg carries the outcome of each applied import change.
+type importAppliedMsg struct {
+	zoneID   string
+	zoneName string
+	outcomes []plan.Outcome
+}
+
+// closeImportMsg signals that the user left the import plan.
+type closeImportMsg struct{}
+
+// importCmd reads the zone file or desired-state file at path and plans
+// its import against the zone's records as they are now.
+func (m RecordsModel) importCmd(path string) tea.Cmd {
+	client := m.client
+	zone := m.zone
+	return func() tea.Msg {
+		file, err := expandHome(path)
+		if err != nil {
+			return importPlanMsg{path: path, err: err}
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return importPlanMsg{path: path, err: err}
+		}
+		var records []zonefile.Record
+		var f state.File
+		if isStateFile(path) {
+			if f, err = state.Load(src); err != nil {
+				return importPlanMsg{path: path, err: err}
+			}
+			if f.Zone != "" && !strings.EqualFold(strings.TrimSuffix(f.Zone, "."), zone.Name) {
+				return importPlanMsg{path: path, err: fmt.Errorf("the file describes %s, not %s", f.Zone, zone.Name)}
+			}
+		} else if records, err = zonefile.Parse(string(src), zone.Name); err != nil {
+			return importPlanMsg{path: path, err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		current, err := client.ListDNSRecords(ctx, zone.ID)
+		if err != nil {
+			return importPlanMsg{path: path, err: err}
+		}
+		if !isStateFile(path) {
+			return importPlanMsg{path: path, changes: plan.Import(zone.Name, current, records)}
+		}
+		changes, ignored := plan.State(zone.Name, current, f.DNSRecords(zone.Name), f.Ignore)
+		return importPlanMsg{path: path, changes: changes, state: true, ignored: ignored}
+	}
+}
+
+// importPhase is the step the import screen is on.
+type importPhase int
+
+const (
+	importReviewing importPhase = iota
+	importApplying
+	importDone
+)
+
+// ImportModel shows an import plan, lets the user tick the changes to make
+// and applies them. Creates and updates start ticked. Deletes only do for a
+// desired-state file, which lists every record the zone should have; a zone
+// file rarely does.
+type ImportModel struct {
+	client   *api.Client
+	zone     api.Zone
+	path     string
+	state    bool
+	ignored  int
+	changes  []plan.Change
+	selected []bool
+	// outcomes is indexed like changes once applied; unticked changes have
+	// no outcome.
+	outcomes []*plan.Outcome
+	endpoint bool
+	readOnly bool
+	phase    importPhase
+	cursor   int
+	spinner  spinner.Model
+	width    int
+	height   int
+}
+
+// NewImportModel creates the import screen for a plan of changes to zone.
+func NewImportModel(client *api.Client, zone api.Zone, msg importPlanMsg, readOnly bool, width, height int) ImportModel {
+	changes := msg.changes
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	selected := make([]bool, len(changes))
+	for i, c := range changes {
+		selected[i] = msg.state || c.Action() != "delete"
+	}
+	return ImportModel{
+		client:   client,
+		zone:     zone,
+		path:     msg.path,
+		state:    msg.state,
+		ignored:  msg.ignored,
+		changes:  changes,
+		selected: selected,
+		readOnly: readOnly,
+		spinner:  sp,
+		width:    width,
+		height:   height,
+	}
+}
+
+// Init returns no initial command.
+func (m ImportModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the import screen.
+func (m ImportModel) Update(msg tea.Msg) (ImportModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.phase == importApplying {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case importAppliedMsg:
+		m.phase = importDone
+		m.outcomes = make([]*plan.Outcome, len(m.changes))
+		n := 0
+		for i := range m.changes {
+			if m.selected[i] && n < len(msg.outcomes) {
+				m.outcomes[i] = &msg.outcomes[n]
+				n++
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.phase == importApplying {
+			return m, nil
+		}
+		if m.phase == importDone {
+			switch msg.String() {
+			case "enter", "esc", "q":
+				return m, func() tea.Msg { return closeImportMsg{} }
+			}
+		}
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.changes)-1 {
+				m.cursor++
+			}
+		case "esc", "q":
+			return m, func() tea.Msg { return closeImportMsg{} }
+		}
+		if m.phase != importReviewing || m.readOnly {
+			return m, nil
+		}
+		switch msg.String() {
+		case " ":
+			if m.cursor < len(m.changes) {
+				m.selected[m.cursor] = !m.selected[m.cursor]
+			}
+		case "a":
+			all := true
+			for _, s := range m.selected {
+				all = all && s
+			}
+			for i := range m.selected {
+				m.selected[i] = !all
+			}
+		case "m":
+			m.endpoint = !m.endpoint
+		case "enter":
+			return m.apply()
+		}
+	}
+	return m, nil
+}
+
+// apply sends the ticked changes.
+func (m ImportModel) apply() (ImportModel, tea.Cmd) {
+	var changes []plan.Change
+	for i, c := range m.changes {
+		if m.selected[i] {
+			changes = append(changes, c)
+		}
+	}
+	if len(changes) == 0 {
+		return m, nil
+	}
+	m.phase = importApplying
+	client, zone, endpoint := m.client, m.zone, m.endpoint
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), plan.ApplyTimeout)
+		defer cancel()
+		return importAppliedMsg{
+			zoneID:   zone.ID,
+			zoneName: zone.Name,
+			outcomes: plan.Apply(ctx, client, zone.ID, zone.Name, changes, endpoint),
+		}
+	})
+}
+
+// Busy returns whether the changes are being applied.
+func (m ImportModel) Busy() bool {
+	return m.phase == importApplying
+}
+
+// View renders the plan, with the outcome of each change once applied.
+func (m ImportModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	helpStyle := lipgloss.NewStyle().Faint(true).Padding(1, 0, 0, 2)
+
+	title := titleStyle.Render(" Import Zone File ")
+	if m.state {
+		title = titleStyle.Render(" Apply State File ")
+	}
+	sections := []string{headerStyle.Render(fmt.Sprintf("%s  %s  ←  %s", title, sanitize(m.zone.Name), sanitize(m.path)))}
+
+	method := "one batch, applied all or nothing"
+	if m.endpoint {
+		method = "a batch of deletes and updates, then Cloudflare's import endpoint for creates"
+	}
+	sections = append(sections, rowStyle.Render("Method: "+method), "", m.changeList())
+
+	helpText := "↑/↓: navigate | Space: tick | a: tick all | m: method | Enter: apply ticked | Esc: cancel"
+	switch {
+	case m.readOnly:
+		helpText = "[READ-ONLY]  ↑/↓: navigate | Esc: back"
+	case m.phase == importApplying:
+		helpText = "Applying changes…"
+	case m.phase == importDone:
+		helpText = "Enter/Esc: back to records"
+	}
+	sections = append(sections, helpStyle.Render(truncate(helpText, m.width-2)))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// changeList renders the changes around the cursor, each with its diff, and
+// the outcome once applied.
+func (m ImportModel) changeList() string {
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	selectedStyle := rowStyle.Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	diffStyle := lipgloss.NewStyle().Faint(true).Padding(0, 0, 0, 8)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 0, 0, 8)
+
+	if len(m.changes) == 0 {
+		return rowStyle.Faint(true).Render("The zone already matches the file.")
+	}
+	ticked := 0
+	for _, s := range m.selected {
+		if s {
+			ticked++
+		}
+	}
+	summary := fmt.Sprintf("%s; %d ticked", plan.Counts(m.changes), ticked)
+	if m.ignored > 0 {
+		summary += fmt.Sprintf("; unmanaged records ignored: %d", m.ignored)
+	}
+	lines := []string{rowStyle.Bold(true).Render(summary)}
+
+	// Each change takes two lines; show a window that keeps the cursor in view.
+	visible := max((m.height-18)/2, 3)
+	start := max(0, min(m.cursor-visible/2, len(m.changes)-visible))
+	end := min(start+visible, len(m.changes))
+	for i := start; i < end; i++ {
+		c := m.changes[i]
+		var mark, failure string
+		switch {
+		case m.phase == importApplying && m.selected[i]:
+			mark = m.spinner.View()
+		case m.phase == importDone && m.outcomes[i] != nil && m.outcomes[i].Err == nil:
+			mark = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓")
+		case m.phase == importDone && m.outcomes[i] != nil:
+			mark = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("✗")
+			failure = m.outcomes[i].Err.Error()
+		case m.selected[i]:
+			mark = "[x]"
+		default:
+			mark = "[ ]"
+		}
+		line := mark + " " + plan.Line(c)
+		style := rowStyle
+		if i == m.cursor && m.phase != importApplying {
+			style = selectedStyle
+		}
+		lines = append(lines, style.Render(truncate(sanitize(line), m.width-2)))
+		lines = append(lines, diffStyle.Render(truncate(sanitize(c.Summary()), m.width-8)))
+		if failure != "" {
+			lines = append(lines, errorStyle.Render(truncate(sanitize(failure), m.width-8)))
+		}
+	}
+	if end < len(m.changes) {
+		lines = append(lines, rowStyle.Faint(true).Render(fmt.Sprintf("… %d more", len(m.changes)-end)))
+	}
+
+	if m.phase == importDone {
+		applied, failed := 0, 0
+		for _, o := range m.outcomes {
+			switch {
+			case o == nil:
+			case o.Err == nil:
+				applied++
+			default:
+				failed++
+			}
+		}
+		style := rowStyle.Foreground(lipgloss.Color("42"))
+		if failed > 0 {
+			style = rowStyle.Foreground(lipgloss.Color("196"))
+		}
+		lines = append(lines, "", style.Bold(true).Render(fmt.Sprintf("%d applied, %d failed", applied, failed)))
+	}
+	return strings.Join(lines, "\n")
+}
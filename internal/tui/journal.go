@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+)
+
+// journalEntry records one mutation made during the session. before is the
+// zero record for a create and after is the zero record for a delete.
+type journalEntry struct {
+	at       time.Time
+	zoneID   string
+	zoneName string
+	before   api.DNSRecord
+	after    api.DNSRecord
+}
+
+// action describes what the entry did: "create", "update" or "delete".
+func (e journalEntry) action() string {
+	switch {
+	case e.before.ID == "":
+		return "create"
+	case e.after.ID == "":
+		return "delete"
+	}
+	return "update"
+}
+
+// record returns the snapshot that identifies the entry's record.
+func (e journalEntry) record() api.DNSRecord {
+	if e.after.ID != "" {
+		return e.after
+	}
+	return e.before
+}
+
+// summary lists the fields the entry changed as "field: old → new".
+func (e journalEntry) summary() string {
+	return plan.Change{Before: e.before, After: e.after}.Summary()
+}
+
+// closeJournalMsg signals that the user left the change journal.
+type closeJournalMsg struct{}
+
+// revertEntryMsg asks the root model to undo a journal entry.
+type revertEntryMsg struct {
+	entry journalEntry
+}
+
+// JournalModel lists the mutations made during the session, newest first,
+// and lets the user revert any of them.
+type JournalModel struct {
+	entries  []journalEntry
+	cursor   int
+	status   string
+	readOnly bool
+	width    int
+	height   int
+}
+
+// NewJournalModel creates a journal view over entries, which are stored in
+// the order they happened.
+func NewJournalModel(entries []journalEntry, readOnly bool, width, height int) JournalModel {
+	return JournalModel{entries: entries, readOnly: readOnly, width: width, height: height}
+}
+
+// Init is a no-op; the journal is held in memory.
+func (m JournalModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles navigation and revert requests.
+func (m JournalModel) Update(msg tea.Msg) (JournalModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+		case "enter", "r":
+			if e, ok := m.selected(); ok && !m.readOnly {
+				return m, func() tea.Msg { return revertEntryMsg{entry: e} }
+			}
+		case "esc", "q":
+			return m, func() tea.Msg { return closeJournalMsg{} }
+		}
+	}
+	return m, nil
+}
+
+// selected returns the entry under the cursor. The cursor counts from the
+// newest entry.
+func (m JournalModel) selected() (journalEntry, bool) {
+	i := len(m.entries) - 1 - m.cursor
+	if i < 0 || i >= len(m.entries) {
+		return journalEntry{}, false
+	}
+	return m.entries[i], true
+}
+
+// View renders the journal.
+func (m JournalModel) View() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 0, 1, 2).
+		Render(fmt.Sprintf("Session changes (%d)", len(m.entries)))
+
+	helpText := "↑/↓: navigate | Enter/r: revert | q/Esc: back | Ctrl+C: quit"
+	if m.readOnly {
+		helpText = "↑/↓: navigate | q/Esc: back | Ctrl+C: quit  [READ-ONLY]"
+	}
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Padding(1, 0, 0, 2).
+		Render(helpText)
+
+	if len(m.entries) == 0 {
+		empty := lipgloss.NewStyle().Faint(true).Padding(0, 0, 0, 2).Render("No changes made in this session.")
+		return "\n" + header + "\n" + empty + "\n" + help
+	}
+
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	selectedStyle := rowStyle.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57"))
+
+	var rows []string
+	for c := 0; c < len(m.entries); c++ {
+		e := m.entries[len(m.entries)-1-c]
+		r := e.record()
+		line := fmt.Sprintf("%s  %-6s  %-20s  %-5s %s  %s",
+			e.at.Local().Format("15:04:05"), e.action(), e.zoneName, r.Type, r.Name, e.summary())
+		style := rowStyle
+		if c == m.cursor {
+			style = selectedStyle
+		}
+		rows = append(rows, style.Render(truncate(sanitize(line), m.width-2)))
+	}
+	result := "\n" + header + "\n" + strings.Join(rows, "\n") + "\n"
+	if m.status != "" {
+		result += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1, 0, 0, 2).
+			Render(m.status) + "\n"
+	}
+	return result + help
+}
@@ -3,20 +3,30 @@ package tui
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/prefs"
 )
 
 // View represents which screen is currently active.
 type View int
 
 const (
-	ViewZones   View = iota
+	ViewZones View = iota
 	ViewRecords
 	ViewEdit
+	ViewDelete
+	ViewJournal
+	ViewDetail
+	ViewBulk
+	ViewPending
+	ViewReplace
+	ViewSearch
+	ViewImport
 )
 
 // selectZoneMsg signals a transition from zones to the records view.
@@ -27,6 +37,11 @@ type selectZoneMsg struct {
 // backToZonesMsg signals a transition back to the zone-selection view.
 type backToZonesMsg struct{}
 
+// prefsSavedMsg carries the result of writing the preferences file.
+type prefsSavedMsg struct {
+	err error
+}
+
 // Model is the root Bubble Tea model.
 type Model struct {
 	currentView View
@@ -34,13 +49,47 @@ type Model struct {
 	zones       ZonesModel
 	records     RecordsModel
 	edit        EditModel
+	delete      DeleteModel
+	journalView JournalModel
+	detail      DetailModel
+	bulk        BulkModel
+	pending     PendingModel
+	replace     ReplaceModel
+	search      SearchModel
+	importer    ImportModel
 	width       int
 	height      int
 	readOnly    bool
+
+	// journal records every mutation made during the session, oldest first.
+	// prevView is the screen to return to when the journal is closed, and
+	// reverting is set while an edit or delete opened from it is in progress.
+	journal   []journalEntry
+	prevView  View
+	reverting bool
+
+	// searchFrom is the screen to return to when the global search is
+	// closed. searching is set once a search has been opened; it is kept so
+	// its results survive a jump to a record.
+	searchFrom View
+	searching  bool
+
+	// staging sends saved edits to staged instead of the API; staged holds
+	// the pending changes, oldest first, until they are applied or dropped.
+	// pendingFrom is the screen to return to when the pending view is closed.
+	staging     bool
+	staged      []stagedChange
+	pendingFrom View
+
+	// prefs are the saved UI choices, written back to prefsPath when they
+	// change. An empty prefsPath disables saving.
+	prefs     prefs.Prefs
+	prefsPath string
 }
 
 // New creates a new root Model with the given API client.
-// When readOnly is true, all mutating operations (editing records) are disabled.
+// When readOnly is true, all mutating operations (creating, editing and
+// deleting records) are disabled.
 func New(client *api.Client, readOnly bool) Model {
 	return Model{
 		currentView: ViewZones,
@@ -50,6 +99,14 @@ func New(client *api.Client, readOnly bool) Model {
 	}
 }
 
+// WithPrefs returns a copy of m that starts from the saved preferences p and
+// writes changes back to path.
+func (m Model) WithPrefs(path string, p prefs.Prefs) Model {
+	m.prefsPath = path
+	m.prefs = p
+	return m
+}
+
 func (m Model) Init() tea.Cmd {
 	return m.zones.Init()
 }
@@ -60,6 +117,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
+		if msg.String() == "ctrl+r" && m.currentView != ViewJournal && !m.busy() {
+			m.prevView = m.currentView
+			m.currentView = ViewJournal
+			m.journalView = NewJournalModel(m.journal, m.readOnly, m.width, m.height)
+			return m, m.journalView.Init()
+		}
+		if msg.String() == "ctrl+p" && !m.readOnly && m.currentView != ViewPending && m.currentView != ViewJournal && !m.busy() {
+			m.pendingFrom = m.currentView
+			m.currentView = ViewPending
+			m.pending = NewPendingModel(m.client, m.staged, m.width, m.height)
+			return m, m.pending.Init()
+		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -68,7 +137,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case selectZoneMsg:
 		m.currentView = ViewRecords
-		m.records = NewRecordsModel(m.client, msg.zone, m.width, m.height, m.readOnly)
+		m.records = m.newRecordsModel(msg.zone)
 		return m, m.records.Init()
 
 	case backToZonesMsg:
@@ -81,16 +150,330 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.currentView = ViewEdit
 		m.edit = NewEditModel(m.client, m.records.zone.ID, m.records.zone.Name, msg.record, m.width, m.height)
+		if i := m.stagedIndex(m.records.zone.ID, msg.record.ID); m.staging && i >= 0 {
+			// Continue from the staged edit, keeping the record as loaded.
+			m.edit = NewEditModel(m.client, m.records.zone.ID, m.records.zone.Name, paramsRecord(msg.record.ID, m.staged[i].params), m.width, m.height)
+			m.edit.record = msg.record
+		}
+		m.edit.zoneRecords = m.records.records
+		m.edit.staging = m.staging
+		return m, m.edit.Init()
+
+	case newRecordMsg:
+		if m.readOnly {
+			return m, nil
+		}
+		m.currentView = ViewEdit
+		m.edit = NewCreateModel(m.client, m.records.zone.ID, m.records.zone.Name, m.width, m.height)
+		m.edit.zoneRecords = m.records.records
+		m.edit.staging = m.staging
 		return m, m.edit.Init()
 
+	case deleteRecordMsg:
+		if m.readOnly {
+			return m, nil
+		}
+		m.currentView = ViewDelete
+		m.delete = NewDeleteModel(m.client, m.records.zone.ID, m.records.zone.Name, msg.record, m.width, m.height)
+		return m, m.delete.Init()
+
+	case cancelDeleteMsg:
+		m.currentView = ViewRecords
+		if m.reverting {
+			m.currentView = ViewJournal
+			m.reverting = false
+		}
+		return m, nil
+
+	case deleteDoneMsg:
+		m.journal = append(m.journal, journalEntry{
+			at: time.Now(), zoneID: m.delete.zoneID, zoneName: m.delete.zoneName, before: msg.record,
+		})
+		m.currentView = ViewRecords
+		m.reverting = false
+		load := m.openZoneRecords(m.delete.zoneID, m.delete.zoneName)
+		m.records.removeRecord(msg.record.ID)
+		m.records.statusMsg = fmt.Sprintf("Record %q deleted", msg.record.Name)
+		return m, tea.Batch(load, clearStatusAfter(5*time.Second))
+
 	case cancelEditMsg:
 		m.currentView = ViewRecords
+		if m.reverting {
+			m.currentView = ViewJournal
+			m.reverting = false
+		}
 		return m, nil
 
 	case editDoneMsg:
+		m.journal = append(m.journal, journalEntry{
+			at: time.Now(), zoneID: m.edit.zoneID, zoneName: m.edit.zoneName, before: msg.before, after: msg.record,
+		})
 		m.currentView = ViewRecords
+		m.reverting = false
+		if load := m.openZoneRecords(m.edit.zoneID, m.edit.zoneName); load != nil {
+			m.records.statusMsg = fmt.Sprintf("Record %q saved successfully", msg.record.Name)
+			return m, tea.Batch(load, clearStatusAfter(5*time.Second))
+		}
+		if msg.created {
+			m.records.insertRecord(msg.record)
+			m.records.statusMsg = fmt.Sprintf("Record %q created successfully", msg.record.Name)
+			return m, clearStatusAfter(5 * time.Second)
+		}
 		m.records.statusMsg = fmt.Sprintf("Record %q saved successfully", msg.record.Name)
 		return m, tea.Batch(m.records.fetchRecords(), clearStatusAfter(5*time.Second))
+
+	case bulkEditMsg:
+		if m.readOnly {
+			return m, nil
+		}
+		m.currentView = ViewBulk
+		m.bulk = NewBulkModel(m.client, m.records.zone.ID, m.records.zone.Name, msg.records, m.width, m.height)
+		return m, m.bulk.Init()
+
+	case bulkDoneMsg:
+		m.currentView = ViewRecords
+		if msg.items == nil {
+			return m, nil
+		}
+		updated, failed := 0, 0
+		for _, item := range msg.items {
+			switch item.status {
+			case bulkUpdated:
+				updated++
+				m.journal = append(m.journal, journalEntry{
+					at: time.Now(), zoneID: msg.zoneID, zoneName: msg.zoneName, before: item.before, after: item.after,
+				})
+			case bulkFailed:
+				failed++
+			}
+		}
+		load := m.openZoneRecords(msg.zoneID, msg.zoneName)
+		if load == nil {
+			load = m.records.fetchRecords()
+		}
+		m.records.clearMarked()
+		m.records.statusMsg = fmt.Sprintf("Bulk edit: %d updated, %d failed", updated, failed)
+		return m, tea.Batch(load, clearStatusAfter(5*time.Second))
+
+	case globalSearchMsg:
+		m.searchFrom = m.currentView
+		m.currentView = ViewSearch
+		if !m.searching {
+			m.searching = true
+			m.search = NewSearchModel(m.client, m.width, m.height)
+			m.search.queries = m.prefs.Queries
+			return m, m.search.Init()
+		}
+		if m.search.Running() {
+			return m, m.search.spinner.Tick
+		}
+		return m, nil
+
+	case searchZonesMsg, searchResultMsg, searchDoneMsg:
+		// The search keeps streaming while a found record is shown.
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		return m, cmd
+
+	case closeSearchMsg:
+		m.currentView = m.searchFrom
+		return m, nil
+
+	case jumpToRecordMsg:
+		m.currentView = ViewRecords
+		if load := m.openZoneRecords(msg.zone.ID, msg.zone.Name); load != nil {
+			m.records.jumpTo = msg.record.ID
+			return m, load
+		}
+		if !m.records.selectRecord(msg.record.ID) {
+			m.records.clearFilter()
+			m.records.selectRecord(msg.record.ID)
+		}
+		return m, nil
+
+	case findReplaceMsg:
+		if m.readOnly {
+			return m, nil
+		}
+		m.currentView = ViewReplace
+		m.replace = NewReplaceModel(m.client, m.records.zone, m.records.records, m.width, m.height)
+		return m, m.replace.Init()
+
+	case replaceDoneMsg:
+		m.currentView = ViewRecords
+		if msg.matches == nil {
+			return m, nil
+		}
+		updated, failed := 0, 0
+		var load tea.Cmd
+		for _, match := range msg.matches {
+			switch match.status {
+			case bulkUpdated:
+				updated++
+				m.journal = append(m.journal, journalEntry{
+					at: time.Now(), zoneID: match.zone.ID, zoneName: match.zone.Name, before: match.record, after: match.after,
+				})
+				if match.zone.ID == m.records.zone.ID {
+					load = m.records.fetchRecords()
+				}
+			case bulkFailed:
+				failed++
+			}
+		}
+		m.records.statusMsg = fmt.Sprintf("Find and replace: %d updated, %d failed", updated, failed)
+		return m, tea.Batch(load, clearStatusAfter(5*time.Second))
+
+	case toggleStagingMsg:
+		m.staging = !m.staging
+		m.records.setStaged(m.staging, m.staged)
+		return m, nil
+
+	case stageEditMsg:
+		c := msg.change
+		if i := m.stagedIndex(c.zoneID, c.before.ID); i >= 0 {
+			c.before = m.staged[i].before
+			m.staged[i] = c
+		} else {
+			m.staged = append(m.staged, c)
+		}
+		m.currentView = ViewRecords
+		m.records.setStaged(m.staging, m.staged)
+		m.records.statusMsg = fmt.Sprintf("Staged %s of %q (%d pending)", c.entry().action(), c.params.Name, len(m.staged))
+		return m, clearStatusAfter(5 * time.Second)
+
+	case dropStagedMsg:
+		if msg.index < 0 || msg.index >= len(m.staged) {
+			return m, nil
+		}
+		m.staged = append(m.staged[:msg.index:msg.index], m.staged[msg.index+1:]...)
+		m.pending.changes = m.staged
+		m.pending.cursor = min(m.pending.cursor, max(len(m.staged)-1, 0))
+		m.records.setStaged(m.staging, m.staged)
+		return m, nil
+
+	case stagedAppliedMsg:
+		var remaining []stagedChange
+		var load tea.Cmd
+		for _, res := range msg.results {
+			if res.err != nil {
+				remaining = append(remaining, res.change)
+				continue
+			}
+			m.journal = append(m.journal, journalEntry{
+				at: time.Now(), zoneID: res.change.zoneID, zoneName: res.change.zoneName, before: res.change.before, after: res.after,
+			})
+			if res.change.zoneID == m.records.zone.ID {
+				load = m.records.fetchRecords()
+			}
+		}
+		m.staged = remaining
+		m.pending.applying = false
+		m.pending.results = msg.results
+		m.pending.changes = m.staged
+		m.pending.cursor = 0
+		m.records.setStaged(m.staging, m.staged)
+		return m, load
+
+	case closePendingMsg:
+		m.currentView = m.pendingFrom
+		return m, nil
+
+	case showDetailMsg:
+		m.currentView = ViewDetail
+		m.detail = NewDetailModel(m.records.zone.Name, msg.record, m.width, m.height)
+		return m, m.detail.Init()
+
+	case closeDetailMsg:
+		m.currentView = ViewRecords
+		return m, nil
+
+	case columnsChangedMsg:
+		m.prefs.HiddenColumns = msg.hidden
+		m.prefs.DetailPane = msg.detailPane
+		return m, m.savePrefs()
+
+	case queriesChangedMsg:
+		m.prefs.Queries = msg.queries
+		m.records.queries = msg.queries
+		m.search.queries = msg.queries
+		m.records.statusMsg = "Query saved"
+		return m, tea.Batch(m.savePrefs(), clearStatusAfter(5*time.Second))
+
+	case zoneExportedMsg:
+		switch {
+		case msg.err == nil && msg.local:
+			m.records.statusMsg = fmt.Sprintf("Zone file rendered locally and written to %s", msg.path)
+		case msg.err == nil:
+			m.records.statusMsg = fmt.Sprintf("Zone file written to %s", msg.path)
+		case !msg.local && (api.StatusCode(msg.err) == http.StatusForbidden || api.StatusCode(msg.err) == http.StatusUnauthorized):
+			m.records.statusMsg = "The API token may not export this zone; press e and Ctrl+L to render the file locally"
+		default:
+			m.records.statusMsg = "Export failed: " + msg.err.Error()
+		}
+		return m, clearStatusAfter(5 * time.Second)
+
+	case terraformExportedMsg:
+		if msg.err != nil {
+			m.records.statusMsg = "Terraform export failed: " + msg.err.Error()
+		} else {
+			m.records.statusMsg = fmt.Sprintf("Terraform export: %d resources written to %s", msg.count, msg.path)
+		}
+		return m, clearStatusAfter(5 * time.Second)
+
+	case importPlanMsg:
+		switch {
+		case msg.err != nil:
+			m.records.statusMsg = "Import failed: " + msg.err.Error()
+			return m, clearStatusAfter(5 * time.Second)
+		case len(msg.changes) == 0 && msg.ignored > 0:
+			m.records.statusMsg = fmt.Sprintf("The zone already matches %s (unmanaged records ignored: %d)", msg.path, msg.ignored)
+			return m, clearStatusAfter(5 * time.Second)
+		case len(msg.changes) == 0:
+			m.records.statusMsg = fmt.Sprintf("The zone already matches %s", msg.path)
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		m.records.statusMsg = ""
+		m.currentView = ViewImport
+		m.importer = NewImportModel(m.client, m.records.zone, msg, m.readOnly, m.width, m.height)
+		return m, m.importer.Init()
+
+	case importAppliedMsg:
+		for _, o := range msg.outcomes {
+			if o.Err != nil {
+				continue
+			}
+			m.journal = append(m.journal, journalEntry{
+				at: time.Now(), zoneID: msg.zoneID, zoneName: msg.zoneName, before: o.Change.Before, after: o.After,
+			})
+		}
+		var load tea.Cmd
+		if msg.zoneID == m.records.zone.ID {
+			load = m.records.fetchRecords()
+		}
+		var cmd tea.Cmd
+		m.importer, cmd = m.importer.Update(msg)
+		return m, tea.Batch(load, cmd)
+
+	case closeImportMsg:
+		m.currentView = ViewRecords
+		return m, nil
+
+	case prefsSavedMsg:
+		if msg.err != nil {
+			m.records.statusMsg = "Could not save preferences: " + msg.err.Error()
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		return m, nil
+
+	case closeJournalMsg:
+		m.currentView = m.prevView
+		return m, nil
+
+	case revertEntryMsg:
+		if m.readOnly {
+			return m, nil
+		}
+		return m.revert(msg.entry)
 	}
 
 	var cmd tea.Cmd
@@ -101,16 +484,151 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.records, cmd = m.records.Update(msg)
 	case ViewEdit:
 		m.edit, cmd = m.edit.Update(msg)
+	case ViewDelete:
+		m.delete, cmd = m.delete.Update(msg)
+	case ViewJournal:
+		m.journalView, cmd = m.journalView.Update(msg)
+	case ViewDetail:
+		m.detail, cmd = m.detail.Update(msg)
+	case ViewBulk:
+		m.bulk, cmd = m.bulk.Update(msg)
+	case ViewPending:
+		m.pending, cmd = m.pending.Update(msg)
+	case ViewReplace:
+		m.replace, cmd = m.replace.Update(msg)
+	case ViewSearch:
+		m.search, cmd = m.search.Update(msg)
+	case ViewImport:
+		m.importer, cmd = m.importer.Update(msg)
 	}
 	return m, cmd
 }
 
+// busy reports whether an API call started by the current screen is still
+// in flight, during which the journal cannot be opened.
+func (m Model) busy() bool {
+	return (m.currentView == ViewEdit && m.edit.saving) ||
+		(m.currentView == ViewDelete && m.delete.deleting) ||
+		(m.currentView == ViewBulk && m.bulk.running) ||
+		(m.currentView == ViewPending && m.pending.applying) ||
+		(m.currentView == ViewReplace && m.replace.Busy()) ||
+		(m.currentView == ViewImport && m.importer.Busy())
+}
+
+// stagedIndex returns the index of the pending change to the record with
+// the given ID, or -1 if it has none. Creates never match.
+func (m Model) stagedIndex(zoneID, recordID string) int {
+	for i, c := range m.staged {
+		if recordID != "" && c.zoneID == zoneID && c.before.ID == recordID {
+			return i
+		}
+	}
+	return -1
+}
+
+// openZoneRecords points the records view at the given zone, returning the
+// command that loads it, or nil if that zone is already shown.
+func (m *Model) openZoneRecords(zoneID, zoneName string) tea.Cmd {
+	if m.records.zone.ID == zoneID {
+		return nil
+	}
+	m.records = m.newRecordsModel(api.Zone{ID: zoneID, Name: zoneName})
+	return m.records.Init()
+}
+
+// newRecordsModel creates the records view for zone with the saved column
+// choices applied.
+func (m Model) newRecordsModel(zone api.Zone) RecordsModel {
+	r := NewRecordsModel(m.client, zone, m.width, m.height, m.readOnly)
+	r.hidden = make(map[string]bool, len(m.prefs.HiddenColumns))
+	for _, title := range m.prefs.HiddenColumns {
+		r.hidden[title] = true
+	}
+	r.detailPane = m.prefs.DetailPane
+	r.queries = m.prefs.Queries
+	r.setStaged(m.staging, m.staged)
+	return r
+}
+
+// savePrefs writes the preferences file in the background.
+func (m Model) savePrefs() tea.Cmd {
+	if m.prefsPath == "" {
+		return nil
+	}
+	path, p := m.prefsPath, m.prefs
+	return func() tea.Msg {
+		return prefsSavedMsg{err: prefs.Save(path, p)}
+	}
+}
+
+// revert undoes a journal entry through the normal confirmation flows: an
+// update re-applies the before snapshot via the review screen, a create is
+// undone through the delete modal, and a delete is undone by re-creating the
+// record via the review screen.
+func (m Model) revert(e journalEntry) (tea.Model, tea.Cmd) {
+	switch e.action() {
+	case "create":
+		m.delete = NewDeleteModel(m.client, e.zoneID, e.zoneName, e.after, m.width, m.height)
+		m.currentView = ViewDelete
+		m.reverting = true
+		return m, m.delete.Init()
+
+	case "update":
+		m.edit = NewEditModel(m.client, e.zoneID, e.zoneName, e.before, m.width, m.height)
+		m.edit.record = e.after
+		m.edit.pending = submitEditMsg{zoneID: e.zoneID, recordID: e.after.ID, params: e.before.Params()}
+
+	case "delete":
+		typeIndex := -1
+		for i, t := range createRecordTypes {
+			if t == e.before.Type {
+				typeIndex = i
+			}
+		}
+		if typeIndex < 0 {
+			m.journalView.status = fmt.Sprintf("%s records cannot be re-created from here", e.before.Type)
+			return m, nil
+		}
+		m.edit = NewEditModel(m.client, e.zoneID, e.zoneName, e.before, m.width, m.height)
+		m.edit.creating = true
+		m.edit.typeIndex = typeIndex
+		m.edit.record = api.DNSRecord{Type: e.before.Type, Data: e.before.Data}
+		m.edit.pending = submitEditMsg{zoneID: e.zoneID, params: e.before.Params()}
+	}
+
+	if m.records.zone.ID == e.zoneID {
+		m.edit.zoneRecords = m.records.records
+	}
+	m.edit.focused = fieldSubmit
+	m.edit.updateFocus()
+	m.edit.reviewing = true
+	m.currentView = ViewEdit
+	m.reverting = true
+	return m, m.edit.Init()
+}
+
 func (m Model) View() string {
 	switch m.currentView {
 	case ViewRecords:
 		return m.records.View()
 	case ViewEdit:
 		return m.edit.View()
+	case ViewDelete:
+		return m.delete.View()
+	case ViewJournal:
+		return m.journalView.View()
+	case ViewDetail:
+		return m.detail.View()
+	case ViewBulk:
+		return m.bulk.View()
+	case ViewPending:
+		return m.pending.View()
+	case ViewReplace:
+		return m.replace.View()
+	case ViewSearch:
+		return m.search.View()
+	case ViewImport:
+		return m.importer.View()
 	default:
 		return m.zones.View()
 	}
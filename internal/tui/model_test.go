@@ -1,16 +1,27 @@
 package tui
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Azahorscak/cloudflare-tui/internal/api"
 	"github.com/Azahorscak/cloudflare-tui/internal/config"
+	"github.com/Azahorscak/cloudflare-tui/internal/prefs"
+	"github.com/Azahorscak/cloudflare-tui/internal/query"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
 )
 
 func TestNew_StartsAtZonesView(t *testing.T) {
@@ -201,7 +212,7 @@ func newTestRecord() api.DNSRecord {
 		Name:    "example.com",
 		Content: "192.0.2.1",
 		TTL:     300,
-		Proxied: true,
+		Proxied: false,
 	}
 }
 
@@ -218,8 +229,8 @@ func TestEditModel_InitialFieldPopulation(t *testing.T) {
 	if m.TTLValue() != "300" {
 		t.Errorf("expected TTL '300', got %q", m.TTLValue())
 	}
-	if m.Proxied() != true {
-		t.Error("expected proxied to be true")
+	if m.Proxied() != false {
+		t.Error("expected proxied to be false")
 	}
 	if m.Focused() != fieldName {
 		t.Errorf("expected initial focus on fieldName, got %d", m.Focused())
@@ -262,6 +273,18 @@ func TestEditModel_TabCyclesFocus(t *testing.T) {
 		t.Errorf("expected focus on fieldProxied after tab, got %d", m.Focused())
 	}
 
+	// Tab to comment
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.Focused() != fieldComment {
+		t.Errorf("expected focus on fieldComment after tab, got %d", m.Focused())
+	}
+
+	// Tab to tags
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.Focused() != fieldTags {
+		t.Errorf("expected focus on fieldTags after tab, got %d", m.Focused())
+	}
+
 	// Tab to submit
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	if m.Focused() != fieldSubmit {
@@ -285,16 +308,22 @@ func TestEditModel_ShiftTabReversesFocus(t *testing.T) {
 		t.Errorf("expected focus on fieldSubmit after shift+tab from name, got %d", m.Focused())
 	}
 
-	// Shift+Tab to proxied
+	// Shift+Tab to tags
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
-	if m.Focused() != fieldProxied {
-		t.Errorf("expected focus on fieldProxied after shift+tab, got %d", m.Focused())
+	if m.Focused() != fieldTags {
+		t.Errorf("expected focus on fieldTags after shift+tab, got %d", m.Focused())
 	}
 
-	// Shift+Tab to TTL
+	// Shift+Tab to comment
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
-	if m.Focused() != fieldTTL {
-		t.Errorf("expected focus on fieldTTL after shift+tab, got %d", m.Focused())
+	if m.Focused() != fieldComment {
+		t.Errorf("expected focus on fieldComment after shift+tab, got %d", m.Focused())
+	}
+
+	// Shift+Tab to proxied
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.Focused() != fieldProxied {
+		t.Errorf("expected focus on fieldProxied after shift+tab, got %d", m.Focused())
 	}
 }
 
@@ -412,7 +441,7 @@ func TestEditModel_SubmitWithValidData(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to submit button
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	if m.Focused() != fieldSubmit {
@@ -444,8 +473,8 @@ func TestEditModel_SubmitWithValidData(t *testing.T) {
 	if sub.params.TTL != 300 {
 		t.Errorf("expected TTL 300, got %d", sub.params.TTL)
 	}
-	if sub.params.Proxied != true {
-		t.Error("expected proxied true")
+	if sub.params.Proxied != false {
+		t.Error("expected proxied false")
 	}
 	if sub.params.Type != "A" {
 		t.Errorf("expected type 'A', got %q", sub.params.Type)
@@ -458,7 +487,7 @@ func TestEditModel_SubmitAutoTTL(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to submit
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -482,7 +511,7 @@ func TestEditModel_ValidationEmptyName(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to submit
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -503,7 +532,7 @@ func TestEditModel_ValidationEmptyContent(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to submit
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -528,8 +557,9 @@ func TestEditModel_ValidationInvalidTTL(t *testing.T) {
 	m.ttlInput.SetValue("abc")
 
 	// Navigate to submit
-	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab}) // proxied
-	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab}) // submit
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
 
 	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	if cmd != nil {
@@ -548,7 +578,7 @@ func TestEditModel_ValidationNegativeTTL(t *testing.T) {
 	m.ttlInput.SetValue("-5")
 
 	// Navigate to submit
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -569,7 +599,7 @@ func TestEditModel_ValidationZeroTTL(t *testing.T) {
 	m.ttlInput.SetValue("0")
 
 	// Navigate to submit
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -591,7 +621,7 @@ func TestEditModel_ValidationMultipleErrors(t *testing.T) {
 	m.ttlInput.SetValue("bad")
 
 	// Navigate to submit
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 
@@ -608,7 +638,7 @@ func TestEditModel_ValidationErrorsClearedOnSuccessfulSubmit(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to submit and trigger validation error
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -662,7 +692,7 @@ func TestEditModel_ViewRendersValidationErrors(t *testing.T) {
 	m.ttlInput.SetValue("bad")
 
 	// Navigate to submit and trigger validation
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -714,11 +744,20 @@ func TestEditModel_SubmitEditMsgSetsSavingState(t *testing.T) {
 		},
 	})
 
+	if !m.Reviewing() {
+		t.Error("expected review screen after submitEditMsg")
+	}
+	if m.Saving() || cmd != nil {
+		t.Error("expected no save before the change is confirmed")
+	}
+
+	// Confirming the review starts the save.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	if !m.Saving() {
-		t.Error("expected saving to be true after submitEditMsg")
+		t.Error("expected saving to be true after confirming")
 	}
 	if cmd == nil {
-		t.Error("expected command (spinner tick + save cmd) after submitEditMsg")
+		t.Error("expected command (spinner tick + save cmd) after confirming")
 	}
 }
 
@@ -882,7 +921,7 @@ func TestEditModel_SaveErrorRetryFlow(t *testing.T) {
 
 	// User should be able to navigate to submit and retry
 	// (not saving, so keys work)
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	if m.Focused() != fieldSubmit {
@@ -1288,11 +1327,14 @@ func TestModel_FullEditSaveLoop(t *testing.T) {
 
 func TestEditModel_LongContentValue(t *testing.T) {
 	rec := newTestRecord()
+	rec.Type = "TXT"
 	rec.Content = strings.Repeat("a", 2000)
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
-	if m.ContentValue() != rec.Content {
-		t.Errorf("expected content length %d, got %d", len(rec.Content), len(m.ContentValue()))
+	// Long TXT content is saved as quoted strings of at most 255 bytes.
+	chunks, err := zonefile.SplitTXT(m.ContentValue())
+	if err != nil || len(chunks) != 8 || strings.Join(chunks, "") != rec.Content {
+		t.Errorf("expected content split into 8 strings, got %d (err %v)", len(chunks), err)
 	}
 
 	// Should render without error.
@@ -1302,7 +1344,7 @@ func TestEditModel_LongContentValue(t *testing.T) {
 	}
 
 	// Should validate and submit successfully.
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -1313,24 +1355,24 @@ func TestEditModel_LongContentValue(t *testing.T) {
 
 func TestEditModel_TTLBoundaryMinNonAuto(t *testing.T) {
 	rec := newTestRecord()
-	rec.TTL = 2
+	rec.TTL = 60
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
-	if m.TTLValue() != "2" {
-		t.Errorf("expected TTL '2', got %q", m.TTLValue())
+	if m.TTLValue() != "60" {
+		t.Errorf("expected TTL '60', got %q", m.TTLValue())
 	}
 
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	if cmd == nil {
-		t.Fatal("expected valid submission with TTL=2")
+		t.Fatal("expected valid submission with TTL=60")
 	}
 	msg := cmd()
 	sub := msg.(submitEditMsg)
-	if sub.params.TTL != 2 {
-		t.Errorf("expected TTL 2, got %d", sub.params.TTL)
+	if sub.params.TTL != 60 {
+		t.Errorf("expected TTL 60, got %d", sub.params.TTL)
 	}
 }
 
@@ -1343,7 +1385,7 @@ func TestEditModel_TTLBoundaryLargeValue(t *testing.T) {
 		t.Errorf("expected TTL '86400', got %q", m.TTLValue())
 	}
 
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -1362,7 +1404,7 @@ func TestEditModel_TTLAutoLowerCase(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 	m.ttlInput.SetValue("auto")
 
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -1381,7 +1423,7 @@ func TestEditModel_TTLAutoMixedCase(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 	m.ttlInput.SetValue("AuTo")
 
-	for i := 0; i < 4; i++ {
+	for m.Focused() != fieldSubmit {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -1407,12 +1449,9 @@ func TestEditModel_ProxiedOnMXRecord(t *testing.T) {
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to proxied field.
-	for i := 0; i < 3; i++ {
+	for m.Focused() != fieldProxied {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
-	if m.Focused() != fieldProxied {
-		t.Fatalf("expected focus on fieldProxied, got %d", m.Focused())
-	}
 
 	// Toggle proxied on.
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
@@ -1420,19 +1459,16 @@ func TestEditModel_ProxiedOnMXRecord(t *testing.T) {
 		t.Error("expected proxied to toggle to true even for MX record")
 	}
 
-	// Submit should proceed (API would reject, but form allows it).
-	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
-	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
-	if cmd == nil {
-		t.Fatal("expected submission to proceed for MX record with proxied=true")
+	// MX records cannot be proxied, so submission is blocked.
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
-	msg := cmd()
-	sub := msg.(submitEditMsg)
-	if sub.params.Type != "MX" {
-		t.Errorf("expected type MX, got %q", sub.params.Type)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Fatal("expected submission to be blocked for proxied MX record")
 	}
-	if !sub.params.Proxied {
-		t.Error("expected proxied=true in submit params")
+	if m.errors[fieldProxied] == "" {
+		t.Error("expected proxied error for MX record")
 	}
 }
 
@@ -1465,11 +1501,12 @@ func TestEditModel_ProxiedOnSRVRecord(t *testing.T) {
 		Content: "10 60 5060 sip.example.com",
 		TTL:     3600,
 		Proxied: false,
+		Data:    map[string]any{"priority": float64(10), "weight": float64(60), "port": float64(5060), "target": "sip.example.com"},
 	}
 	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 
 	// Navigate to proxied and toggle.
-	for i := 0; i < 3; i++ {
+	for m.Focused() != fieldProxied {
 		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
@@ -1477,22 +1514,22 @@ func TestEditModel_ProxiedOnSRVRecord(t *testing.T) {
 		t.Error("expected proxied to toggle to true for SRV record")
 	}
 
-	// Submit includes the toggled value.
-	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
-	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
-	if cmd == nil {
-		t.Fatal("expected submission to proceed for SRV record")
+	// SRV records cannot be proxied.
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
-	msg := cmd()
-	sub := msg.(submitEditMsg)
-	if sub.params.Type != "SRV" {
-		t.Errorf("expected type SRV, got %q", sub.params.Type)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Fatal("expected submission to be blocked for proxied SRV record")
+	}
+	if !strings.Contains(m.View(), "can be proxied") {
+		t.Error("expected proxied error in view")
 	}
 }
 
 func TestEditModel_TTLValidationBoundary(t *testing.T) {
-	// TTL = 1 means Auto; values between 2 and the minimum Cloudflare
-	// allows should still pass local validation.
+	// TTL = 1 means Auto; otherwise the value must lie within Cloudflare's
+	// 60-86400 second range.
 	tests := []struct {
 		input string
 		valid bool
@@ -1501,8 +1538,11 @@ func TestEditModel_TTLValidationBoundary(t *testing.T) {
 		{"Auto", true, 1},
 		{"auto", true, 1},
 		{"1", true, 1},
-		{"2", true, 2},
+		{"2", false, 0},
+		{"59", false, 0},
 		{"60", true, 60},
+		{"86400", true, 86400},
+		{"86401", false, 0},
 		{"0", false, 0},
 		{"-1", false, 0},
 		{"abc", false, 0},
@@ -1515,7 +1555,7 @@ func TestEditModel_TTLValidationBoundary(t *testing.T) {
 			m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
 			m.ttlInput.SetValue(tt.input)
 
-			for i := 0; i < 4; i++ {
+			for m.Focused() != fieldSubmit {
 				m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 			}
 			m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
@@ -1549,8 +1589,13 @@ func TestEditFlow_IntegrationWithMockedAPI(t *testing.T) {
 	updateCalled := false
 	mux := http.NewServeMux()
 
-	// Handle record update.
+	// Handle the pre-save read and the record update.
 	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-1","type":"A","name":"example.com","content":"192.0.2.1","ttl":1,"proxied":true}}`)
+			return
+		}
 		if r.Method == http.MethodPut {
 			updateCalled = true
 			w.Header().Set("Content-Type", "application/json")
@@ -1618,7 +1663,7 @@ func TestEditFlow_IntegrationWithMockedAPI(t *testing.T) {
 	// Step 3: select record → edit.
 	rec := api.DNSRecord{
 		ID: "rec-1", Type: "A", Name: "example.com",
-		Content: "192.0.2.1", TTL: 300, Proxied: true,
+		Content: "192.0.2.1", TTL: 1, Proxied: true,
 	}
 	updated, _ = model.Update(editRecordMsg{record: rec})
 	model = updated.(Model)
@@ -1630,7 +1675,7 @@ func TestEditFlow_IntegrationWithMockedAPI(t *testing.T) {
 	model.edit.contentInput.SetValue("203.0.113.50")
 
 	// Step 5: navigate to submit and press enter.
-	for i := 0; i < 4; i++ {
+	for model.edit.Focused() != fieldSubmit {
 		model.edit, _ = model.edit.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	var cmd tea.Cmd
@@ -1649,8 +1694,12 @@ func TestEditFlow_IntegrationWithMockedAPI(t *testing.T) {
 		t.Errorf("expected updated content '203.0.113.50', got %q", sub.params.Content)
 	}
 
-	// Step 7: deliver submitEditMsg → triggers API call.
-	model.edit, cmd = model.edit.Update(sub)
+	// Step 7: deliver submitEditMsg → review, then confirm → triggers API call.
+	model.edit, _ = model.edit.Update(sub)
+	if !strings.Contains(model.edit.View(), "203.0.113.50") {
+		t.Error("expected review screen to show the new content")
+	}
+	model.edit, cmd = model.edit.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	if !model.edit.Saving() {
 		t.Error("expected saving state")
 	}
@@ -1742,13 +1791,13 @@ func TestEditFlow_IntegrationAPIError(t *testing.T) {
 
 	rec := api.DNSRecord{
 		ID: "rec-1", Type: "A", Name: "example.com",
-		Content: "192.0.2.1", TTL: 300, Proxied: true,
+		Content: "192.0.2.1", TTL: 1, Proxied: true,
 	}
 	updated, _ = model.Update(editRecordMsg{record: rec})
 	model = updated.(Model)
 
 	// Submit the form.
-	for i := 0; i < 4; i++ {
+	for model.edit.Focused() != fieldSubmit {
 		model.edit, _ = model.edit.Update(tea.KeyMsg{Type: tea.KeyTab})
 	}
 	var cmd tea.Cmd
@@ -1756,8 +1805,9 @@ func TestEditFlow_IntegrationAPIError(t *testing.T) {
 	submitResult := cmd()
 	sub := submitResult.(submitEditMsg)
 
-	// Deliver submitEditMsg → triggers API call.
-	model.edit, cmd = model.edit.Update(sub)
+	// Deliver submitEditMsg and confirm → triggers API call.
+	model.edit, _ = model.edit.Update(sub)
+	model.edit, cmd = model.edit.Update(tea.KeyMsg{Type: tea.KeyEnter})
 
 	// Execute batch to find saveResultMsg.
 	batchResult := cmd()
@@ -1793,3 +1843,2493 @@ func TestEditFlow_IntegrationAPIError(t *testing.T) {
 		t.Error("expected view to show API error")
 	}
 }
+
+// --- Create record tests ---
+
+func TestRecordsModel_NKeyEmitsNewRecordMsg(t *testing.T) {
+	zone := api.Zone{ID: "z1", Name: "example.com"}
+	m := NewRecordsModel(nil, zone, 80, 24, false)
+	m.loading = false
+	m.records = []api.DNSRecord{}
+	m.table = m.buildTable(m.records)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if cmd == nil {
+		t.Fatal("expected command from n key, got nil")
+	}
+	if _, ok := cmd().(newRecordMsg); !ok {
+		t.Errorf("expected newRecordMsg, got %T", cmd())
+	}
+}
+
+func TestRecordsModel_ReadOnlyNKeyNoOp(t *testing.T) {
+	zone := api.Zone{ID: "z1", Name: "example.com"}
+	m := NewRecordsModel(nil, zone, 80, 24, true)
+	m.loading = false
+	m.records = []api.DNSRecord{}
+	m.table = m.buildTable(m.records)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if cmd != nil {
+		if _, ok := cmd().(newRecordMsg); ok {
+			t.Error("n key should not emit newRecordMsg in read-only mode")
+		}
+	}
+}
+
+func TestModel_NewRecordMsgOpensCreateForm(t *testing.T) {
+	m := New(nil, false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	model := updated.(Model)
+
+	updated, cmd := model.Update(newRecordMsg{})
+	model = updated.(Model)
+
+	if model.currentView != ViewEdit {
+		t.Fatalf("expected ViewEdit after newRecordMsg, got %d", model.currentView)
+	}
+	if !model.edit.creating {
+		t.Error("expected edit model to be in create mode")
+	}
+	if model.edit.zoneID != "zone-1" {
+		t.Errorf("expected zoneID 'zone-1', got %q", model.edit.zoneID)
+	}
+	if cmd == nil {
+		t.Error("expected Init command from create form")
+	}
+}
+
+func TestModel_ReadOnlyBlocksNewRecordMsg(t *testing.T) {
+	m := New(nil, true)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	model := updated.(Model)
+
+	updated, cmd := model.Update(newRecordMsg{})
+	model = updated.(Model)
+
+	if model.currentView != ViewRecords {
+		t.Errorf("expected to stay on ViewRecords in read-only mode, got %d", model.currentView)
+	}
+	if cmd != nil {
+		t.Error("expected no command from newRecordMsg in read-only mode")
+	}
+}
+
+func TestCreateModel_StartsOnTypeSelector(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+
+	if m.Focused() != fieldType {
+		t.Errorf("expected initial focus on fieldType, got %d", m.Focused())
+	}
+	if m.NameValue() != "" || m.ContentValue() != "" {
+		t.Errorf("expected empty name and content, got %q / %q", m.NameValue(), m.ContentValue())
+	}
+	if m.TTLValue() != "Auto" {
+		t.Errorf("expected TTL 'Auto', got %q", m.TTLValue())
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "New Record") {
+		t.Error("expected view to contain 'New Record' title")
+	}
+	if strings.Contains(view, "read-only") {
+		t.Error("expected Type not to be marked read-only in create mode")
+	}
+	if !strings.Contains(view, "Create") {
+		t.Error("expected view to contain 'Create' button")
+	}
+}
+
+func TestCreateModel_TypeSelectorCycles(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if got := m.recordType(); got != createRecordTypes[1] {
+		t.Errorf("expected type %q after right, got %q", createRecordTypes[1], got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if got := m.recordType(); got != createRecordTypes[len(createRecordTypes)-1] {
+		t.Errorf("expected left to wrap to %q, got %q", createRecordTypes[len(createRecordTypes)-1], got)
+	}
+}
+
+func TestCreateModel_TabOrderIncludesType(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+
+	want := []editField{fieldName, fieldContent, fieldTTL, fieldProxied, fieldComment, fieldTags, fieldSubmit, fieldType}
+	for _, f := range want {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		if m.Focused() != f {
+			t.Fatalf("expected focus %d, got %d", f, m.Focused())
+		}
+	}
+}
+
+func TestCreateModel_SubmitEmitsCreateParams(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight}) // AAAA
+	m.nameInput.SetValue("v6.example.com")
+	m.contentInput.SetValue("2001:db8::1")
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected submit command")
+	}
+	sub, ok := cmd().(submitEditMsg)
+	if !ok {
+		t.Fatalf("expected submitEditMsg, got %T", cmd())
+	}
+	if sub.recordID != "" {
+		t.Errorf("expected empty recordID for create, got %q", sub.recordID)
+	}
+	if sub.params.Type != "AAAA" || sub.params.Name != "v6.example.com" || sub.params.Content != "2001:db8::1" {
+		t.Errorf("unexpected create params: %+v", sub.params)
+	}
+	if sub.params.TTL != 1 {
+		t.Errorf("expected TTL 1, got %d", sub.params.TTL)
+	}
+}
+
+func TestCreateModel_ValidationBlocksEmptyFields(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected no command when validation fails")
+	}
+	if _, ok := m.Errors()[fieldName]; !ok {
+		t.Error("expected validation error for empty name")
+	}
+	if _, ok := m.Errors()[fieldContent]; !ok {
+		t.Error("expected validation error for empty content")
+	}
+}
+
+func TestCreateModel_SaveResultEmitsCreatedDone(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+	m.saving = true
+
+	created := api.DNSRecord{ID: "rec-new", Type: "A", Name: "new.example.com", Content: "192.0.2.7", TTL: 1}
+	_, cmd := m.Update(saveResultMsg{record: created})
+	if cmd == nil {
+		t.Fatal("expected editDoneMsg command")
+	}
+	done, ok := cmd().(editDoneMsg)
+	if !ok {
+		t.Fatalf("expected editDoneMsg, got %T", cmd())
+	}
+	if !done.created {
+		t.Error("expected editDoneMsg.created to be true")
+	}
+}
+
+func TestModel_CreatedRecordInsertedIntoTable(t *testing.T) {
+	m := New(nil, false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	model := updated.(Model)
+	updated, _ = model.Update(recordsLoadedMsg{records: []api.DNSRecord{
+		{ID: "rec-1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+	}})
+	model = updated.(Model)
+
+	updated, _ = model.Update(newRecordMsg{})
+	model = updated.(Model)
+
+	created := api.DNSRecord{ID: "rec-new", Type: "A", Name: "new.example.com", Content: "192.0.2.7", TTL: 1}
+	updated, cmd := model.Update(editDoneMsg{record: created, created: true})
+	model = updated.(Model)
+
+	if model.currentView != ViewRecords {
+		t.Fatalf("expected ViewRecords after create, got %d", model.currentView)
+	}
+	if len(model.records.records) != 2 {
+		t.Fatalf("expected 2 records after create, got %d", len(model.records.records))
+	}
+	if rows := model.records.table.Rows(); len(rows) != 2 || rows[1][1] != "new.example.com" {
+		t.Errorf("expected new row in table, got %v", rows)
+	}
+	if model.records.table.Cursor() != 1 {
+		t.Errorf("expected cursor on new row, got %d", model.records.table.Cursor())
+	}
+	if !strings.Contains(model.records.statusMsg, "created successfully") {
+		t.Errorf("expected created status message, got %q", model.records.statusMsg)
+	}
+	if cmd == nil {
+		t.Error("expected clear-status command after create")
+	}
+}
+
+func TestCreateFlow_IntegrationWithMockedAPI(t *testing.T) {
+	createCalled := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		createCalled = true
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "rec-new",
+				"type": "A",
+				"name": "new.example.com",
+				"content": "192.0.2.7",
+				"ttl": 1,
+				"proxied": false
+			}
+		}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	m := NewCreateModel(client, "zone-1", "example.com", 80, 24)
+	m.nameInput.SetValue("new.example.com")
+	m.contentInput.SetValue("192.0.2.7")
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	sub := cmd().(submitEditMsg)
+
+	m, _ = m.Update(sub)
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	var result saveResultMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if sr, ok := c().(saveResultMsg); ok {
+			result = sr
+		}
+	}
+	if result.err != nil {
+		t.Fatalf("expected no save error, got %v", result.err)
+	}
+	if result.record.ID != "rec-new" {
+		t.Errorf("expected created record ID 'rec-new', got %q", result.record.ID)
+	}
+	if !createCalled {
+		t.Error("expected API create endpoint to be called")
+	}
+}
+
+// --- Delete record tests ---
+
+func TestRecordsModel_DKeyEmitsDeleteRecordMsg(t *testing.T) {
+	zone := api.Zone{ID: "z1", Name: "example.com"}
+	m := NewRecordsModel(nil, zone, 80, 24, false)
+	m.loading = false
+	m.records = []api.DNSRecord{
+		{ID: "rec-1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+		{ID: "rec-2", Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 1},
+	}
+	m.table = m.buildTable(m.records)
+	m.table.SetCursor(1)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("expected command from d key, got nil")
+	}
+	del, ok := cmd().(deleteRecordMsg)
+	if !ok {
+		t.Fatalf("expected deleteRecordMsg, got %T", cmd())
+	}
+	if del.record.ID != "rec-2" {
+		t.Errorf("expected record 'rec-2', got %q", del.record.ID)
+	}
+}
+
+func TestRecordsModel_ReadOnlyHidesDelete(t *testing.T) {
+	zone := api.Zone{ID: "z1", Name: "example.com"}
+	m := NewRecordsModel(nil, zone, 80, 24, true)
+	m.loading = false
+	m.records = []api.DNSRecord{
+		{ID: "rec-1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+	}
+	m.table = m.buildTable(m.records)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd != nil {
+		if _, ok := cmd().(deleteRecordMsg); ok {
+			t.Error("d key should not emit deleteRecordMsg in read-only mode")
+		}
+	}
+	if strings.Contains(m.View(), "delete") {
+		t.Error("read-only help bar should not mention delete")
+	}
+}
+
+func TestModel_DeleteRecordMsgOpensModal(t *testing.T) {
+	m := New(nil, false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	model := updated.(Model)
+
+	updated, cmd := model.Update(deleteRecordMsg{record: newTestRecord()})
+	model = updated.(Model)
+
+	if model.currentView != ViewDelete {
+		t.Fatalf("expected ViewDelete after deleteRecordMsg, got %d", model.currentView)
+	}
+	if cmd == nil {
+		t.Error("expected Init command from delete modal")
+	}
+
+	updated, _ = model.Update(cancelDeleteMsg{})
+	model = updated.(Model)
+	if model.currentView != ViewRecords {
+		t.Errorf("expected ViewRecords after cancelDeleteMsg, got %d", model.currentView)
+	}
+}
+
+func TestModel_ReadOnlyBlocksDeleteRecordMsg(t *testing.T) {
+	m := New(nil, true)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	model := updated.(Model)
+
+	updated, cmd := model.Update(deleteRecordMsg{record: newTestRecord()})
+	model = updated.(Model)
+
+	if model.currentView != ViewRecords {
+		t.Errorf("expected to stay on ViewRecords in read-only mode, got %d", model.currentView)
+	}
+	if cmd != nil {
+		t.Error("expected no command from deleteRecordMsg in read-only mode")
+	}
+}
+
+func TestDeleteModel_ViewShowsFullRecord(t *testing.T) {
+	rec := newTestRecord()
+	m := NewDeleteModel(nil, "zone-1", "example.com", rec, 100, 30)
+	view := m.View()
+
+	for _, want := range []string{"Delete A Record", "example.com", "192.0.2.1", "Type the record name"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected delete modal to contain %q", want)
+		}
+	}
+}
+
+func TestDeleteModel_EnterRequiresMatchingName(t *testing.T) {
+	m := NewDeleteModel(nil, "zone-1", "example.com", newTestRecord(), 80, 24)
+
+	m.confirmInput.SetValue("example.co")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected no command when confirmation does not match")
+	}
+	if m.Deleting() {
+		t.Error("expected delete not to start on mismatch")
+	}
+	if !strings.Contains(m.View(), "does not match") {
+		t.Error("expected mismatch hint in view")
+	}
+
+	m.confirmInput.SetValue("example.com")
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected delete command when confirmation matches")
+	}
+	if !m.Deleting() {
+		t.Error("expected deleting state after confirmation")
+	}
+}
+
+func TestDeleteModel_EscEmitsCancel(t *testing.T) {
+	m := NewDeleteModel(nil, "zone-1", "example.com", newTestRecord(), 80, 24)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if cmd == nil {
+		t.Fatal("expected command from esc")
+	}
+	if _, ok := cmd().(cancelDeleteMsg); !ok {
+		t.Errorf("expected cancelDeleteMsg, got %T", cmd())
+	}
+}
+
+func TestDeleteModel_DeletingBlocksKeys(t *testing.T) {
+	m := NewDeleteModel(nil, "zone-1", "example.com", newTestRecord(), 80, 24)
+	m.deleting = true
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if cmd != nil {
+		t.Error("expected esc to be blocked while deleting")
+	}
+}
+
+func TestDeleteModel_ResultError(t *testing.T) {
+	m := NewDeleteModel(nil, "zone-1", "example.com", newTestRecord(), 80, 24)
+	m.deleting = true
+
+	m, cmd := m.Update(deleteResultMsg{err: fmt.Errorf("permission denied")})
+	if cmd != nil {
+		t.Error("expected no command after delete error")
+	}
+	if m.Deleting() {
+		t.Error("expected deleting to be false after error")
+	}
+	if m.DeleteErr() == nil || !strings.Contains(m.View(), "permission denied") {
+		t.Error("expected delete error to be shown")
+	}
+}
+
+func TestModel_DeleteDoneRemovesRow(t *testing.T) {
+	m := New(nil, false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	model := updated.(Model)
+	updated, _ = model.Update(recordsLoadedMsg{records: []api.DNSRecord{
+		{ID: "rec-1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+		{ID: "rec-2", Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 1},
+	}})
+	model = updated.(Model)
+
+	updated, _ = model.Update(deleteRecordMsg{record: model.records.records[1]})
+	model = updated.(Model)
+
+	updated, cmd := model.Update(deleteDoneMsg{record: model.records.records[1]})
+	model = updated.(Model)
+
+	if model.currentView != ViewRecords {
+		t.Fatalf("expected ViewRecords after delete, got %d", model.currentView)
+	}
+	if len(model.records.records) != 1 || model.records.records[0].ID != "rec-1" {
+		t.Errorf("expected only rec-1 to remain, got %+v", model.records.records)
+	}
+	if len(model.records.table.Rows()) != 1 {
+		t.Errorf("expected 1 table row, got %d", len(model.records.table.Rows()))
+	}
+	if !strings.Contains(model.records.statusMsg, "deleted") {
+		t.Errorf("expected deleted status message, got %q", model.records.statusMsg)
+	}
+	if cmd == nil {
+		t.Error("expected clear-status command after delete")
+	}
+}
+
+func TestDeleteFlow_IntegrationWithMockedAPI(t *testing.T) {
+	deleteCalled := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.NotFound(w, r)
+			return
+		}
+		deleteCalled = true
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-1"}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	m := NewDeleteModel(client, "zone-1", "example.com", newTestRecord(), 80, 24)
+	m.confirmInput.SetValue("example.com")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	var result deleteResultMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if dr, ok := c().(deleteResultMsg); ok {
+			result = dr
+		}
+	}
+	if result.err != nil {
+		t.Fatalf("expected no delete error, got %v", result.err)
+	}
+	if !deleteCalled {
+		t.Error("expected API delete endpoint to be called")
+	}
+
+	_, cmd = m.Update(result)
+	if cmd == nil {
+		t.Fatal("expected deleteDoneMsg command")
+	}
+	if done, ok := cmd().(deleteDoneMsg); !ok || done.record.ID != "rec-1" {
+		t.Errorf("expected deleteDoneMsg for rec-1, got %#v", cmd())
+	}
+}
+
+// --- Full record data model tests ---
+
+func newTestMXRecord() api.DNSRecord {
+	return api.DNSRecord{
+		ID:         "rec-mx",
+		Type:       "MX",
+		Name:       "example.com",
+		Content:    "mail.example.com",
+		TTL:        3600,
+		Priority:   10,
+		Comment:    "primary MX",
+		Tags:       []string{"owner:mail", "env:prod"},
+		CreatedOn:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		ModifiedOn: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+	}
+}
+
+func TestEditModel_MXShowsPriorityField(t *testing.T) {
+	m := NewEditModel(nil, "zone-1", "example.com", newTestMXRecord(), 100, 40)
+
+	if m.PriorityValue() != "10" {
+		t.Errorf("expected priority '10', got %q", m.PriorityValue())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.Focused() != fieldPriority {
+		t.Errorf("expected focus on fieldPriority after name, got %d", m.Focused())
+	}
+
+	view := m.View()
+	for _, want := range []string{"Priority", "primary MX", "owner:mail, env:prod", "Modified"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q", want)
+		}
+	}
+}
+
+func TestEditModel_PriorityHiddenForA(t *testing.T) {
+	m := NewEditModel(nil, "zone-1", "example.com", newTestRecord(), 100, 40)
+	for _, f := range m.focusOrder() {
+		if f == fieldPriority {
+			t.Fatal("expected no priority field for an A record")
+		}
+	}
+	if strings.Contains(m.View(), "Priority") {
+		t.Error("expected view not to contain Priority for an A record")
+	}
+}
+
+func TestEditModel_ValidationInvalidPriority(t *testing.T) {
+	m := NewEditModel(nil, "zone-1", "example.com", newTestMXRecord(), 80, 24)
+	m.priorityInput.SetValue("70000")
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected no command when priority is out of range")
+	}
+	if _, ok := m.Errors()[fieldPriority]; !ok {
+		t.Error("expected validation error for priority")
+	}
+}
+
+func TestEditModel_SubmitRoundTripsAllFields(t *testing.T) {
+	rec := newTestMXRecord()
+	rec.Type = "URI"
+	rec.Name = "_ftp._tcp.example.com"
+	rec.Data = map[string]any{"weight": float64(5), "target": "ftp://ftp.example.com/"}
+	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
+	m.tagsInput.SetValue(" owner:mail ,, env:staging ")
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected submit command")
+	}
+	sub := cmd().(submitEditMsg)
+
+	if sub.params.Priority != 10 {
+		t.Errorf("expected priority 10, got %d", sub.params.Priority)
+	}
+	if sub.params.Comment != "primary MX" {
+		t.Errorf("expected comment to round-trip, got %q", sub.params.Comment)
+	}
+	if want := []string{"owner:mail", "env:staging"}; !reflect.DeepEqual(sub.params.Tags, want) {
+		t.Errorf("expected tags %v, got %v", want, sub.params.Tags)
+	}
+	if !reflect.DeepEqual(sub.params.Data, rec.Data) {
+		t.Errorf("expected data to round-trip, got %v", sub.params.Data)
+	}
+}
+
+func TestRecordsModel_ShowsModifiedAndDetails(t *testing.T) {
+	zone := api.Zone{ID: "z1", Name: "example.com"}
+	m := NewRecordsModel(nil, zone, 120, 24, false)
+	m, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{newTestMXRecord()}})
+
+	rows := m.table.Rows()
+	if len(rows) != 1 || rows[0][5] == "" {
+		t.Fatalf("expected a Modified column value, got %v", rows)
+	}
+
+	view := m.View()
+	for _, want := range []string{"Priority: 10", "Comment: primary MX", "Tags: owner:mail, env:prod"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected records view to contain %q", want)
+		}
+	}
+}
+
+// --- Structured record editor tests ---
+
+func newTestSRVRecord() api.DNSRecord {
+	return api.DNSRecord{
+		ID:      "rec-srv",
+		Type:    "SRV",
+		Name:    "_sip._tcp.example.com",
+		Content: "60 5060 sip.example.com",
+		TTL:     300,
+		Data:    map[string]any{"priority": float64(10), "weight": float64(60), "port": float64(5060), "target": "sip.example.com"},
+	}
+}
+
+func TestEditModel_SRVLayoutSplitsName(t *testing.T) {
+	m := NewEditModel(nil, "zone-1", "example.com", newTestSRVRecord(), 100, 40)
+
+	if m.NameValue() != "example.com" {
+		t.Errorf("expected host 'example.com' in name input, got %q", m.NameValue())
+	}
+	for key, want := range map[string]string{"service": "_sip", "proto": "_tcp", "priority": "10", "weight": "60", "port": "5060", "target": "sip.example.com"} {
+		if got := m.DataValue(key); got != want {
+			t.Errorf("DataValue(%q) = %q, want %q", key, got, want)
+		}
+	}
+	for _, f := range m.focusOrder() {
+		if f == fieldContent {
+			t.Error("expected structured layout to replace the content field")
+		}
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "_sip._tcp.example.com. 300 IN SRV 10 60 5060 sip.example.com.") {
+		t.Errorf("expected zone file preview in view, got:\n%s", view)
+	}
+}
+
+func TestEditModel_SRVSubmitBuildsData(t *testing.T) {
+	m := NewEditModel(nil, "zone-1", "example.com", newTestSRVRecord(), 80, 24)
+	m.dataInputs[1].SetValue("udp") // proto, underscore added on submit
+	m.dataInputs[4].SetValue("5061")
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected submit command, errors: %v", m.Errors())
+	}
+	sub := cmd().(submitEditMsg)
+
+	if sub.params.Name != "_sip._udp.example.com" {
+		t.Errorf("expected recomposed name, got %q", sub.params.Name)
+	}
+	want := map[string]any{"priority": float64(10), "weight": float64(60), "port": float64(5061), "target": "sip.example.com"}
+	if !reflect.DeepEqual(sub.params.Data, want) {
+		t.Errorf("expected data %v, got %v", want, sub.params.Data)
+	}
+	if sub.params.Content != "10 60 5061 sip.example.com." {
+		t.Errorf("expected rendered content, got %q", sub.params.Content)
+	}
+}
+
+func TestEditModel_StructuredValidation(t *testing.T) {
+	rec := api.DNSRecord{ID: "rec-caa", Type: "CAA", Name: "example.com", TTL: 1,
+		Data: map[string]any{"flags": "x", "tag": "issue", "value": ""}}
+	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected no command when structured fields are invalid")
+	}
+	if !strings.Contains(m.Errors()[fieldData], "number") {
+		t.Errorf("expected numeric error on flags, got %v", m.Errors())
+	}
+	if !strings.Contains(m.Errors()[fieldData+2], "non-empty") {
+		t.Errorf("expected empty error on value, got %v", m.Errors())
+	}
+	if !strings.Contains(m.View(), "Flags must be a number") {
+		t.Error("expected structured field error in view")
+	}
+}
+
+func TestCreateModel_TypeChangeBuildsLayout(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+	for m.recordType() != "CAA" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	if len(m.dataInputs) != 3 {
+		t.Fatalf("expected 3 CAA inputs, got %d", len(m.dataInputs))
+	}
+	m.nameInput.SetValue("example.com")
+	m.dataInputs[0].SetValue("0")
+	m.dataInputs[1].SetValue("issue")
+	m.dataInputs[2].SetValue("letsencrypt.org")
+
+	if !strings.Contains(m.View(), `example.com. 300 IN CAA 0 issue "letsencrypt.org"`) {
+		t.Error("expected CAA zone file preview")
+	}
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected submit command, errors: %v", m.Errors())
+	}
+	sub := cmd().(submitEditMsg)
+	if sub.params.Type != "CAA" || sub.params.Data["tag"] != "issue" || sub.params.Data["flags"] != float64(0) {
+		t.Errorf("unexpected CAA params: %+v", sub.params)
+	}
+}
+
+func TestCreateModel_TypeFieldKeepsDataUntilTypeChanges(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+	for m.recordType() != "CAA" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	m.dataInputs[2].SetValue("letsencrypt.org")
+
+	// Keys that do not change the type leave the structured values alone.
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("x")}, {Type: tea.KeyUp}} {
+		m, _ = m.Update(key)
+	}
+	if m.recordType() != "CAA" || m.dataInputs[2].Value() != "letsencrypt.org" {
+		t.Fatalf("expected the CAA value to survive, type %s value %q", m.recordType(), m.dataInputs[2].Value())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.recordType() == "CAA" || len(m.dataInputs) == 3 && m.dataInputs[2].Value() != "" {
+		t.Errorf("expected a new layout after the type changed, got %s", m.recordType())
+	}
+}
+
+func TestEditModel_TypeAwareContentValidation(t *testing.T) {
+	tests := []struct {
+		recordType string
+		content    string
+		wantErr    string
+	}{
+		{"A", "192.0.2.1", ""},
+		{"A", "2001:db8::1", "IPv4"},
+		{"A", "192.0.2", "IPv4"},
+		{"AAAA", "2001:db8::1", ""},
+		{"AAAA", "192.0.2.1", "IPv6"},
+		{"AAAA", "::ffff:192.0.2.1", "IPv6"},
+		{"CNAME", "target.example.com", ""},
+		{"CNAME", "192.0.2.1", "not an IP"},
+		{"CNAME", "bad-.example.com", "hyphen"},
+		{"CNAME", "a..example.com", "empty labels"},
+		{"NS", "ns 1.example.com", "invalid character"},
+		{"MX", strings.Repeat("a", 64) + ".example.com", "63"},
+		{"TXT", "v=spf1 -all", ""},
+		{"TXT", `"part one" "part two"`, ""},
+		{"TXT", strings.Repeat("a", 2049), "2048"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.recordType+"="+tt.content, func(t *testing.T) {
+			rec := api.DNSRecord{ID: "rec-1", Type: tt.recordType, Name: "example.com", Content: tt.content, TTL: 1}
+			m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
+			for m.Focused() != fieldSubmit {
+				m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+			}
+			m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			got := m.Errors()[fieldContent]
+			if tt.wantErr == "" {
+				if cmd == nil || got != "" {
+					t.Errorf("expected valid content, got error %q", got)
+				}
+				return
+			}
+			if cmd != nil {
+				t.Error("expected submission to be blocked")
+			}
+			if !strings.Contains(got, tt.wantErr) {
+				t.Errorf("expected content error containing %q, got %q", tt.wantErr, got)
+			}
+		})
+	}
+}
+
+func TestEditModel_ProxiedRequiresAutoTTL(t *testing.T) {
+	rec := newTestRecord()
+	rec.Proxied = true
+	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
+
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Fatal("expected proxied record with TTL 300 to be rejected")
+	}
+	if !strings.Contains(m.Errors()[fieldTTL], "Auto") {
+		t.Errorf("expected Auto TTL error, got %q", m.Errors()[fieldTTL])
+	}
+
+	m.ttlInput.SetValue("auto")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected submission with Auto TTL, errors: %v", m.Errors())
+	}
+	if sub := cmd().(submitEditMsg); sub.params.TTL != 1 || !sub.params.Proxied {
+		t.Errorf("expected proxied record with TTL 1, got %+v", sub.params)
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	zone := []api.DNSRecord{
+		{ID: "a1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true},
+		{ID: "c1", Type: "CNAME", Name: "blog.example.com", Content: "host.example.net", TTL: 1},
+		{ID: "ns1", Type: "NS", Name: "sub.example.com", Content: "ns1.example.net", TTL: 3600},
+	}
+	tests := []struct {
+		name     string
+		recordID string
+		params   api.UpdateDNSRecordParams
+		want     []string
+		blocking bool
+	}{
+		{"cname next to A", "", api.UpdateDNSRecordParams{Type: "CNAME", Name: "www", Content: "x.example.net"}, []string{"a1"}, true},
+		{"A onto cname", "", api.UpdateDNSRecordParams{Type: "A", Name: "blog.example.com", Content: "192.0.2.9"}, []string{"c1"}, true},
+		{"duplicate", "", api.UpdateDNSRecordParams{Type: "A", Name: "WWW.example.com.", Content: "192.0.2.1", Proxied: true}, []string{"a1"}, true},
+		{"editing itself", "a1", api.UpdateDNSRecordParams{Type: "A", Name: "www.example.com", Content: "192.0.2.1", Proxied: true}, nil, false},
+		{"mixed proxy", "", api.UpdateDNSRecordParams{Type: "A", Name: "www.example.com", Content: "192.0.2.2"}, []string{"a1"}, false},
+		{"shadowed by NS", "", api.UpdateDNSRecordParams{Type: "TXT", Name: "sub", Content: "hello"}, []string{"ns1"}, false},
+		{"no clash", "", api.UpdateDNSRecordParams{Type: "A", Name: "api.example.com", Content: "192.0.2.1"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := findConflicts("example.com", tt.recordID, tt.params, zone)
+			var ids []string
+			for _, c := range conflicts {
+				ids = append(ids, c.record.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("expected conflicts %v, got %v", tt.want, ids)
+			}
+			if hasBlockingConflict(conflicts) != tt.blocking {
+				t.Errorf("expected blocking=%v", tt.blocking)
+			}
+		})
+	}
+}
+
+func TestEditModel_ConflictBlocksSave(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 80, 24)
+	m.zoneRecords = []api.DNSRecord{{ID: "c1", Type: "CNAME", Name: "blog.example.com", Content: "host.example.net", TTL: 1}}
+	m.nameInput.SetValue("blog")
+	m.contentInput.SetValue("192.0.2.1")
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Fatal("expected conflicting record to be refused")
+	}
+	if len(m.Conflicts()) != 1 {
+		t.Fatalf("expected one conflict, got %d", len(m.Conflicts()))
+	}
+	view := m.View()
+	if !strings.Contains(view, "already holds a CNAME") || !strings.Contains(view, "blog.example.com.") {
+		t.Error("expected view to list the conflicting CNAME")
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected blocking conflict to refuse repeated submits")
+	}
+}
+
+func TestEditModel_ConflictWarningNeedsConfirmation(t *testing.T) {
+	rec := newTestRecord()
+	m := NewEditModel(nil, "zone-1", "example.com", rec, 80, 24)
+	m.zoneRecords = []api.DNSRecord{rec, {ID: "rec-2", Type: "A", Name: "example.com", Content: "192.0.2.2", TTL: 1, Proxied: true}}
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Fatal("expected first submit to stop for confirmation")
+	}
+	if !strings.Contains(m.View(), "Press Enter again") {
+		t.Error("expected confirmation prompt in view")
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected second submit to proceed")
+	}
+	if _, ok := cmd().(submitEditMsg); !ok {
+		t.Error("expected submitEditMsg after confirmation")
+	}
+}
+
+func TestRecordChanged(t *testing.T) {
+	base := newTestRecord()
+	if recordChanged(base, base) {
+		t.Error("expected identical records to be unchanged")
+	}
+	other := base
+	other.Content = "192.0.2.2"
+	if !recordChanged(base, other) {
+		t.Error("expected content change to be detected")
+	}
+
+	stamped := base
+	stamped.ModifiedOn = time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	later := stamped
+	later.ModifiedOn = stamped.ModifiedOn.Add(time.Minute)
+	if !recordChanged(stamped, later) {
+		t.Error("expected newer modified_on to be detected")
+	}
+}
+
+func TestMergeRecords(t *testing.T) {
+	original := newTestRecord()
+	theirs := original
+	theirs.TTL = 3600
+	theirs.Comment = "theirs"
+	mine := original
+	mine.Content = "192.0.2.50"
+	mine.Comment = "mine"
+
+	merged, clashes := mergeRecords(original, theirs, mine)
+	if merged.Content != "192.0.2.50" {
+		t.Errorf("expected my content to be kept, got %q", merged.Content)
+	}
+	if merged.TTL != 3600 {
+		t.Errorf("expected their TTL to be taken, got %d", merged.TTL)
+	}
+	if merged.Comment != "mine" {
+		t.Errorf("expected my comment to win a clash, got %q", merged.Comment)
+	}
+	if !reflect.DeepEqual(clashes, []string{"Comment"}) {
+		t.Errorf("expected Comment clash, got %v", clashes)
+	}
+}
+
+func TestEditFlow_StaleRecordThreeWay(t *testing.T) {
+	putCalls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			putCalls++
+			fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-1","type":"A","name":"example.com","content":"203.0.113.50","ttl":3600,"proxied":false}}`)
+			return
+		}
+		// Someone else raised the TTL since the form was opened.
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-1","type":"A","name":"example.com","content":"192.0.2.1","ttl":3600,"proxied":false}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	m := NewEditModel(client, "zone-1", "example.com", newTestRecord(), 120, 40)
+	m.contentInput.SetValue("203.0.113.50")
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	sub := cmd().(submitEditMsg)
+
+	msg := m.saveCmd(sub)()
+	stale, ok := msg.(staleRecordMsg)
+	if !ok {
+		t.Fatalf("expected staleRecordMsg, got %T", msg)
+	}
+	if putCalls != 0 {
+		t.Fatal("expected no update while the record is stale")
+	}
+	m, _ = m.Update(stale)
+	if !m.Stale() {
+		t.Fatal("expected three-way view")
+	}
+	view := m.View()
+	for _, want := range []string{"Original", "Theirs", "Mine", "3600", "203.0.113.50"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected three-way view to contain %q", want)
+		}
+	}
+
+	// Merge keeps my content and takes their TTL, back on the form.
+	merged, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if merged.Stale() {
+		t.Error("expected merge to leave the three-way view")
+	}
+	if merged.ContentValue() != "203.0.113.50" || merged.TTLValue() != "3600" {
+		t.Errorf("unexpected merged values: content=%q ttl=%q", merged.ContentValue(), merged.TTLValue())
+	}
+
+	// Abort returns to the form without saving.
+	aborted, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if aborted.Stale() || cmd != nil {
+		t.Error("expected abort to close the three-way view without a command")
+	}
+
+	// Overwrite re-bases on their copy and saves.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if !m.Saving() || cmd == nil {
+		t.Fatal("expected overwrite to start saving")
+	}
+	var result saveResultMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if sr, ok := c().(saveResultMsg); ok {
+			result = sr
+		}
+	}
+	if result.err != nil || putCalls != 1 {
+		t.Errorf("expected one successful update, got err=%v calls=%d", result.err, putCalls)
+	}
+}
+
+func TestEditModel_ReviewShowsDiff(t *testing.T) {
+	m := NewEditModel(nil, "zone-1", "example.com", newTestRecord(), 120, 40)
+	m.contentInput.SetValue("198.51.100.7")
+	m.ttlInput.SetValue("3600")
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+
+	view := m.View()
+	for _, want := range []string{"Review Save", "Before", "After", "192.0.2.1", "198.51.100.7", "~ Content", "~ TTL", "2 field(s) changed"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected review view to contain %q", want)
+		}
+	}
+	if strings.Contains(view, "~ Name") {
+		t.Error("expected unchanged name not to be marked")
+	}
+
+	// Esc returns to the form with the edits intact.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.Reviewing() || cmd != nil {
+		t.Error("expected Esc to leave the review without saving")
+	}
+	if m.ContentValue() != "198.51.100.7" {
+		t.Errorf("expected edits to be kept, got %q", m.ContentValue())
+	}
+}
+
+func TestCreateModel_ReviewShowsNewValues(t *testing.T) {
+	m := NewCreateModel(nil, "zone-1", "example.com", 120, 40)
+	m.nameInput.SetValue("new.example.com")
+	m.contentInput.SetValue("192.0.2.7")
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+	view := m.View()
+	if !strings.Contains(view, "Review Create") || !strings.Contains(view, "new.example.com") {
+		t.Error("expected create review to list the new record")
+	}
+}
+
+// journalModel returns a root model showing zone-1 with one record loaded.
+func journalModel(t *testing.T) Model {
+	t.Helper()
+	m := New(nil, false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	updated, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{newTestRecord()}})
+	return updated.(Model)
+}
+
+func TestModel_JournalRecordsSavesAndDeletes(t *testing.T) {
+	m := journalModel(t)
+	before := newTestRecord()
+	after := before
+	after.Content = "192.0.2.50"
+
+	updated, _ := m.Update(editRecordMsg{record: before})
+	m = updated.(Model)
+	updated, _ = m.Update(editDoneMsg{record: after, before: before})
+	m = updated.(Model)
+	updated, _ = m.Update(deleteRecordMsg{record: after})
+	m = updated.(Model)
+	updated, _ = m.Update(deleteDoneMsg{record: after})
+	m = updated.(Model)
+
+	if len(m.journal) != 2 {
+		t.Fatalf("expected 2 journal entries, got %d", len(m.journal))
+	}
+	if m.journal[0].action() != "update" || m.journal[0].before.Content != "192.0.2.1" || m.journal[0].zoneID != "zone-1" {
+		t.Errorf("unexpected update entry: %+v", m.journal[0])
+	}
+	if m.journal[1].action() != "delete" {
+		t.Errorf("expected delete entry, got %q", m.journal[1].action())
+	}
+
+	// Ctrl+R opens the journal from any screen, newest first.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated.(Model)
+	if m.currentView != ViewJournal {
+		t.Fatalf("expected ViewJournal, got %d", m.currentView)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Session changes (2)") || !strings.Contains(view, "Content: 192.0.2.1 → 192.0.2.50") {
+		t.Errorf("unexpected journal view:\n%s", view)
+	}
+	if e, _ := m.journalView.selected(); e.action() != "delete" {
+		t.Errorf("expected newest entry selected first, got %q", e.action())
+	}
+
+	// Esc returns to the screen the journal was opened from.
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	updated, _ = updated.Update(cmd())
+	if updated.(Model).currentView != ViewRecords {
+		t.Errorf("expected ViewRecords after closing the journal, got %d", updated.(Model).currentView)
+	}
+}
+
+func TestModel_JournalRevertUpdateGoesThroughReview(t *testing.T) {
+	m := journalModel(t)
+	before := newTestRecord()
+	after := before
+	after.Content = "192.0.2.50"
+	m.journal = []journalEntry{{zoneID: "zone-1", zoneName: "example.com", before: before, after: after}}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated.(Model)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(cmd())
+	m = updated.(Model)
+
+	if m.currentView != ViewEdit || !m.edit.Reviewing() {
+		t.Fatalf("expected review screen, got view %d reviewing=%v", m.currentView, m.edit.Reviewing())
+	}
+	if m.edit.pending.recordID != "rec-1" || m.edit.pending.params.Content != "192.0.2.1" {
+		t.Errorf("expected pending revert to the before snapshot, got %+v", m.edit.pending)
+	}
+	if m.edit.record.Content != "192.0.2.50" {
+		t.Errorf("expected the after snapshot as the base, got %q", m.edit.record.Content)
+	}
+	view := m.View()
+	if !strings.Contains(view, "192.0.2.50") || !strings.Contains(view, "~ Content") {
+		t.Error("expected review diff from after back to before")
+	}
+
+	// Backing out of the revert returns to the journal.
+	updated, _ = m.Update(cancelEditMsg{})
+	if updated.(Model).currentView != ViewJournal {
+		t.Errorf("expected ViewJournal after cancelling a revert, got %d", updated.(Model).currentView)
+	}
+}
+
+func TestModel_JournalRevertCreateAndDelete(t *testing.T) {
+	m := journalModel(t)
+	rec := newTestRecord()
+	m.journal = []journalEntry{{zoneID: "zone-1", zoneName: "example.com", after: rec}}
+
+	updated, _ := m.revert(m.journal[0])
+	if got := updated.(Model); got.currentView != ViewDelete || got.delete.record.ID != "rec-1" {
+		t.Errorf("expected delete modal for reverting a create, got view %d", got.currentView)
+	}
+
+	updated, _ = m.revert(journalEntry{zoneID: "zone-1", zoneName: "example.com", before: rec})
+	got := updated.(Model)
+	if got.currentView != ViewEdit || !got.edit.creating || !got.edit.Reviewing() {
+		t.Fatal("expected create review for reverting a delete")
+	}
+	if got.edit.pending.recordID != "" || got.edit.pending.params.Content != "192.0.2.1" {
+		t.Errorf("unexpected pending re-create: %+v", got.edit.pending)
+	}
+}
+
+func filterTestRecords() RecordsModel {
+	m := NewRecordsModel(nil, api.Zone{ID: "zone-1", Name: "example.com"}, 160, 30, false)
+	m, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1},
+		{ID: "r2", Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 1},
+		{ID: "r3", Type: "TXT", Name: "Mail.example.com", Content: "v=spf1 -all", TTL: 1},
+		{ID: "r4", Type: "A", Name: "api.example.com", Content: "10.0.0.4", TTL: 1},
+	}})
+	return m
+}
+
+func typeKeys(m RecordsModel, s string) RecordsModel {
+	for _, r := range s {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestRecordsModel_FilterMapsSelectionToRecord(t *testing.T) {
+	m := filterTestRecords()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !m.filtering {
+		t.Fatal("expected / to open the filter bar")
+	}
+	m = typeKeys(m, "mail")
+	if got := len(m.table.Rows()); got != 2 {
+		t.Fatalf("expected 2 matching rows, got %d", got)
+	}
+	if !strings.Contains(m.View(), "2 of 4") {
+		t.Error("expected match count in view")
+	}
+
+	// Enter leaves the filter bar; the second row must map to r3, not records[1].
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected edit command")
+	}
+	if msg := cmd().(editRecordMsg); msg.record.ID != "r3" {
+		t.Errorf("expected filtered row to map to r3, got %s", msg.record.ID)
+	}
+
+	// Esc clears the filter before it navigates back, keeping the selection.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil {
+		t.Error("expected Esc to clear the filter rather than go back")
+	}
+	if len(m.table.Rows()) != 4 {
+		t.Errorf("expected all rows after clearing, got %d", len(m.table.Rows()))
+	}
+	if r, _ := m.selectedRecord(); r.ID != "r3" {
+		t.Errorf("expected cursor to stay on r3, got %s", r.ID)
+	}
+}
+
+func TestRecordsModel_FilterCaseAndRegex(t *testing.T) {
+	m := filterTestRecords()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = typeKeys(m, "Mail")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if got := len(m.table.Rows()); got != 1 {
+		t.Errorf("expected 1 case-sensitive match, got %d", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+
+	m.filterInput.SetValue("")
+	m = typeKeys(m, `^10\.`)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if rows := m.table.Rows(); len(rows) != 1 || rows[0][1] != "api.example.com" {
+		t.Errorf("expected regex to match api.example.com only, got %v", rows)
+	}
+
+	m = typeKeys(m, "(")
+	if !strings.Contains(m.View(), "invalid regex") {
+		t.Error("expected invalid regex to be reported")
+	}
+	if len(m.table.Rows()) != 0 {
+		t.Error("expected no rows for an invalid regex")
+	}
+}
+
+func TestRecordsModel_SortByNameGroupsSubdomains(t *testing.T) {
+	m := NewRecordsModel(nil, api.Zone{ID: "zone-1", Name: "example.com"}, 160, 30, false)
+	records := []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 300},
+		{ID: "r2", Type: "A", Name: "a.dev.example.com", Content: "192.0.2.2", TTL: 1},
+		{ID: "r3", Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 3600},
+		{ID: "r4", Type: "A", Name: "dev.example.com", Content: "192.0.2.4", TTL: 60},
+	}
+	m, _ = m.Update(recordsLoadedMsg{records: records})
+
+	// s cycles Type → Name.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.sortBy != sortName {
+		t.Fatalf("expected sort by name, got %d", m.sortBy)
+	}
+	var names []string
+	for _, r := range m.table.Rows() {
+		names = append(names, r[1])
+	}
+	want := []string{"example.com", "dev.example.com", "a.dev.example.com", "www.example.com"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+	if title := m.table.Columns()[1].Title; title != "Name ▲" {
+		t.Errorf("expected sort indicator on Name, got %q", title)
+	}
+
+	// S reverses the order.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	if first := m.table.Rows()[0][1]; first != "www.example.com" {
+		t.Errorf("expected descending order to start with www, got %q", first)
+	}
+	if title := m.table.Columns()[1].Title; title != "Name ▼" {
+		t.Errorf("expected descending indicator, got %q", title)
+	}
+}
+
+func TestRecordsModel_SortSurvivesRefresh(t *testing.T) {
+	m := NewRecordsModel(nil, api.Zone{ID: "zone-1", Name: "example.com"}, 160, 30, false)
+	records := []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "b.example.com", Content: "192.0.2.1", TTL: 300},
+		{ID: "r2", Type: "A", Name: "a.example.com", Content: "192.0.2.2", TTL: 60},
+		{ID: "r3", Type: "A", Name: "c.example.com", Content: "192.0.2.3", TTL: 3600},
+	}
+	m, _ = m.Update(recordsLoadedMsg{records: records})
+	for m.sortBy != sortTTL {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	}
+	m.selectRecord("r1")
+
+	// A refresh (as after editDoneMsg) returns the records in API order with
+	// r1's TTL changed; the sort holds and the cursor follows r1.
+	refreshed := append([]api.DNSRecord{}, records...)
+	refreshed[0].TTL = 7200
+	m, _ = m.Update(recordsLoadedMsg{records: refreshed})
+	if rows := m.table.Rows(); rows[2][1] != "b.example.com" {
+		t.Errorf("expected r1 last after TTL change, got %v", rows)
+	}
+	if r, _ := m.selectedRecord(); r.ID != "r1" {
+		t.Errorf("expected cursor to stay on r1, got %s", r.ID)
+	}
+}
+
+func TestLayoutColumns_FillsWidth(t *testing.T) {
+	for _, width := range []int{80, 120, 200} {
+		columns, _ := layoutColumns(width, nil)
+		total := 0
+		for _, c := range columns {
+			total += c.Width + cellPadding
+		}
+		if total != width {
+			t.Errorf("width %d: columns take %d", width, total)
+		}
+	}
+
+	// Too narrow: flexible columns keep their minimum.
+	columns, _ := layoutColumns(40, nil)
+	if columns[1].Width != 12 || columns[2].Width != 12 {
+		t.Errorf("expected minimum widths, got %d and %d", columns[1].Width, columns[2].Width)
+	}
+
+	columns, idx := layoutColumns(100, map[string]bool{"Modified": true, "Proxied": true})
+	if len(columns) != 4 || !reflect.DeepEqual(idx, []int{0, 1, 2, 3}) {
+		t.Errorf("expected hidden columns to be skipped, got %v", idx)
+	}
+}
+
+func TestRecordsModel_ResizeAndTruncate(t *testing.T) {
+	m := NewRecordsModel(nil, api.Zone{ID: "zone-1", Name: "example.com"}, 80, 24, false)
+	long := strings.Repeat("x", 200)
+	m, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{{ID: "r1", Type: "TXT", Name: "example.com", Content: long, TTL: 1}}})
+	if w := m.table.Columns()[2].Width; w >= 80 {
+		t.Fatalf("expected Content column to fit an 80-column terminal, got %d", w)
+	}
+	if !strings.Contains(m.View(), "…") {
+		t.Error("expected long content to be cut with an ellipsis")
+	}
+
+	narrow := m.table.Columns()[2].Width
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 160, Height: 24})
+	if m.table.Columns()[2].Width <= narrow {
+		t.Error("expected Content column to grow on a wider terminal")
+	}
+}
+
+func TestRecordsModel_ColumnPickerAndDetailPane(t *testing.T) {
+	m := NewRecordsModel(nil, api.Zone{ID: "zone-1", Name: "example.com"}, 160, 30, false)
+	m, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Comment: "web frontend"}}})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if !strings.Contains(m.View(), "[x] Modified") {
+		t.Error("expected column picker to list columns")
+	}
+	for m.columnCursor != int(sortModified)-1 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected closing the picker to report the column choice")
+	}
+	if msg := cmd().(columnsChangedMsg); !reflect.DeepEqual(msg.hidden, []string{"Modified"}) {
+		t.Errorf("expected Modified hidden, got %v", msg.hidden)
+	}
+	for _, c := range m.table.Columns() {
+		if c.Title == "Modified" {
+			t.Error("expected Modified column to be hidden")
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if !m.showsDetailPane() || !strings.Contains(m.View(), "web frontend") {
+		t.Error("expected detail pane with the selected record on a wide terminal")
+	}
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if m.showsDetailPane() {
+		t.Error("expected detail pane to hide on a narrow terminal")
+	}
+}
+
+func TestModel_ColumnChoicesPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	m := New(nil, false).WithPrefs(path, prefs.Prefs{HiddenColumns: []string{"TTL"}})
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	if !m.records.hidden["TTL"] {
+		t.Fatal("expected saved hidden columns to apply to the records view")
+	}
+
+	_, cmd := m.Update(columnsChangedMsg{hidden: []string{"Proxied"}, detailPane: true})
+	if cmd == nil {
+		t.Fatal("expected a save command")
+	}
+	if msg := cmd().(prefsSavedMsg); msg.err != nil {
+		t.Fatalf("save failed: %v", msg.err)
+	}
+	saved, err := prefs.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved, prefs.Prefs{HiddenColumns: []string{"Proxied"}, DetailPane: true}) {
+		t.Errorf("unexpected saved prefs: %+v", saved)
+	}
+}
+
+func TestModel_DetailViewInReadOnly(t *testing.T) {
+	m := New(nil, true)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 60})
+	m = updated.(Model)
+	updated, _ = m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	record := api.DNSRecord{ID: "rec-1", Type: "TXT", Name: "example.com", Content: strings.Repeat("k", 300), TTL: 1, Comment: "dkim key", Tags: []string{"mail"}}
+	updated, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{record}})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected i to open the detail view in read-only mode")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.currentView != ViewDetail {
+		t.Fatalf("expected ViewDetail, got %d", m.currentView)
+	}
+
+	view := m.View()
+	for _, want := range []string{"rec-1", "dkim key", "mail", "Auto", "2 (300 bytes total)", "#1, 255 bytes", "#2, 45 bytes"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected detail view to contain %q", want)
+		}
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.currentView != ViewRecords {
+		t.Errorf("expected Esc to return to ViewRecords, got %d", m.currentView)
+	}
+}
+
+func TestEncodeTXT(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"v=spf1 -all", "v=spf1 -all"},
+		{"part one\npart two\n", `"part one" "part two"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{" padded", `" padded"`},
+		{strings.Repeat("a", 300), `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`},
+	}
+	for _, tt := range tests {
+		if got := encodeTXT(txtEditorStrings(tt.text)); got != tt.want {
+			t.Errorf("encodeTXT(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	if got := txtEditorText(`"a b" "c\"d"`); got != "a b\nc\"d" {
+		t.Errorf("expected quoted strings one per line, got %q", got)
+	}
+}
+
+func TestEditModel_TXTEditor(t *testing.T) {
+	rec := api.DNSRecord{ID: "rec-1", Type: "TXT", Name: "sel._domainkey.example.com", Content: `"v=DKIM1; k=rsa; " "p=MIIB"`, TTL: 1}
+	m := NewEditModel(nil, "zone-1", "example.com", rec, 100, 40)
+	for m.Focused() != fieldContent {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	if got := m.txtInput.Value(); got != "v=DKIM1; k=rsa; \np=MIIB" {
+		t.Fatalf("expected one string per line, got %q", got)
+	}
+
+	// Enter starts a new string rather than submitting.
+	m.txtInput.CursorEnd()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(strings.Repeat("Q", 260))})
+	if got := m.ContentValue(); got != `"v=DKIM1; k=rsa; " "p=MIIB" "`+strings.Repeat("Q", 255)+`" "QQQQQ"` {
+		t.Errorf("unexpected content %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "4 strings: 16 + 6 + 255 + 5 bytes") {
+		t.Errorf("expected chunk boundaries in view, got:\n%s", view)
+	}
+
+	// Content over Cloudflare's limit is rejected before saving.
+	m.txtInput.SetValue(strings.Repeat("x", 2040))
+	for m.Focused() != fieldSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || !strings.Contains(m.Errors()[fieldContent], "2048") {
+		t.Errorf("expected over-long TXT content to be rejected, got %q", m.Errors()[fieldContent])
+	}
+}
+
+func TestBulkEdit_ApplyAndCheck(t *testing.T) {
+	ttl, on := 300, true
+	b := bulkEdit{ttl: &ttl}
+	rec := api.DNSRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Comment: "keep me", Tags: []string{"env:prod"}}
+	p := b.apply(rec)
+	want := rec.Params()
+	want.TTL = 300
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("expected only TTL to change, got %+v", p)
+	}
+	if msg := b.check(rec); msg != "" {
+		t.Errorf("expected TTL change to be valid, got %q", msg)
+	}
+
+	b = bulkEdit{proxied: &on}
+	if msg := b.check(api.DNSRecord{Type: "TXT", TTL: 1}); !strings.Contains(msg, "proxied") {
+		t.Errorf("expected proxying a TXT record to be rejected, got %q", msg)
+	}
+	if msg := b.check(api.DNSRecord{Type: "A", Content: "192.0.2.1", TTL: 300}); !strings.Contains(msg, "Auto") {
+		t.Errorf("expected proxying a record with a fixed TTL to be rejected, got %q", msg)
+	}
+	content := "203.0.113.9"
+	b = bulkEdit{content: &content}
+	if msg := b.check(newTestSRVRecord()); !strings.Contains(msg, "cannot be bulk-edited") {
+		t.Errorf("expected new content for an SRV record to be rejected, got %q", msg)
+	}
+}
+
+func TestBulkModel_ProxiedSetsAutoTTL(t *testing.T) {
+	on := true
+	m := NewBulkModel(nil, "zone-1", "example.com", []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "a.example.com", Content: "192.0.2.1", TTL: 300},
+	}, 120, 40)
+	m.proxied = &on
+	b, err := m.edit()
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if b.ttl == nil || *b.ttl != 1 {
+		t.Fatalf("expected turning proxying on to set an Auto TTL, got %v", b.ttl)
+	}
+	if msg := b.check(m.items[0].record); msg != "" {
+		t.Errorf("expected the record with a fixed TTL to become proxiable, got %q", msg)
+	}
+
+	// A TTL given in the form is kept, and checked as usual.
+	m.ttlInput.SetValue("300")
+	if b, _ = m.edit(); b.ttl == nil || *b.ttl != 300 {
+		t.Errorf("expected the given TTL to be kept, got %v", b.ttl)
+	}
+}
+
+func TestRecordsModel_MultiSelect(t *testing.T) {
+	m := NewRecordsModel(nil, api.Zone{ID: "zone-1", Name: "example.com"}, 120, 30, false)
+	m, _ = m.Update(recordsLoadedMsg{records: []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "a.example.com", Content: "192.0.2.1", TTL: 1},
+		{ID: "r2", Type: "A", Name: "b.example.com", Content: "192.0.2.2", TTL: 1},
+		{ID: "r3", Type: "TXT", Name: "example.com", Content: "v=spf1 -all", TTL: 1},
+	}})
+
+	// Space marks the row and moves down.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	if !m.marked["r1"] || m.selectedID() != "r2" {
+		t.Fatalf("expected r1 marked and cursor on r2, got %v / %s", m.marked, m.selectedID())
+	}
+	if row := m.table.Rows()[0]; row[0] != "● A" {
+		t.Errorf("expected marker on the first cell, got %q", row[0])
+	}
+
+	// a selects every record the filter shows.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	for _, r := range "192.0.2" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m.clearFilter()
+	var ids []string
+	for _, r := range m.markedRecords() {
+		ids = append(ids, r.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"r1", "r2"}) {
+		t.Errorf("expected filtered records selected, got %v", ids)
+	}
+	if !strings.Contains(m.View(), "(2 selected)") {
+		t.Error("expected selection count in header")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if cmd == nil {
+		t.Fatal("expected b to open the bulk edit")
+	}
+	if msg := cmd().(bulkEditMsg); len(msg.records) != 2 {
+		t.Errorf("expected 2 records for bulk edit, got %d", len(msg.records))
+	}
+
+	// Esc clears the selection before leaving the view.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil || len(m.markedRecords()) != 0 {
+		t.Error("expected Esc to clear the selection")
+	}
+}
+
+func TestModel_BulkEditSendsOneBatch(t *testing.T) {
+	var patches []any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		patches, _ = body["patches"].([]any)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"patches":[
+			{"id":"r1","type":"A","name":"a.example.com","content":"192.0.2.1","ttl":1,"proxied":true},
+			{"id":"r2","type":"A","name":"b.example.com","content":"192.0.2.2","ttl":1,"proxied":true}
+		]}}`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[],"result_info":{"page":1,"per_page":20,"total_count":0,"total_pages":1}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	m := New(api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL), false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	updated, _ = m.Update(bulkEditMsg{records: []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "a.example.com", Content: "192.0.2.1", TTL: 1},
+		{ID: "r2", Type: "A", Name: "b.example.com", Content: "192.0.2.2", TTL: 1},
+		{ID: "r3", Type: "TXT", Name: "example.com", Content: "v=spf1 -all", TTL: 1},
+	}})
+	m = updated.(Model)
+	if m.currentView != ViewBulk {
+		t.Fatalf("expected ViewBulk, got %d", m.currentView)
+	}
+
+	// Turn proxying on and apply.
+	m.bulk = applyBulkProxied(m.bulk)
+	if !m.bulk.done {
+		t.Fatal("expected the bulk edit to finish")
+	}
+	if len(patches) != 2 {
+		t.Errorf("expected the two valid records in one batch, got %v", patches)
+	}
+	view := m.bulk.View()
+	for _, want := range []string{"2 updated, 1 failed", "Only A, AAAA and CNAME"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected bulk view to contain %q", want)
+		}
+	}
+
+	updated, _ = m.Update(m.bulk.finish()())
+	m = updated.(Model)
+	if m.currentView != ViewRecords || len(m.journal) != 2 || !m.journal[0].after.Proxied {
+		t.Errorf("expected two journal entries and the records view, got %d entries on view %d", len(m.journal), m.currentView)
+	}
+}
+
+func TestModel_BulkEditRejectedBatchChangesNothing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":9000,"message":"origin rejected","source":{"pointer":"/patches/1/proxied"}}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	m := NewBulkModel(client, "zone-1", "example.com", []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "a.example.com", TTL: 1},
+		{ID: "r2", Type: "CNAME", Name: "b.example.com", TTL: 1},
+	}, 120, 40)
+	m = applyBulkProxied(m)
+
+	if updated, failed := m.counts(); updated != 0 || failed != 2 {
+		t.Errorf("expected both records failed, got %d updated, %d failed", updated, failed)
+	}
+	// The error names the second patch, so it goes to r2 alone.
+	if got := m.items[1].err; got != "origin rejected" {
+		t.Errorf("r2 error = %q, want the message Cloudflare gave for it", got)
+	}
+	if got := m.items[0].err; got != "not applied: batch rejected" {
+		t.Errorf("r1 error = %q, want it reported as not applied", got)
+	}
+}
+
+// applyBulkProxied turns proxying on in the bulk form, applies it and feeds
+// the batch result back into m.
+func applyBulkProxied(m BulkModel) BulkModel {
+	for m.focused != bulkProxied {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	for m.focused != bulkSubmit {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		return m
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c == nil {
+				continue
+			}
+			if msg, ok := c().(bulkBatchMsg); ok {
+				m, _ = m.Update(msg)
+			}
+		}
+	}
+	return m
+}
+
+func TestModel_StagingEditGoesToPending(t *testing.T) {
+	m := journalModel(t)
+	updated, _ := m.Update(toggleStagingMsg{})
+	m = updated.(Model)
+
+	stage := func(m Model, content, ttl string) Model {
+		t.Helper()
+		updated, _ := m.Update(editRecordMsg{record: newTestRecord()})
+		m = updated.(Model)
+		m.edit.contentInput.SetValue(content)
+		m.edit.ttlInput.SetValue(ttl)
+		for m.edit.Focused() != fieldSubmit {
+			m.edit, _ = m.edit.Update(tea.KeyMsg{Type: tea.KeyTab})
+		}
+		_, cmd := m.edit.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m.edit, _ = m.edit.Update(cmd())
+		if !strings.Contains(m.edit.View(), "Review Stage") {
+			t.Fatal("expected the review to offer staging")
+		}
+		_, cmd = m.edit.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msg, ok := cmd().(stageEditMsg)
+		if !ok {
+			t.Fatal("expected the review to stage the edit instead of saving it")
+		}
+		updated, _ = m.Update(msg)
+		return updated.(Model)
+	}
+
+	m = stage(m, "198.51.100.7", "300")
+	if m.currentView != ViewRecords || len(m.staged) != 1 {
+		t.Fatalf("expected one staged change on the records view, got %d on view %d", len(m.staged), m.currentView)
+	}
+	if !strings.Contains(m.records.rowMarker(newTestRecord()), "~") {
+		t.Error("expected the staged row to be marked")
+	}
+	if !strings.Contains(m.records.View(), "STAGING: 1 pending") {
+		t.Error("expected the header to show the pending count")
+	}
+
+	// Editing the record again continues from the staged values and replaces
+	// the change, keeping the record as originally loaded.
+	updated, _ = m.Update(editRecordMsg{record: newTestRecord()})
+	if got := updated.(Model).edit.ContentValue(); got != "198.51.100.7" {
+		t.Errorf("expected the form to start from the staged content, got %q", got)
+	}
+	m = stage(m, "198.51.100.7", "3600")
+	if len(m.staged) != 1 || m.staged[0].before.Content != "192.0.2.1" || m.staged[0].params.TTL != 3600 {
+		t.Errorf("expected the staged change to be replaced, got %+v", m.staged)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+	if m.currentView != ViewPending {
+		t.Fatalf("expected ViewPending, got %d", m.currentView)
+	}
+	view := m.View()
+	for _, want := range []string{"Pending changes (1)", "update", "192.0.2.1 → 198.51.100.7"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected pending view to contain %q", want)
+		}
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if len(m.staged) != 0 || m.records.rowMarker(newTestRecord()) != "" {
+		t.Error("expected the dropped change to be removed and unmarked")
+	}
+}
+
+func TestModel_JournalFromPendingReturnsToRecords(t *testing.T) {
+	m := journalModel(t)
+
+	// Records → Ctrl+P → Ctrl+R → Esc → Esc ends back on the records view.
+	want := []View{ViewPending, ViewJournal, ViewPending, ViewRecords}
+	for i, key := range []tea.KeyMsg{{Type: tea.KeyCtrlP}, {Type: tea.KeyCtrlR}, {Type: tea.KeyEsc}, {Type: tea.KeyEsc}} {
+		updated, cmd := m.Update(key)
+		if cmd != nil {
+			if msg := cmd(); msg != nil {
+				updated, _ = updated.Update(msg)
+			}
+		}
+		m = updated.(Model)
+		if m.currentView != want[i] {
+			t.Fatalf("after key %d: expected view %d, got %d", i+1, want[i], m.currentView)
+		}
+	}
+}
+
+func TestModel_ApplyStagedReportsEachChange(t *testing.T) {
+	var body map[string][]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{
+			"puts":[{"id":"rec-1","type":"A","name":"example.com","content":"198.51.100.7","ttl":300}],
+			"posts":[{"id":"rec-9","type":"A","name":"new.example.com","content":"192.0.2.9","ttl":1}]
+		}}`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-1","type":"A","name":"example.com","content":"192.0.2.1","ttl":300}}`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"rec-2","type":"A","name":"b.example.com","content":"203.0.113.5","ttl":300}}`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":[],"result_info":{"page":1,"per_page":20,"total_count":0,"total_pages":1}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	m := New(api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL), false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	stale := api.DNSRecord{ID: "rec-2", Type: "A", Name: "b.example.com", Content: "192.0.2.2", TTL: 300}
+	for _, c := range []stagedChange{
+		{before: newTestRecord(), params: api.UpdateDNSRecordParams{Type: "A", Name: "example.com", Content: "198.51.100.7", TTL: 300}},
+		{before: stale, params: api.UpdateDNSRecordParams{Type: "A", Name: "b.example.com", Content: "198.51.100.8", TTL: 300}},
+		{params: api.UpdateDNSRecordParams{Type: "A", Name: "new.example.com", Content: "192.0.2.9", TTL: 1}},
+	} {
+		c.zoneID, c.zoneName = "zone-1", "example.com"
+		updated, _ = m.Update(stageEditMsg{change: c})
+		m = updated.(Model)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(Model)
+	if !m.pending.Applying() {
+		t.Fatal("expected a to start applying")
+	}
+	updated, _ = m.Update(m.pending.applyCmd()())
+	m = updated.(Model)
+
+	if len(body["puts"]) != 1 || len(body["posts"]) != 1 {
+		t.Errorf("expected one put and one post in the batch, got %v", body)
+	}
+	if len(m.staged) != 1 || m.staged[0].before.ID != "rec-2" {
+		t.Errorf("expected only the stale change to stay pending, got %+v", m.staged)
+	}
+	if len(m.journal) != 2 || m.journal[1].after.ID != "rec-9" {
+		t.Errorf("expected the update and create journalled, got %+v", m.journal)
+	}
+	view := m.View()
+	for _, want := range []string{"Last apply", "✓ update A example.com", "✓ create A new.example.com", "✗ update A b.example.com: changed on Cloudflare"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected pending view to contain %q", want)
+		}
+	}
+}
+
+func TestApplyStagedRejectedBatch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":81057,"message":"An identical record already exists.","source":{"pointer":"/posts/1"}}],"messages":[],"result":null}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	results := applyStaged(context.Background(), client, []stagedChange{
+		{zoneID: "zone-1", params: api.UpdateDNSRecordParams{Type: "A", Name: "a.example.com", Content: "192.0.2.1", TTL: 1}},
+		{zoneID: "zone-1", params: api.UpdateDNSRecordParams{Type: "A", Name: "b.example.com", Content: "192.0.2.2", TTL: 1}},
+	})
+	if err := results[1].err; err == nil || err.Error() != "An identical record already exists." {
+		t.Errorf("second create error = %v, want the message Cloudflare gave for it", err)
+	}
+	if err := results[0].err; err == nil || !strings.Contains(err.Error(), "applying 2 DNS record changes") {
+		t.Errorf("first create error = %v, want the batch error", err)
+	}
+}
+
+func TestContentReplacer(t *testing.T) {
+	tests := []struct {
+		name     string
+		replacer contentReplacer
+		in, want string
+		wantErr  bool
+	}{
+		{"literal", contentReplacer{find: "203.0.113.10", with: "198.51.100.10"}, "203.0.113.10", "198.51.100.10", false},
+		{"literal dots are not wildcards", contentReplacer{find: "203.0.113.10", with: "x"}, "203x0x113x10", "203x0x113x10", false},
+		{"literal in text", contentReplacer{find: "old.example.net", with: "new.example.net"}, "v=spf1 include:old.example.net -all", "v=spf1 include:new.example.net -all", false},
+		{"regex with group", contentReplacer{find: `^10\.0\.(\d+)\.(\d+)$`, with: "10.1.$1.$2", regex: true}, "10.0.4.7", "10.1.4.7", false},
+		{"invalid regex", contentReplacer{find: "(", regex: true}, "", "", true},
+		{"empty search", contentReplacer{with: "x"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replace, err := tt.replacer.compile()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compile error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got := replace(tt.in); got != tt.want {
+					t.Errorf("replace(%q) = %q, want %q", tt.in, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFindReplacements(t *testing.T) {
+	records := []api.DNSRecord{
+		{ID: "r1", Type: "A", Name: "a.example.com", Content: "203.0.113.10", TTL: 1},
+		{ID: "r2", Type: "A", Name: "b.example.com", Content: "192.0.2.1", TTL: 1},
+		{ID: "r3", Type: "AAAA", Name: "c.example.com", Content: "2001:db8::203.0.113.10", TTL: 1},
+		{ID: "r4", Type: "SRV", Name: "_sip._tcp.example.com", Content: "10 5060 203.0.113.10", TTL: 1},
+	}
+	replace, _ := contentReplacer{find: "203.0.113.10", with: "198.51.100.10"}.compile()
+	matches := findReplacements(api.Zone{ID: "zone-1", Name: "example.com"}, records, replace)
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 matches, got %d", len(matches))
+	}
+	if !matches[0].selected || matches[0].content != "198.51.100.10" {
+		t.Errorf("expected the A record ticked with the new address, got %+v", matches[0])
+	}
+	if !matches[1].selected || matches[1].err != "" {
+		t.Errorf("expected the AAAA record with an embedded address ticked, got %+v", matches[1])
+	}
+	if matches[2].selected || !strings.Contains(matches[2].err, "SRV") {
+		t.Errorf("expected the SRV record to be rejected, got %+v", matches[2])
+	}
+}
+
+// writeList writes a one-page list response; the auto-pager's request for
+// a later page gets an empty result.
+func writeList(w http.ResponseWriter, r *http.Request, result string) {
+	w.Header().Set("Content-Type", "application/json")
+	if p := r.URL.Query().Get("page"); p != "" && p != "1" {
+		result = "[]"
+	}
+	fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":%s,"result_info":{"page":1,"per_page":100,"total_count":1,"total_pages":1}}`, result)
+}
+
+func TestModel_FindReplaceAcrossZones(t *testing.T) {
+	batches := make(map[string][]any)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"zone-1","name":"example.com"},{"id":"zone-2","name":"example.org"},{"id":"zone-3","name":"example.net"}]`)
+	})
+	mux.HandleFunc("/zones/zone-3/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"r1","type":"A","name":"example.com","content":"203.0.113.10","ttl":1},
+			{"id":"r2","type":"A","name":"www.example.com","content":"192.0.2.1","ttl":1}]`)
+	})
+	mux.HandleFunc("/zones/zone-2/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"r3","type":"A","name":"example.org","content":"203.0.113.10","ttl":1},
+			{"id":"r4","type":"A","name":"mail.example.org","content":"203.0.113.10","ttl":1}]`)
+	})
+	for _, zone := range []string{"zone-1", "zone-2"} {
+		mux.HandleFunc("/zones/"+zone+"/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+			var body map[string][]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decoding request body: %v", err)
+			}
+			batches[zone] = body["patches"]
+			var out []string
+			for _, p := range body["patches"] {
+				patch := p.(map[string]any)
+				out = append(out, fmt.Sprintf(`{"id":%q,"type":"A","name":"x","content":%q,"ttl":1}`, patch["id"], patch["content"]))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":{"patches":[%s]}}`, strings.Join(out, ","))
+		})
+	}
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	m := New(api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL), false)
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	updated, _ = m.Update(findReplaceMsg{})
+	m = updated.(Model)
+	if m.currentView != ViewReplace {
+		t.Fatalf("expected ViewReplace, got %d", m.currentView)
+	}
+
+	m.replace.findInput.SetValue("203.0.113.10")
+	m.replace.replaceInput.SetValue("198.51.100.10")
+	m.replace.allZones = true
+	m.replace, _ = m.replace.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.replace.Busy() {
+		t.Fatal("expected the search across zones to start")
+	}
+	replace, _ := contentReplacer{find: "203.0.113.10", with: "198.51.100.10"}.compile()
+	updated, _ = m.Update(m.replace.searchAll(replace)())
+	m = updated.(Model)
+	if len(m.replace.matches) != 3 || !strings.Contains(m.View(), "(example.org)") {
+		t.Fatalf("expected 3 matches tagged with their zone, got %d", len(m.replace.matches))
+	}
+	if !strings.Contains(m.View(), "Could not search example.net") {
+		t.Error("expected the zone that failed to be reported")
+	}
+
+	// Untick the mail record, then apply the rest.
+	for m.replace.matches[m.replace.cursor].record.ID != "r4" {
+		m.replace, _ = m.replace.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m.replace, _ = m.replace.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m.replace, _ = m.replace.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.replace.Busy() {
+		t.Fatal("expected Enter to apply the ticked matches")
+	}
+	updated, _ = m.Update(m.replace.send()())
+	m = updated.(Model)
+	if len(batches["zone-1"]) != 1 || len(batches["zone-2"]) != 1 {
+		t.Errorf("expected one patch per zone, got %v", batches)
+	}
+	if !strings.Contains(m.View(), "2 updated, 0 failed") {
+		t.Error("expected the outcome in the view")
+	}
+
+	updated, _ = m.Update(m.replace.finish()())
+	m = updated.(Model)
+	if m.currentView != ViewRecords || len(m.journal) != 2 || m.journal[1].after.Content != "198.51.100.10" {
+		t.Errorf("expected two journal entries and the records view, got %d entries on view %d", len(m.journal), m.currentView)
+	}
+}
+
+func TestSearchZones_BoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		writeList(w, r, `[{"id":"r1","type":"A","name":"example.com","content":"192.0.2.1","ttl":1}]`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	var zones []api.Zone
+	for i := range 10 {
+		zones = append(zones, api.Zone{ID: fmt.Sprintf("zone-%d", i), Name: fmt.Sprintf("example%d.com", i)})
+	}
+	out := make(chan zoneRecordsResult)
+	go func() {
+		searchZones(context.Background(), client, zones, 3, out)
+		close(out)
+	}()
+	seen := make(map[string]bool)
+	for res := range out {
+		if res.err != nil || len(res.records) != 1 {
+			t.Errorf("zone %s: got %d records, err %v", res.zone.Name, len(res.records), res.err)
+		}
+		seen[res.zone.ID] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected a result for each of 10 zones, got %d", len(seen))
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 requests in flight, saw %d", peak)
+	}
+}
+
+func TestModel_GlobalSearchJumpsToRecord(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"zone-1","name":"example.com"},{"id":"zone-2","name":"example.org"}]`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"r1","type":"A","name":"www.example.com","content":"192.0.2.1","ttl":1}]`)
+	})
+	mux.HandleFunc("/zones/zone-2/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"r2","type":"A","name":"www.example.org","content":"192.0.2.2","ttl":1},
+			{"id":"r3","type":"MX","name":"example.org","content":"mail.example.org","priority":10,"ttl":1},
+			{"id":"r4","type":"A","name":"mail.example.org","content":"192.0.2.3","ttl":1}]`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	m := New(api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL), false)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if m.currentView != ViewSearch {
+		t.Fatalf("expected ViewSearch, got %d", m.currentView)
+	}
+
+	m.search.input.SetValue("mail")
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(searchZonesMsg); ok {
+			updated, cmd = m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+	// The first command lists the zones until the channel is closed; the
+	// second waits for each result in turn.
+	batch := cmd().(tea.BatchMsg)
+	go batch[0]()
+	for next := batch[1]; next != nil; {
+		updated, next = m.Update(next())
+		m = updated.(Model)
+	}
+
+	if m.search.Running() || len(m.search.hits) != 2 {
+		t.Fatalf("expected the finished search to find 2 records, got %d", len(m.search.hits))
+	}
+	if !strings.Contains(m.View(), "Searched 2 zones, 2 matches") || !strings.Contains(m.View(), "example.org") {
+		t.Error("expected the results tagged with their zone")
+	}
+
+	// Enter on the A record opens its zone with the record selected.
+	for m.search.hits[m.search.table.Cursor()].record.ID != "r4" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, cmd = m.Update(cmd())
+	m = updated.(Model)
+	if m.currentView != ViewRecords || m.records.zone.ID != "zone-2" {
+		t.Fatalf("expected the records of zone-2, got view %d zone %q", m.currentView, m.records.zone.ID)
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(recordsLoadedMsg); ok {
+			updated, _ = m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+	if got := m.records.selectedID(); got != "r4" {
+		t.Errorf("expected record r4 selected, got %q", got)
+	}
+}
+
+func TestRecordsModel_QueryFilter(t *testing.T) {
+	m := filterTestRecords()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	m = typeKeys(m, `type == "A" && content ~ "^10\."`)
+	if got := len(m.table.Rows()); got != 1 {
+		t.Fatalf("expected 1 matching row, got %d", got)
+	}
+	if r, _ := m.selectedRecord(); r.ID != "r4" {
+		t.Errorf("expected r4, got %s", r.ID)
+	}
+
+	// An invalid expression highlights the offending part with its message.
+	m.filterInput.SetValue(`type == "A" && ttl < "x"`)
+	m = typeKeys(m, " ")
+	var qe *query.Error
+	if !errors.As(m.filterErr, &qe) || qe.Pos != 21 {
+		t.Fatalf("expected a query error at column 22, got %v", m.filterErr)
+	}
+	if !strings.Contains(m.View(), "expected a number") {
+		t.Error("expected the error message under the filter bar")
+	}
+
+	// Ctrl+S saves a valid expression under a name.
+	m.filterInput.SetValue(`proxied == false`)
+	m = typeKeys(m, " ")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.savePrompt.active {
+		t.Fatal("expected Ctrl+S to ask for a name")
+	}
+	m.savePrompt.input.SetValue("direct")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(queriesChangedMsg)
+	if !ok || msg.queries["direct"] != "proxied == false " {
+		t.Fatalf("expected the query saved as \"direct\", got %+v", msg)
+	}
+
+	// Ctrl+N cycles through the saved queries.
+	m.queries = map[string]string{"internal": `content ~ "^10\."`, "mail": `name ~ "(?i)^mail"`}
+	m.filterInput.SetValue("")
+	m.filter.query = ""
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if m.filterInput.Value() != `content ~ "^10\."` || len(m.table.Rows()) != 1 {
+		t.Errorf("expected the first saved query applied, got %q", m.filterInput.Value())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if m.filterInput.Value() != `name ~ "(?i)^mail"` || len(m.table.Rows()) != 1 {
+		t.Errorf("expected the second saved query applied, got %q", m.filterInput.Value())
+	}
+}
+
+func TestModel_SavedQueriesPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	m := New(nil, false).WithPrefs(path, prefs.Prefs{Queries: map[string]string{"a": `type == "A"`}})
+	updated, _ := m.Update(selectZoneMsg{zone: api.Zone{ID: "zone-1", Name: "example.com"}})
+	m = updated.(Model)
+	if m.records.queries["a"] == "" {
+		t.Fatal("expected saved queries in the records view")
+	}
+
+	queries := map[string]string{"a": `type == "A"`, "slow": "ttl > 3600"}
+	updated, _ = m.Update(queriesChangedMsg{queries: queries})
+	m = updated.(Model)
+	// The returned batch also holds the status timer, so save directly.
+	if msg := m.savePrefs()().(prefsSavedMsg); msg.err != nil {
+		t.Fatalf("save failed: %v", msg.err)
+	}
+	saved, err := prefs.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved.Queries, queries) || m.records.queries["slow"] == "" {
+		t.Errorf("unexpected saved queries: %+v", saved.Queries)
+	}
+}
+
+func TestSearchModel_QueryErrorBlocksSearch(t *testing.T) {
+	m := NewSearchModel(nil, 120, 40)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	m.input.SetValue(`ttl < `)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.Running() {
+		t.Fatal("expected an invalid query not to start a search")
+	}
+	if !strings.Contains(m.View(), "expected a number") {
+		t.Error("expected the query error in the view")
+	}
+}
+
+func TestModel_ExportZoneFileLocally(t *testing.T) {
+	m := journalModel(t)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = updated.(Model)
+	if !m.records.exportPrompt.active || m.records.exportPrompt.input.Value() != "example.com.zone" {
+		t.Fatalf("expected the export prompt with a default path, got %q", m.records.exportPrompt.input.Value())
+	}
+
+	path := filepath.Join(t.TempDir(), "out.zone")
+	m.records.exportPrompt.input.SetValue(path)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = updated.(Model)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil || m.records.exportPrompt.active {
+		t.Fatal("expected Enter to close the prompt and start the export")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if !strings.Contains(m.records.statusMsg, "rendered locally") || !strings.Contains(m.records.statusMsg, path) {
+		t.Errorf("unexpected status: %q", m.records.statusMsg)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "example.com. 300 IN A 192.0.2.1\n") {
+		t.Errorf("unexpected zone file:\n%s", b)
+	}
+}
+
+func TestModel_ExportForbiddenSuggestsLocal(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`)
+	}))
+	defer srv.Close()
+
+	m := journalModel(t)
+	m.records.client = api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = updated.(Model)
+	m.records.exportPrompt.input.SetValue(filepath.Join(t.TempDir(), "out.zone"))
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if !strings.Contains(m.records.statusMsg, "Ctrl+L") {
+		t.Errorf("expected a hint to render locally, got %q", m.records.statusMsg)
+	}
+}
+
+func TestModel_ImportZoneFile(t *testing.T) {
+	var batch struct {
+		Deletes []map[string]any `json:"deletes"`
+		Posts   []map[string]any `json:"posts"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones/zone-1/dns_records/batch", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("decoding batch body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"posts":[{"id":"rec-2","type":"A","name":"api.example.com","content":"192.0.2.7","ttl":300}]}}`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records", func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"rec-1","type":"A","name":"example.com","content":"192.0.2.1","ttl":300},
+			{"id":"rec-3","type":"A","name":"old.example.com","content":"192.0.2.9","ttl":300}]`)
+	})
+	mux.HandleFunc("/zones/zone-1/dns_records/rec-3", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unticked delete was sent: %s", r.Method)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	if err := os.WriteFile(path, []byte("@ 300 A 192.0.2.1\napi 300 A 192.0.2.7\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := journalModel(t)
+	m.client = api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	m.records.client = m.client
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = updated.(Model)
+	if !m.records.importPrompt.active {
+		t.Fatal("expected I to open the import prompt")
+	}
+	m.records.importPrompt.input.SetValue(path)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.currentView != ViewImport {
+		t.Fatalf("expected ViewImport, got %d (status %q)", m.currentView, m.records.statusMsg)
+	}
+	view := m.View()
+	if !strings.Contains(view, "1 to create, 0 to update, 1 to delete; 1 ticked") {
+		t.Errorf("unexpected plan view:\n%s", view)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.busy() {
+		t.Error("expected the import to be in flight")
+	}
+	var applied tea.Msg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(importAppliedMsg); ok {
+			applied = msg
+		}
+	}
+	updated, _ = m.Update(applied)
+	m = updated.(Model)
+
+	if len(batch.Posts) != 1 || batch.Posts[0]["name"] != "api.example.com" || batch.Posts[0]["content"] != "192.0.2.7" || len(batch.Deletes) != 0 {
+		t.Errorf("unexpected batch body: %+v", batch)
+	}
+	if len(m.journal) != 1 || m.journal[0].action() != "create" || m.journal[0].after.ID != "rec-2" {
+		t.Errorf("expected the create to be journalled, got %+v", m.journal)
+	}
+	if !strings.Contains(m.View(), "1 applied, 0 failed") {
+		t.Errorf("unexpected result view:\n%s", m.View())
+	}
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	if updated.(Model).currentView != ViewRecords {
+		t.Error("expected Esc to return to the records view")
+	}
+}
+
+func TestModel_ApplyStateFileTicksDeletes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeList(w, r, `[{"id":"rec-1","type":"A","name":"example.com","content":"192.0.2.1","ttl":300},
+			{"id":"rec-3","type":"A","name":"old.example.com","content":"192.0.2.9","ttl":300}]`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "dns.yaml")
+	src := "zone: example.com\nrecords:\n  - {name: \"@\", type: A, content: 192.0.2.1, ttl: 300}\n"
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := journalModel(t)
+	m.records.client = api.NewClientWithBaseURL(&config.Config{APIToken: "test-token"}, srv.URL)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = updated.(Model)
+	m.records.importPrompt.input.SetValue(path)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.currentView != ViewImport {
+		t.Fatalf("expected ViewImport, got %d (status %q)", m.currentView, m.records.statusMsg)
+	}
+	view := m.View()
+	for _, want := range []string{"Apply State File", "0 to create, 0 to update, 1 to delete; 1 ticked", "- delete A     old.example.com"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the plan view:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_ExportTerraformMarkedRecords(t *testing.T) {
+	m := journalModel(t)
+	other := newTestRecord()
+	other.ID, other.Name = "rec-2", "www.example.com"
+	m.records.records = append(m.records.records, other)
+	m.records.marked = map[string]bool{"rec-2": true}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = updated.(Model)
+	if !m.records.terraformPrompt.active || m.records.terraformPrompt.input.Value() != "example.com.tf" {
+		t.Fatalf("expected the Terraform prompt with a default path, got %q", m.records.terraformPrompt.input.Value())
+	}
+	if view := m.View(); !strings.Contains(view, "the 1 marked records") {
+		t.Errorf("expected the prompt to say only marked records are written:\n%s", view)
+	}
+	path := filepath.Join(t.TempDir(), "dns.tf")
+	m.records.terraformPrompt.input.SetValue(path)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if !strings.Contains(m.records.statusMsg, "1 resources written to "+path) {
+		t.Errorf("unexpected status: %q", m.records.statusMsg)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `resource "cloudflare_dns_record" "www_a"`) || strings.Contains(string(b), "apex_a") {
+		t.Errorf("expected only the marked record:\n%s", b)
+	}
+	if !strings.Contains(string(b), `id = "zone-1/rec-2"`) {
+		t.Errorf("expected an import block keyed on zone and record ID:\n%s", b)
+	}
+}
@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// stagedChange is an edit saved into the pending-changes list instead of
+// being sent. before is the record as loaded, or the zero record when the
+// change creates one.
+type stagedChange struct {
+	zoneID   string
+	zoneName string
+	before   api.DNSRecord
+	params   api.UpdateDNSRecordParams
+}
+
+// entry returns the change as a journal entry, which describes it and
+// summarises its field changes.
+func (c stagedChange) entry() journalEntry {
+	after := paramsRecord(c.before.ID, c.params)
+	if after.ID == "" {
+		after.ID = "new" // not yet created; lets record() pick the after side
+	}
+	return journalEntry{zoneID: c.zoneID, zoneName: c.zoneName, before: c.before, after: after}
+}
+
+// stageEditMsg asks the root model to add an edit to the pending changes.
+type stageEditMsg struct {
+	change stagedChange
+}
+
+// toggleStagingMsg switches staging mode on or off.
+type toggleStagingMsg struct{}
+
+// dropStagedMsg removes the pending change at index.
+type dropStagedMsg struct {
+	index int
+}
+
+// closePendingMsg signals that the user left the pending changes view.
+type closePendingMsg struct{}
+
+// stagedResult is the outcome of applying one pending change.
+type stagedResult struct {
+	change stagedChange
+	after  api.DNSRecord
+	err    error
+}
+
+// stagedAppliedMsg carries the outcome of applying every pending change, in
+// the order they were staged.
+type stagedAppliedMsg struct {
+	results []stagedResult
+}
+
+// errStagedStale reports a record changed on Cloudflare after it was staged.
+var errStagedStale = errors.New("changed on Cloudflare since it was staged")
+
+// applyStaged sends changes one zone at a time, each zone as a single batch.
+// Updates whose record changed since it was staged are left out and reported
+// instead; if a zone's batch is rejected, none of its changes is applied,
+// and each change Cloudflare found fault with gets its own message.
+func applyStaged(ctx context.Context, client *api.Client, changes []stagedChange) []stagedResult {
+	results := make([]stagedResult, len(changes))
+	var zones []string
+	byZone := make(map[string][]int)
+	for i, c := range changes {
+		results[i].change = c
+		if _, ok := byZone[c.zoneID]; !ok {
+			zones = append(zones, c.zoneID)
+		}
+		byZone[c.zoneID] = append(byZone[c.zoneID], i)
+	}
+
+	for _, zoneID := range zones {
+		var req api.BatchRequest
+		var puts, posts []int
+		for _, i := range byZone[zoneID] {
+			c := changes[i]
+			if c.before.ID == "" {
+				req.Posts = append(req.Posts, c.params)
+				posts = append(posts, i)
+				continue
+			}
+			current, err := client.GetDNSRecord(ctx, zoneID, c.before.ID)
+			if err != nil {
+				results[i].err = err
+				continue
+			}
+			if recordChanged(c.before, current) {
+				results[i].err = errStagedStale
+				continue
+			}
+			req.Puts = append(req.Puts, api.BatchPut{ID: c.before.ID, Params: c.params})
+			puts = append(puts, i)
+		}
+		if req.Len() == 0 {
+			continue
+		}
+		res, err := client.BatchDNSRecords(ctx, zoneID, req)
+		failed := api.BatchErrors(err)
+		opErr := func(list string, n int) error {
+			if text, ok := failed[api.BatchOp{List: list, Index: n}]; ok {
+				return errors.New(text)
+			}
+			return err
+		}
+		for n, i := range puts {
+			results[i].err = opErr("puts", n)
+			if err == nil && n < len(res.Puts) {
+				results[i].after = res.Puts[n]
+			}
+		}
+		for n, i := range posts {
+			results[i].err = opErr("posts", n)
+			if err == nil && n < len(res.Posts) {
+				results[i].after = res.Posts[n]
+			}
+		}
+	}
+	return results
+}
+
+// PendingModel lists the staged changes with their diffs and applies them.
+type PendingModel struct {
+	client   *api.Client
+	changes  []stagedChange
+	cursor   int
+	applying bool
+	// results holds the outcome of the last apply, shown until the view closes.
+	results []stagedResult
+	spinner spinner.Model
+	width   int
+	height  int
+}
+
+// NewPendingModel creates a pending changes view over changes.
+func NewPendingModel(client *api.Client, changes []stagedChange, width, height int) PendingModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return PendingModel{client: client, changes: changes, spinner: sp, width: width, height: height}
+}
+
+// Init is a no-op; the changes are held in memory.
+func (m PendingModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles navigation, dropping changes and applying them.
+func (m PendingModel) Update(msg tea.Msg) (PendingModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.applying {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.applying {
+			return m, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.changes)-1 {
+				m.cursor++
+			}
+		case "d", "x", "delete":
+			if m.cursor < len(m.changes) {
+				index := m.cursor
+				return m, func() tea.Msg { return dropStagedMsg{index: index} }
+			}
+		case "a":
+			if len(m.changes) > 0 {
+				m.applying = true
+				m.results = nil
+				return m, tea.Batch(m.spinner.Tick, m.applyCmd())
+			}
+		case "esc", "q":
+			return m, func() tea.Msg { return closePendingMsg{} }
+		}
+	}
+	return m, nil
+}
+
+// applyCmd sends every pending change in the background.
+func (m PendingModel) applyCmd() tea.Cmd {
+	client := m.client
+	changes := append([]stagedChange{}, m.changes...)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		return stagedAppliedMsg{results: applyStaged(ctx, client, changes)}
+	}
+}
+
+// View renders the pending changes and the outcome of the last apply.
+func (m PendingModel) View() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 0, 1, 2).
+		Render(fmt.Sprintf("Pending changes (%d)", len(m.changes)))
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	selectedStyle := rowStyle.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57"))
+	diffStyle := lipgloss.NewStyle().Faint(true).Padding(0, 0, 0, 6)
+	okStyle := rowStyle.Foreground(lipgloss.Color("42"))
+	failStyle := rowStyle.Foreground(lipgloss.Color("196"))
+
+	var lines []string
+	if len(m.changes) == 0 {
+		lines = append(lines, rowStyle.Faint(true).Render("Nothing staged. Press t in the records table to start staging edits."))
+	}
+	for i, c := range m.changes {
+		e := c.entry()
+		r := e.record()
+		line := fmt.Sprintf("%-6s  %-20s  %-5s %s", e.action(), c.zoneName, r.Type, r.Name)
+		style := rowStyle
+		if i == m.cursor {
+			style = selectedStyle
+		}
+		lines = append(lines, style.Render(truncate(sanitize(line), m.width-2)))
+		lines = append(lines, diffStyle.Render(truncate(sanitize(e.summary()), m.width-6)))
+	}
+
+	if m.applying {
+		lines = append(lines, "", rowStyle.Render(m.spinner.View()+" Applying changes…"))
+	}
+	if len(m.results) > 0 {
+		lines = append(lines, "", rowStyle.Bold(true).Render("Last apply"))
+		for _, res := range m.results {
+			r := res.change.entry().record()
+			text := fmt.Sprintf("%s %s %s", res.change.entry().action(), r.Type, r.Name)
+			if res.err != nil {
+				lines = append(lines, failStyle.Render(truncate("✗ "+sanitize(text)+": "+res.err.Error(), m.width-2)))
+				continue
+			}
+			lines = append(lines, okStyle.Render(truncate("✓ "+sanitize(text), m.width-2)))
+		}
+	}
+
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Padding(1, 0, 0, 2).
+		Render("↑/↓: navigate | d: drop change | a: apply all | q/Esc: back | Ctrl+C: quit")
+	return "\n" + header + "\n" + strings.Join(lines, "\n") + "\n" + help
+}
+
+// Applying returns whether the changes are being sent.
+func (m PendingModel) Applying() bool {
+	return m.applying
+}
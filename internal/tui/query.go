@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/query"
+)
+
+// queriesChangedMsg reports a new set of saved queries for the preferences
+// file.
+type queriesChangedMsg struct {
+	queries map[string]string
+}
+
+// queryErrorView renders src with the part an expression error points at
+// highlighted, followed by the message. Other errors are shown as is.
+func queryErrorView(src string, err error) string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	var qe *query.Error
+	if !errors.As(err, &qe) {
+		return errStyle.Render(sanitize(err.Error()))
+	}
+	pos := min(max(qe.Pos, 0), len(src))
+	end := min(max(qe.End, pos), len(src))
+	bad := src[pos:end]
+	if bad == "" {
+		bad = " " // past the end: mark where something is missing
+	}
+	badStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Background(lipgloss.Color("160"))
+	return sanitize(src[:pos]) + badStyle.Render(sanitize(bad)) + sanitize(src[end:]) +
+		"  " + errStyle.Render(sanitize(qe.Msg))
+}
+
+// nextSavedQuery returns the saved query after the one whose expression is
+// current, in name order, wrapping around. ok is false if none is saved.
+func nextSavedQuery(saved map[string]string, current string) (name, expr string, ok bool) {
+	names := slices.Sorted(maps.Keys(saved))
+	if len(names) == 0 {
+		return "", "", false
+	}
+	next := 0
+	for i, n := range names {
+		if saved[n] == current {
+			next = (i + 1) % len(names)
+			break
+		}
+	}
+	return names[next], saved[names[next]], true
+}
+
+// queryPrompt asks for the name to save an expression under.
+type queryPrompt struct {
+	input  textinput.Model
+	active bool
+	expr   string
+}
+
+// newQueryPrompt creates a closed prompt.
+func newQueryPrompt() queryPrompt {
+	in := textinput.New()
+	in.Prompt = "Save query as: "
+	in.Placeholder = "name"
+	in.CharLimit = 64
+	in.Width = 30
+	return queryPrompt{input: in}
+}
+
+// open shows the prompt for saving expr.
+func (p *queryPrompt) open(expr string) tea.Cmd {
+	p.active = true
+	p.expr = expr
+	p.input.SetValue("")
+	return p.input.Focus()
+}
+
+// update handles keys while the prompt is open. Enter saves the expression
+// into a copy of saved, replacing any query of the same name; Esc closes the
+// prompt without saving.
+func (p queryPrompt) update(msg tea.KeyMsg, saved map[string]string) (queryPrompt, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.active = false
+		p.input.Blur()
+		return p, nil
+	case "enter":
+		name := strings.TrimSpace(p.input.Value())
+		if name == "" {
+			return p, nil
+		}
+		p.active = false
+		p.input.Blur()
+		queries := maps.Clone(saved)
+		if queries == nil {
+			queries = make(map[string]string)
+		}
+		queries[name] = p.expr
+		return p, func() tea.Msg { return queriesChangedMsg{queries: queries} }
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
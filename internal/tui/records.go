@@ -4,16 +4,23 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+	"github.com/Azahorscak/cloudflare-tui/internal/query"
 )
 
+// timestampLayout is the format used to display record timestamps.
+const timestampLayout = "2006-01-02 15:04"
+
 // statusClearMsg signals that the status message should be cleared.
 type statusClearMsg struct{}
 
@@ -28,12 +35,70 @@ type editRecordMsg struct {
 	record api.DNSRecord
 }
 
+// newRecordMsg signals that the user wants to create a DNS record.
+type newRecordMsg struct{}
+
+// columnsChangedMsg reports new column choices for the preferences file.
+type columnsChangedMsg struct {
+	hidden     []string
+	detailPane bool
+}
+
 // RecordsModel handles the DNS records table view.
 type RecordsModel struct {
-	client    *api.Client
-	zone      api.Zone
-	records   []api.DNSRecord
-	table     table.Model
+	client  *api.Client
+	zone    api.Zone
+	records []api.DNSRecord
+	table   table.Model
+
+	// rows maps table rows to indices into records after filtering and
+	// sorting; nil means every record is shown in its original order.
+	rows []int
+
+	// filterInput is the "/" filter bar. filtering is set while it has focus;
+	// filterErr holds the compile error of an invalid regex or expression.
+	filterInput textinput.Model
+	filtering   bool
+	filter      recordFilter
+	filterErr   error
+
+	// queries are the saved expressions, by name; savePrompt asks for the
+	// name to save the current expression under.
+	queries    map[string]string
+	savePrompt queryPrompt
+
+	// exportPrompt asks where to write the zone file, terraformPrompt where
+	// to write Terraform configuration, and importPrompt which zone file or
+	// desired-state file to import.
+	exportPrompt    pathPrompt
+	terraformPrompt pathPrompt
+	importPrompt    pathPrompt
+
+	// sortBy and sortDesc order the rows; sortNone keeps the API order.
+	sortBy   sortColumn
+	sortDesc bool
+
+	// hidden holds the titles of hidden columns and detailPane enables the
+	// side pane on wide terminals. pickingColumns is set while the column
+	// picker is open, with columnCursor indexing recordColumns.
+	hidden         map[string]bool
+	detailPane     bool
+	pickingColumns bool
+	columnCursor   int
+
+	// marked holds the IDs of the records selected for a bulk edit.
+	marked map[string]bool
+
+	// staging is set while edits go to the pending changes instead of being
+	// saved; staged holds the IDs of records with a pending change and
+	// pendingCount the number of pending changes across all zones.
+	staging      bool
+	staged       map[string]bool
+	pendingCount int
+
+	// jumpTo is the ID of a record to select once the records have loaded.
+	jumpTo string
+
 	spinner   spinner.Model
 	loading   bool
 	err       error
@@ -49,14 +114,25 @@ func NewRecordsModel(client *api.Client, zone api.Zone, width, height int, readO
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
+	filterInput := textinput.New()
+	filterInput.Prompt = "/ "
+	filterInput.Placeholder = "type, name or content"
+	filterInput.CharLimit = 256
+	filterInput.Width = 40
+
 	return RecordsModel{
-		client:   client,
-		zone:     zone,
-		spinner:  sp,
-		loading:  true,
-		width:    width,
-		height:   height,
-		readOnly: readOnly,
+		client:          client,
+		zone:            zone,
+		filterInput:     filterInput,
+		savePrompt:      newQueryPrompt(),
+		exportPrompt:    newPathPrompt("Export zone file to: ", "render locally"),
+		terraformPrompt: newPathPrompt("Export Terraform to: ", ""),
+		importPrompt:    newPathPrompt("Import zone or state file: ", ""),
+		spinner:         sp,
+		loading:         true,
+		width:           width,
+		height:          height,
+		readOnly:        readOnly,
 	}
 }
 
@@ -76,27 +152,33 @@ func (m RecordsModel) fetchRecords() tea.Cmd {
 	}
 }
 
-// buildTable creates a table model from loaded DNS records.
+// buildTable creates a table model from loaded DNS records, sizing the
+// visible columns to the terminal width. Cells that do not fit are cut with
+// an ellipsis by the table itself.
 func (m RecordsModel) buildTable(records []api.DNSRecord) table.Model {
-	columns := []table.Column{
-		{Title: "Type", Width: 8},
-		{Title: "Name", Width: 64},
-		{Title: "Content", Width: 64},
-		{Title: "TTL", Width: 8},
-		{Title: "Proxied", Width: 8},
+	columns, idx := layoutColumns(m.tableWidth(), m.hidden)
+	if m.sortBy != sortNone {
+		arrow := " ▲"
+		if m.sortDesc {
+			arrow = " ▼"
+		}
+		for n, i := range idx {
+			if i == int(m.sortBy)-1 {
+				columns[n].Title += arrow
+			}
+		}
 	}
 
 	rows := make([]table.Row, len(records))
 	for i, r := range records {
-		proxied := "No"
-		if r.Proxied {
-			proxied = "Yes"
+		row := make(table.Row, len(idx))
+		for n, c := range idx {
+			row[n] = recordColumns[c].value(r)
 		}
-		ttl := strconv.Itoa(r.TTL)
-		if r.TTL == 1 {
-			ttl = "Auto"
+		if mark := m.rowMarker(r); mark != "" && len(row) > 0 {
+			row[0] = mark + " " + row[0]
 		}
-		rows[i] = table.Row{sanitize(r.Type), sanitize(r.Name), sanitize(r.Content), ttl, proxied}
+		rows[i] = row
 	}
 
 	h := m.height
@@ -107,7 +189,8 @@ func (m RecordsModel) buildTable(records []api.DNSRecord) table.Model {
 	t := table.New(
 		table.WithColumns(columns),
 		table.WithRows(rows),
-		table.WithHeight(h-4),
+		table.WithHeight(h-5),
+		table.WithWidth(m.tableWidth()),
 	)
 
 	s := table.DefaultStyles()
@@ -132,8 +215,7 @@ func (m RecordsModel) Update(msg tea.Msg) (RecordsModel, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		if !m.loading && m.err == nil {
-			m.table.SetWidth(msg.Width)
-			m.table.SetHeight(msg.Height - 4)
+			m.refreshTable(m.selectedID())
 		}
 		return m, nil
 
@@ -151,8 +233,12 @@ func (m RecordsModel) Update(msg tea.Msg) (RecordsModel, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
+		keep := m.selectedID()
+		if m.jumpTo != "" {
+			keep, m.jumpTo = m.jumpTo, ""
+		}
 		m.records = msg.records
-		m.table = m.buildTable(msg.records)
+		m.refreshTable(keep)
 		return m, nil
 
 	case statusClearMsg:
@@ -161,13 +247,134 @@ func (m RecordsModel) Update(msg tea.Msg) (RecordsModel, tea.Cmd) {
 
 	case tea.KeyMsg:
 		key := msg.String()
+		if m.savePrompt.active {
+			var cmd tea.Cmd
+			m.savePrompt, cmd = m.savePrompt.update(msg, m.queries)
+			return m, cmd
+		}
+		if m.exportPrompt.active {
+			var path string
+			var cmd tea.Cmd
+			m.exportPrompt, path, cmd = m.exportPrompt.update(msg)
+			if path != "" {
+				m.statusMsg = "Exporting zone file…"
+				cmd = m.exportCmd(path, m.exportPrompt.option)
+			}
+			return m, cmd
+		}
+		if m.terraformPrompt.active {
+			var path string
+			var cmd tea.Cmd
+			m.terraformPrompt, path, cmd = m.terraformPrompt.update(msg)
+			if path != "" {
+				m.statusMsg = "Writing Terraform configuration…"
+				cmd = m.terraformCmd(path)
+			}
+			return m, cmd
+		}
+		if m.importPrompt.active {
+			var path string
+			var cmd tea.Cmd
+			m.importPrompt, path, cmd = m.importPrompt.update(msg)
+			if path != "" {
+				m.statusMsg = "Reading zone file…"
+				cmd = m.importCmd(path)
+			}
+			return m, cmd
+		}
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		if m.pickingColumns {
+			return m.updateColumnPicker(msg)
+		}
+		if key == "c" && !m.loading && m.err == nil {
+			m.pickingColumns = true
+			return m, nil
+		}
+		if key == "i" && !m.loading && m.err == nil {
+			if record, ok := m.selectedRecord(); ok {
+				return m, func() tea.Msg { return showDetailMsg{record: record} }
+			}
+		}
+		if key == "p" && !m.loading && m.err == nil {
+			m.detailPane = !m.detailPane
+			m.refreshTable(m.selectedID())
+			return m, m.columnsChanged()
+		}
+		if key == "e" && !m.loading && m.err == nil {
+			return m, m.exportPrompt.open(m.zone.Name + ".zone")
+		}
+		if key == "T" && !m.loading && m.err == nil {
+			return m, m.terraformPrompt.open(m.zone.Name + ".tf")
+		}
+		if key == "I" && !m.loading && m.err == nil {
+			return m, m.importPrompt.open(m.zone.Name + ".zone")
+		}
+		if key == "/" && !m.loading && m.err == nil {
+			m.filtering = true
+			return m, m.filterInput.Focus()
+		}
+		if key == "s" && !m.loading && m.err == nil {
+			m.sortBy = m.sortBy.next()
+			m.sortDesc = false
+			m.refreshTable(m.selectedID())
+			return m, nil
+		}
+		if key == "S" && !m.loading && m.err == nil && m.sortBy != sortNone {
+			m.sortDesc = !m.sortDesc
+			m.refreshTable(m.selectedID())
+			return m, nil
+		}
+		if key == " " && !m.readOnly && !m.loading && m.err == nil {
+			if record, ok := m.selectedRecord(); ok {
+				m.toggleMarked(record.ID)
+				m.refreshTable(record.ID)
+				m.table.MoveDown(1)
+			}
+			return m, nil
+		}
+		if key == "a" && !m.readOnly && !m.loading && m.err == nil {
+			m.markShown()
+			m.refreshTable(m.selectedID())
+			return m, nil
+		}
+		if key == "b" && !m.readOnly && !m.loading && m.err == nil {
+			if records := m.markedRecords(); len(records) > 0 {
+				return m, func() tea.Msg { return bulkEditMsg{records: records} }
+			}
+			return m, nil
+		}
+		if key == "ctrl+f" {
+			return m, func() tea.Msg { return globalSearchMsg{} }
+		}
+		if key == "f" && !m.readOnly && !m.loading && m.err == nil {
+			return m, func() tea.Msg { return findReplaceMsg{} }
+		}
+		if key == "t" && !m.readOnly {
+			return m, func() tea.Msg { return toggleStagingMsg{} }
+		}
+		if key == "esc" && m.filter.active() {
+			m.clearFilter()
+			return m, nil
+		}
+		if key == "esc" && len(m.marked) > 0 {
+			m.clearMarked()
+			return m, nil
+		}
 		if key == "q" || key == "esc" {
 			return m, func() tea.Msg { return backToZonesMsg{} }
 		}
-		if key == "enter" && !m.readOnly && !m.loading && m.err == nil && len(m.records) > 0 {
-			cursor := m.table.Cursor()
-			if cursor >= 0 && cursor < len(m.records) {
-				record := m.records[cursor]
+		if key == "n" && !m.readOnly && !m.loading && m.err == nil {
+			return m, func() tea.Msg { return newRecordMsg{} }
+		}
+		if key == "d" && !m.readOnly && !m.loading && m.err == nil {
+			if record, ok := m.selectedRecord(); ok {
+				return m, func() tea.Msg { return deleteRecordMsg{record: record} }
+			}
+		}
+		if key == "enter" && !m.readOnly && !m.loading && m.err == nil {
+			if record, ok := m.selectedRecord(); ok {
 				return m, func() tea.Msg { return editRecordMsg{record: record} }
 			}
 		}
@@ -181,6 +388,324 @@ func (m RecordsModel) Update(msg tea.Msg) (RecordsModel, tea.Cmd) {
 	return m, nil
 }
 
+// updateFilter handles keys while the filter bar has focus. The filter is
+// re-applied on every change; Enter keeps it and returns to the table, Esc
+// clears it. The arrow keys still move the table cursor.
+func (m RecordsModel) updateFilter(msg tea.KeyMsg) (RecordsModel, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+		return m, nil
+	case "esc":
+		m.clearFilter()
+		return m, nil
+	case "ctrl+t":
+		m.filter.caseSensitive = !m.filter.caseSensitive
+		m.refreshTable(m.selectedID())
+		return m, nil
+	case "ctrl+x":
+		m.filter.regex = !m.filter.regex
+		m.refreshTable(m.selectedID())
+		return m, nil
+	case "ctrl+e":
+		m.filter.expr = !m.filter.expr
+		m.refreshTable(m.selectedID())
+		return m, nil
+	case "ctrl+n":
+		if name, expr, ok := nextSavedQuery(m.queries, m.filter.query); ok {
+			m.filter.expr = true
+			m.filter.query = expr
+			m.filterInput.SetValue(expr)
+			m.filterInput.CursorEnd()
+			m.statusMsg = fmt.Sprintf("Query %q", name)
+			m.refreshTable(m.selectedID())
+			return m, clearStatusAfter(5 * time.Second)
+		}
+		return m, nil
+	case "ctrl+s":
+		if m.filter.expr && m.filter.active() && m.filterErr == nil {
+			return m, m.savePrompt.open(m.filter.query)
+		}
+		return m, nil
+	case "up", "down", "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if m.filterInput.Value() != m.filter.query {
+		m.filter.query = m.filterInput.Value()
+		m.refreshTable(m.selectedID())
+	}
+	return m, cmd
+}
+
+// updateColumnPicker handles keys while the column picker is open. Space
+// toggles the column under the cursor; Enter, Esc or c close the picker and
+// save the choice.
+func (m RecordsModel) updateColumnPicker(msg tea.KeyMsg) (RecordsModel, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.columnCursor > 0 {
+			m.columnCursor--
+		}
+	case "down", "j":
+		if m.columnCursor < len(recordColumns)-1 {
+			m.columnCursor++
+		}
+	case " ", "x":
+		title := recordColumns[m.columnCursor].title
+		if !m.hidden[title] && len(m.hidden) == len(recordColumns)-1 {
+			return m, nil // keep at least one column
+		}
+		hidden := make(map[string]bool, len(m.hidden)+1)
+		for t := range m.hidden {
+			hidden[t] = true
+		}
+		if hidden[title] {
+			delete(hidden, title)
+		} else {
+			hidden[title] = true
+		}
+		m.hidden = hidden
+		m.refreshTable(m.selectedID())
+	case "enter", "esc", "c":
+		m.pickingColumns = false
+		return m, m.columnsChanged()
+	}
+	return m, nil
+}
+
+// columnsChanged reports the current column choices so they can be saved.
+func (m RecordsModel) columnsChanged() tea.Cmd {
+	hidden := make([]string, 0, len(m.hidden))
+	for _, c := range recordColumns {
+		if m.hidden[c.title] {
+			hidden = append(hidden, c.title)
+		}
+	}
+	detail := m.detailPane
+	return func() tea.Msg { return columnsChangedMsg{hidden: hidden, detailPane: detail} }
+}
+
+// tableWidth returns the width available to the table, leaving room for the
+// detail pane when it is shown.
+func (m RecordsModel) tableWidth() int {
+	w := m.width
+	if w == 0 {
+		w = 80
+	}
+	if m.showsDetailPane() {
+		w -= detailPaneWidth
+	}
+	return w
+}
+
+// showsDetailPane reports whether the detail pane is enabled and fits.
+func (m RecordsModel) showsDetailPane() bool {
+	return m.detailPane && m.width >= detailPaneMinWidth
+}
+
+// rowMarker returns the marker shown before a record's first cell, or "".
+func (m RecordsModel) rowMarker(r api.DNSRecord) string {
+	mark := ""
+	if m.marked[r.ID] {
+		mark = "●"
+	}
+	if m.staged[r.ID] {
+		mark += "~"
+	}
+	return mark
+}
+
+// toggleMarked selects or deselects the record with the given ID.
+func (m *RecordsModel) toggleMarked(id string) {
+	marked := make(map[string]bool, len(m.marked)+1)
+	for k := range m.marked {
+		marked[k] = true
+	}
+	if marked[id] {
+		delete(marked, id)
+	} else {
+		marked[id] = true
+	}
+	m.marked = marked
+}
+
+// markShown selects every record the filter shows, or deselects them all if
+// they are already selected.
+func (m *RecordsModel) markShown() {
+	shown := m.shownRecords()
+	all := len(shown) > 0
+	for _, r := range shown {
+		all = all && m.marked[r.ID]
+	}
+	marked := make(map[string]bool, len(m.marked)+len(shown))
+	for k := range m.marked {
+		marked[k] = true
+	}
+	for _, r := range shown {
+		if all {
+			delete(marked, r.ID)
+		} else {
+			marked[r.ID] = true
+		}
+	}
+	m.marked = marked
+}
+
+// setStaged replaces the staging state and marks the rows with pending
+// changes in this zone.
+func (m *RecordsModel) setStaged(staging bool, changes []stagedChange) {
+	m.staging = staging
+	m.pendingCount = len(changes)
+	m.staged = make(map[string]bool)
+	for _, c := range changes {
+		if c.zoneID == m.zone.ID && c.before.ID != "" {
+			m.staged[c.before.ID] = true
+		}
+	}
+	m.refreshTable(m.selectedID())
+}
+
+// clearMarked deselects every record.
+func (m *RecordsModel) clearMarked() {
+	m.marked = nil
+	m.refreshTable(m.selectedID())
+}
+
+// markedRecords returns the selected records that are still loaded, in
+// their original order.
+func (m RecordsModel) markedRecords() []api.DNSRecord {
+	var records []api.DNSRecord
+	for _, r := range m.records {
+		if m.marked[r.ID] {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// clearFilter removes the filter and closes the filter bar.
+func (m *RecordsModel) clearFilter() {
+	m.filtering = false
+	m.filterInput.Blur()
+	m.filterInput.SetValue("")
+	m.filter.query = ""
+	m.refreshTable(m.selectedID())
+}
+
+// refreshTable recomputes which records are shown and rebuilds the table,
+// moving the cursor onto the record with ID keep if it is still shown.
+func (m *RecordsModel) refreshTable(keep string) {
+	m.filterErr = nil
+	m.rows = nil
+	if m.filter.active() || m.sortBy != sortNone {
+		rows, err := m.filter.apply(m.records)
+		if err != nil {
+			m.filterErr = err
+		}
+		m.rows = append([]int{}, rows...)
+		if m.sortBy != sortNone {
+			sortRows(m.records, m.rows, m.sortBy, m.sortDesc)
+		}
+	}
+
+	m.table = m.buildTable(m.shownRecords())
+	if keep != "" {
+		m.selectRecord(keep)
+	}
+}
+
+// selectedID returns the ID of the record under the cursor, or "".
+func (m RecordsModel) selectedID() string {
+	r, _ := m.selectedRecord()
+	return r.ID
+}
+
+// shownRecords returns the records in table row order.
+func (m RecordsModel) shownRecords() []api.DNSRecord {
+	if m.rows == nil {
+		return m.records
+	}
+	shown := make([]api.DNSRecord, len(m.rows))
+	for i, idx := range m.rows {
+		shown[i] = m.records[idx]
+	}
+	return shown
+}
+
+// selectRecord moves the cursor onto the row showing the record with the
+// given ID. It reports false if the record is not shown.
+func (m *RecordsModel) selectRecord(id string) bool {
+	for i, r := range m.shownRecords() {
+		if r.ID == id {
+			m.table.SetCursor(i)
+			return true
+		}
+	}
+	return false
+}
+
+// insertRecord appends a newly created record to the table and moves the
+// cursor onto it.
+func (m *RecordsModel) insertRecord(record api.DNSRecord) {
+	m.records = append(m.records, record)
+	m.refreshTable(record.ID)
+}
+
+// removeRecord drops the record with the given ID from the table, keeping the
+// cursor in range.
+func (m *RecordsModel) removeRecord(id string) {
+	cursor := m.table.Cursor()
+	for i, r := range m.records {
+		if r.ID == id {
+			m.records = append(m.records[:i:i], m.records[i+1:]...)
+			break
+		}
+	}
+	m.refreshTable("")
+	n := len(m.shownRecords())
+	if cursor >= n {
+		cursor = n - 1
+	}
+	if cursor >= 0 {
+		m.table.SetCursor(cursor)
+	}
+}
+
+// selectedRecord returns the record under the table cursor, mapping the row
+// back to the underlying record when a filter is applied.
+func (m RecordsModel) selectedRecord() (api.DNSRecord, bool) {
+	cursor := m.table.Cursor()
+	shown := m.shownRecords()
+	if cursor < 0 || cursor >= len(shown) {
+		return api.DNSRecord{}, false
+	}
+	return shown[cursor], true
+}
+
+// recordDetails summarises the fields of r that do not fit in the table:
+// priority, structured data, comment and tags.
+func recordDetails(r api.DNSRecord) string {
+	var parts []string
+	if api.UsesPriority(r.Type) {
+		parts = append(parts, "Priority: "+strconv.Itoa(r.Priority))
+	}
+	if len(r.Data) > 0 {
+		parts = append(parts, "Data: "+formatData(r.Data))
+	}
+	if r.Comment != "" {
+		parts = append(parts, "Comment: "+r.Comment)
+	}
+	if len(r.Tags) > 0 {
+		parts = append(parts, "Tags: "+strings.Join(r.Tags, ", "))
+	}
+	return sanitize(strings.Join(parts, " | "))
+}
+
 // View renders the records view.
 func (m RecordsModel) View() string {
 	if m.loading {
@@ -190,21 +715,86 @@ func (m RecordsModel) View() string {
 		return fmt.Sprintf("\n  Error loading records: %v\n\n  Press q to go back.\n", m.err)
 	}
 
-	header := lipgloss.NewStyle().
+	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Padding(0, 0, 1, 2).
-		Render(fmt.Sprintf("DNS Records - %s", sanitize(m.zone.Name)))
+		Padding(0, 0, 1, 2)
+	if m.filtering || m.filter.active() {
+		headerStyle = headerStyle.PaddingBottom(0)
+	}
+	title := fmt.Sprintf("DNS Records - %s", sanitize(m.zone.Name))
+	if n := len(m.markedRecords()); n > 0 {
+		title += fmt.Sprintf("  (%d selected)", n)
+	}
+	if m.staging {
+		title += fmt.Sprintf("  [STAGING: %d pending]", m.pendingCount)
+	} else if m.pendingCount > 0 {
+		title += fmt.Sprintf("  (%d pending)", m.pendingCount)
+	}
+	header := headerStyle.Render(title)
+	if m.filtering || m.filter.active() {
+		header += "\n" + m.filterBar()
+	}
 
-	helpText := "↑/↓: navigate | Enter: edit record | q/Esc: back | Ctrl+C: quit"
+	helpText := "↑/↓: navigate | /: filter | s/S: sort | i: details | Enter: edit record | n: new record | d: delete | Space/a: select | b: bulk edit | f: find/replace | t: staging | Ctrl+P: pending | e/I: export/import | T: Terraform | c: columns | p: pane | Ctrl+F: search all | Ctrl+R: changes | q/Esc: back | Ctrl+C: quit"
 	if m.readOnly {
-		helpText = "↑/↓: navigate | q/Esc: back | Ctrl+C: quit  [READ-ONLY]"
+		helpText = "[READ-ONLY]  ↑/↓: navigate | /: filter | s/S: sort | i: details | e/I: export/import | T: Terraform | c: columns | p: pane | Ctrl+F: search all | q/Esc: back | Ctrl+C: quit"
+	}
+	if m.filtering {
+		helpText = "Enter: apply filter | Esc: clear | Ctrl+T: case | Ctrl+X: regex | Ctrl+E: query | Ctrl+N: saved query | ↑/↓: navigate"
+	}
+	if m.filtering && m.filter.expr {
+		helpText = "Enter: apply | Esc: clear | Ctrl+E: text filter | Ctrl+N: next saved query | Ctrl+S: save query | fields: " + strings.Join(query.Fields, " ")
+	}
+	if m.savePrompt.active {
+		helpText = "Enter: save query | Esc: cancel"
+	}
+	if m.pickingColumns {
+		helpText = "↑/↓: navigate | Space: show/hide column | Enter/Esc: done"
+	}
+	if m.exportPrompt.active {
+		helpText = "Enter: export | Ctrl+L: render locally from the record list | Esc: cancel"
+	}
+	if m.terraformPrompt.active {
+		scope := "every record"
+		if n := len(m.markedRecords()); n > 0 {
+			scope = fmt.Sprintf("the %d marked records", n)
+		}
+		helpText = "Enter: write " + scope + " as cloudflare_dns_record resources with import blocks | Esc: cancel"
+	}
+	if m.importPrompt.active {
+		helpText = "Enter: read the file and review the changes | Esc: cancel"
 	}
 	help := lipgloss.NewStyle().
 		Faint(true).
 		Padding(1, 0, 0, 2).
-		Render(helpText)
+		Render(truncate(helpText, m.width-2))
 
-	result := header + "\n" + m.table.View() + "\n"
+	body := m.table.View()
+	if m.pickingColumns {
+		body = m.columnPicker()
+	} else if record, ok := m.selectedRecord(); ok && m.showsDetailPane() {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.detailPaneView(record))
+	}
+	result := header + "\n" + body + "\n"
+
+	if record, ok := m.selectedRecord(); ok {
+		if details := recordDetails(record); details != "" {
+			result += lipgloss.NewStyle().
+				Faint(true).
+				Padding(0, 0, 0, 2).
+				Render(details) + "\n"
+		}
+	}
+
+	if m.exportPrompt.active {
+		result += lipgloss.NewStyle().Padding(0, 0, 0, 2).Render(m.exportPrompt.View()) + "\n"
+	}
+	if m.terraformPrompt.active {
+		result += lipgloss.NewStyle().Padding(0, 0, 0, 2).Render(m.terraformPrompt.View()) + "\n"
+	}
+	if m.importPrompt.active {
+		result += lipgloss.NewStyle().Padding(0, 0, 0, 2).Render(m.importPrompt.View()) + "\n"
+	}
 
 	if m.statusMsg != "" {
 		statusStyle := lipgloss.NewStyle().
@@ -218,6 +808,75 @@ func (m RecordsModel) View() string {
 	return result
 }
 
+// columnPicker renders the list of columns with their visibility.
+func (m RecordsModel) columnPicker() string {
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	selectedStyle := rowStyle.Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	lines := []string{rowStyle.Bold(true).Render("Columns")}
+	for i, c := range recordColumns {
+		mark := "[x] "
+		if m.hidden[c.title] {
+			mark = "[ ] "
+		}
+		style := rowStyle
+		if i == m.columnCursor {
+			style = selectedStyle
+		}
+		lines = append(lines, style.Render(mark+c.title))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// detailPaneView renders the selected record beside the table.
+func (m RecordsModel) detailPaneView(r api.DNSRecord) string {
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(10)
+	valueWidth := detailPaneWidth - 14
+	var lines []string
+	add := func(label, value string) {
+		if value == "" {
+			return
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render(label),
+			lipgloss.NewStyle().Width(valueWidth).Render(sanitize(value)),
+		))
+	}
+	fields := plan.Fields(r)
+	add("Type", r.Type)
+	for i, label := range plan.FieldLabels {
+		add(label, fields[i])
+	}
+	if !r.ModifiedOn.IsZero() {
+		add("Modified", r.ModifiedOn.Local().Format(timestampLayout))
+	}
+	add("ID", r.ID)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(detailPaneWidth - 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// filterBar renders the filter input with its options and match count.
+func (m RecordsModel) filterBar() string {
+	count := fmt.Sprintf("%d of %d", len(m.shownRecords()), len(m.records))
+	var below string
+	switch {
+	case m.filterErr != nil && m.filter.expr:
+		count = ""
+		below = "\n" + queryErrorView(m.filter.query, m.filterErr)
+	case m.filterErr != nil:
+		count = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("invalid regex: " + m.filterErr.Error())
+	}
+	if m.savePrompt.active {
+		below = "\n" + m.savePrompt.input.View()
+	}
+	return lipgloss.NewStyle().Padding(0, 0, 0, 2).Render(
+		m.filterInput.View() + "  " +
+			lipgloss.NewStyle().Faint(true).Render(m.filter.modeLabel()) + "  " + count + below)
+}
+
 // clearStatusAfter returns a command that clears the status message after the given duration.
 func clearStatusAfter(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(time.Time) tea.Msg {
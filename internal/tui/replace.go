@@ -0,0 +1,627 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// contentReplacer rewrites record content. find is matched literally, or as
+// a regular expression whose replacement may refer to groups as $1 or ${name}.
+type contentReplacer struct {
+	find  string
+	with  string
+	regex bool
+}
+
+// compile returns the replacement function, or an error for an empty search
+// or an invalid regular expression.
+func (c contentReplacer) compile() (func(string) string, error) {
+	if c.find == "" {
+		return nil, errors.New("enter the text to find")
+	}
+	if !c.regex {
+		return func(s string) string { return strings.ReplaceAll(s, c.find, c.with) }, nil
+	}
+	re, err := regexp.Compile(c.find)
+	if err != nil {
+		return nil, err
+	}
+	return func(s string) string { return re.ReplaceAllString(s, c.with) }, nil
+}
+
+// replaceMatch is one record whose content the replacement changes. err is
+// set when the new content is not valid for the record, which cannot then be
+// selected.
+type replaceMatch struct {
+	zone     api.Zone
+	record   api.DNSRecord
+	content  string
+	selected bool
+	err      string
+	status   bulkStatus
+	after    api.DNSRecord
+}
+
+// findReplacements returns the records in zone whose content replace
+// changes, each selected unless the new content fails validation.
+func findReplacements(zone api.Zone, records []api.DNSRecord, replace func(string) string) []replaceMatch {
+	var matches []replaceMatch
+	for _, r := range records {
+		content := replace(r.Content)
+		if content == r.Content {
+			continue
+		}
+		msg := bulkEdit{content: &content}.check(r)
+		matches = append(matches, replaceMatch{zone: zone, record: r, content: content, selected: msg == "", err: msg})
+	}
+	return matches
+}
+
+// replaceField identifies which find-and-replace form field is focused.
+type replaceField int
+
+const (
+	replaceFind replaceField = iota
+	replaceWith
+	replaceRegex
+	replaceAllZones
+	replaceSubmit
+)
+
+// replacePhase is the step the find-and-replace screen is on.
+type replacePhase int
+
+const (
+	replaceEditing replacePhase = iota
+	replaceSearching
+	replacePreview
+	replaceApplying
+	replaceDone
+)
+
+// findReplaceMsg signals that the user wants to find and replace content.
+type findReplaceMsg struct{}
+
+// replaceFoundMsg carries the matches of a search and the zones whose
+// records could not be listed, or the error that stopped it.
+type replaceFoundMsg struct {
+	matches []replaceMatch
+	failed  []string
+	err     error
+}
+
+// replaceAppliedMsg carries the records returned by each zone's batch, the
+// error of each zone whose batch was rejected, and the messages a rejected
+// batch gave for single records, by record ID.
+type replaceAppliedMsg struct {
+	after  map[string]api.DNSRecord
+	errs   map[string]error
+	failed map[string]string
+}
+
+// replaceDoneMsg signals that the user left find and replace. matches is nil
+// if nothing was sent.
+type replaceDoneMsg struct {
+	matches []replaceMatch
+}
+
+// ReplaceModel finds records whose content contains a value, previews the
+// replacement for each and applies the ones the user keeps ticked.
+type ReplaceModel struct {
+	client  *api.Client
+	zone    api.Zone
+	records []api.DNSRecord
+
+	findInput    textinput.Model
+	replaceInput textinput.Model
+	regex        bool
+	allZones     bool
+	focused      replaceField
+	formErr      string
+
+	phase   replacePhase
+	matches []replaceMatch
+	// failed names the zones a search of every zone could not list.
+	failed  []string
+	cursor  int
+	spinner spinner.Model
+	width   int
+	height  int
+}
+
+// NewReplaceModel creates a find-and-replace screen over the records of
+// zone, which can be widened to every zone.
+func NewReplaceModel(client *api.Client, zone api.Zone, records []api.DNSRecord, width, height int) ReplaceModel {
+	newInput := func(placeholder string) textinput.Model {
+		in := textinput.New()
+		in.Placeholder = placeholder
+		in.CharLimit = 2048
+		in.Width = 50
+		return in
+	}
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	m := ReplaceModel{
+		client:       client,
+		zone:         zone,
+		records:      records,
+		findInput:    newInput("e.g. 203.0.113.10"),
+		replaceInput: newInput("replacement, may be empty"),
+		spinner:      sp,
+		width:        width,
+		height:       height,
+	}
+	m.updateFocus()
+	return m
+}
+
+// Init returns the text input blink command.
+func (m ReplaceModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the find-and-replace screen.
+func (m ReplaceModel) Update(msg tea.Msg) (ReplaceModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.Busy() {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case replaceFoundMsg:
+		if msg.err != nil {
+			m.phase = replaceEditing
+			m.formErr = msg.err.Error()
+			m.updateFocus()
+			return m, nil
+		}
+		m.phase = replacePreview
+		m.matches = msg.matches
+		m.failed = msg.failed
+		m.cursor = 0
+		return m, nil
+
+	case replaceAppliedMsg:
+		m.phase = replaceDone
+		for i := range m.matches {
+			match := &m.matches[i]
+			if match.status != bulkRunning {
+				continue
+			}
+			r, ok := msg.after[match.record.ID]
+			switch {
+			case msg.failed[match.record.ID] != "":
+				match.status = bulkFailed
+				match.err = msg.failed[match.record.ID]
+			case msg.errs[match.zone.ID] != nil:
+				match.status = bulkFailed
+				match.err = "not applied: " + msg.errs[match.zone.ID].Error()
+			case !ok:
+				match.status = bulkFailed
+				match.err = "missing from the batch result"
+			default:
+				match.status = bulkUpdated
+				match.after = r
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.phase {
+		case replaceEditing:
+			return m.updateForm(msg)
+		case replacePreview:
+			return m.updatePreview(msg)
+		case replaceDone:
+			switch msg.String() {
+			case "enter", "esc", "q":
+				return m, m.finish()
+			}
+		}
+		return m, nil
+	}
+
+	return m.updateInput(msg)
+}
+
+// updateForm handles keys while the search form is shown.
+func (m ReplaceModel) updateForm(msg tea.KeyMsg) (ReplaceModel, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "down":
+		m.focused = (m.focused + 1) % (replaceSubmit + 1)
+		m.updateFocus()
+		return m, nil
+	case "shift+tab", "up":
+		m.focused = (m.focused + replaceSubmit) % (replaceSubmit + 1)
+		m.updateFocus()
+		return m, nil
+	case "esc":
+		return m, func() tea.Msg { return replaceDoneMsg{} }
+	case "ctrl+x":
+		m.regex = !m.regex
+		return m, nil
+	case " ", "enter":
+		switch m.focused {
+		case replaceRegex:
+			m.regex = !m.regex
+			return m, nil
+		case replaceAllZones:
+			m.allZones = !m.allZones
+			return m, nil
+		case replaceSubmit:
+			return m.search()
+		}
+		if msg.String() == "enter" {
+			return m.search()
+		}
+	}
+	return m.updateInput(msg)
+}
+
+// updateInput passes msg to the focused text input.
+func (m ReplaceModel) updateInput(msg tea.Msg) (ReplaceModel, tea.Cmd) {
+	if m.phase != replaceEditing {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	switch m.focused {
+	case replaceFind:
+		m.findInput, cmd = m.findInput.Update(msg)
+	case replaceWith:
+		m.replaceInput, cmd = m.replaceInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// updatePreview handles keys while the matches are listed for review.
+func (m ReplaceModel) updatePreview(msg tea.KeyMsg) (ReplaceModel, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case " ":
+		if m.cursor < len(m.matches) && m.matches[m.cursor].err == "" {
+			m.matches[m.cursor].selected = !m.matches[m.cursor].selected
+		}
+	case "a":
+		all := true
+		for _, match := range m.matches {
+			all = all && (match.selected || match.err != "")
+		}
+		for i := range m.matches {
+			m.matches[i].selected = !all && m.matches[i].err == ""
+		}
+	case "enter":
+		return m.apply()
+	case "esc":
+		m.phase = replaceEditing
+		m.matches = nil
+		m.updateFocus()
+	}
+	return m, nil
+}
+
+// search finds the matches in the loaded records, or in every zone when
+// allZones is set.
+func (m ReplaceModel) search() (ReplaceModel, tea.Cmd) {
+	replace, err := contentReplacer{find: m.findInput.Value(), with: m.replaceInput.Value(), regex: m.regex}.compile()
+	if err != nil {
+		m.formErr = err.Error()
+		return m, nil
+	}
+	m.formErr = ""
+	if !m.allZones {
+		m.phase = replacePreview
+		m.matches = findReplacements(m.zone, m.records, replace)
+		m.cursor = 0
+		m.updateFocus()
+		return m, nil
+	}
+
+	m.phase = replaceSearching
+	m.updateFocus()
+	return m, tea.Batch(m.spinner.Tick, m.searchAll(replace))
+}
+
+// searchAll finds the matches in every zone the token can list, listing
+// zones like the global search. Zones that fail are reported rather than
+// stopping the search.
+func (m ReplaceModel) searchAll(replace func(string) string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		zones, err := client.ListZones(ctx)
+		if err != nil {
+			return replaceFoundMsg{err: err}
+		}
+		results := make(chan zoneRecordsResult)
+		go func() {
+			searchZones(ctx, client, zones, searchWorkers, results)
+			close(results)
+		}()
+		byZone := make(map[string]zoneRecordsResult, len(zones))
+		for res := range results {
+			byZone[res.zone.ID] = res
+		}
+
+		// Zones finish in any order; list them as ListZones did.
+		var msg replaceFoundMsg
+		for _, zone := range zones {
+			// A zone is missing from byZone if the search ran out of time.
+			if res, ok := byZone[zone.ID]; ok && res.err == nil {
+				msg.matches = append(msg.matches, findReplacements(zone, res.records, replace)...)
+			} else {
+				msg.failed = append(msg.failed, zone.Name)
+			}
+		}
+		return msg
+	}
+}
+
+// apply sends the ticked replacements.
+func (m ReplaceModel) apply() (ReplaceModel, tea.Cmd) {
+	running := false
+	for i := range m.matches {
+		if m.matches[i].selected {
+			m.matches[i].status = bulkRunning
+			running = true
+		}
+	}
+	if !running {
+		return m, nil
+	}
+	m.phase = replaceApplying
+	return m, tea.Batch(m.spinner.Tick, m.send())
+}
+
+// send applies the running replacements, one batch per zone, so each zone
+// either takes all of its changes or none.
+func (m ReplaceModel) send() tea.Cmd {
+	var zones []string
+	reqs := make(map[string]*api.BatchRequest)
+	for _, match := range m.matches {
+		if match.status != bulkRunning {
+			continue
+		}
+		req, ok := reqs[match.zone.ID]
+		if !ok {
+			req = &api.BatchRequest{}
+			reqs[match.zone.ID] = req
+			zones = append(zones, match.zone.ID)
+		}
+		content := match.content
+		req.Patches = append(req.Patches, bulkEdit{content: &content}.patch(match.record))
+	}
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		msg := replaceAppliedMsg{after: make(map[string]api.DNSRecord), errs: make(map[string]error), failed: make(map[string]string)}
+		for _, zoneID := range zones {
+			req := reqs[zoneID]
+			result, err := client.BatchDNSRecords(ctx, zoneID, *req)
+			if err != nil {
+				msg.errs[zoneID] = err
+				for op, text := range api.BatchErrors(err) {
+					if op.List == "patches" && op.Index < len(req.Patches) {
+						msg.failed[req.Patches[op.Index].ID] = text
+					}
+				}
+				continue
+			}
+			for _, r := range result.Patches {
+				msg.after[r.ID] = r
+			}
+		}
+		return msg
+	}
+}
+
+// finish reports the results so the root model can journal them.
+func (m ReplaceModel) finish() tea.Cmd {
+	matches := append([]replaceMatch{}, m.matches...)
+	return func() tea.Msg { return replaceDoneMsg{matches: matches} }
+}
+
+// counts returns how many matches were updated and how many failed.
+func (m ReplaceModel) counts() (updated, failed int) {
+	for _, match := range m.matches {
+		switch match.status {
+		case bulkUpdated:
+			updated++
+		case bulkFailed:
+			failed++
+		}
+	}
+	return updated, failed
+}
+
+// updateFocus sets the focused state on each text input.
+func (m *ReplaceModel) updateFocus() {
+	m.findInput.Blur()
+	m.replaceInput.Blur()
+	if m.phase != replaceEditing {
+		return
+	}
+	switch m.focused {
+	case replaceFind:
+		m.findInput.Focus()
+	case replaceWith:
+		m.replaceInput.Focus()
+	}
+}
+
+// View renders the search form, or the matches once searched.
+func (m ReplaceModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(11).Padding(0, 1, 0, 2)
+	focusedLabelStyle := labelStyle.Foreground(lipgloss.Color("205"))
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 0, 0, 2)
+	helpStyle := lipgloss.NewStyle().Faint(true).Padding(1, 0, 0, 2)
+
+	scope := sanitize(m.zone.Name)
+	if m.allZones {
+		scope = "all zones"
+	}
+	title := titleStyle.Render(" Find and Replace ")
+	sections := []string{headerStyle.Render(fmt.Sprintf("%s  %s", title, scope))}
+
+	label := func(f replaceField, text string) string {
+		if f == m.focused && m.phase == replaceEditing {
+			return focusedLabelStyle.Render(text)
+		}
+		return labelStyle.Render(text)
+	}
+	check := func(on bool, text string) string {
+		if on {
+			return "[x] " + text
+		}
+		return "[ ] " + text
+	}
+	submit := "[ Find ]"
+	if m.focused == replaceSubmit && m.phase == replaceEditing {
+		submit = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Background(lipgloss.Color("57")).
+			Render(submit)
+	}
+	sections = append(sections,
+		label(replaceFind, "Find")+m.findInput.View(),
+		label(replaceWith, "Replace")+m.replaceInput.View(),
+		label(replaceRegex, "Regex")+" "+check(m.regex, "regular expression, $1 in the replacement"),
+		label(replaceAllZones, "Zones")+" "+check(m.allZones, "search every zone"),
+		"",
+		rowStyle.Bold(true).Render(submit),
+	)
+	if m.formErr != "" {
+		sections = append(sections, errorStyle.Render("! "+m.formErr))
+	}
+
+	helpText := "Tab/Shift+Tab: navigate | Space: toggle | Ctrl+X: regex | Enter: find | Esc: cancel"
+	switch m.phase {
+	case replaceSearching:
+		sections = append(sections, "", rowStyle.Render(m.spinner.View()+" Searching every zone…"))
+		helpText = "Searching…"
+	case replacePreview, replaceApplying, replaceDone:
+		sections = append(sections, "", m.matchList())
+		if len(m.failed) > 0 {
+			sections = append(sections, errorStyle.Render(truncate(sanitize("! Could not search "+strings.Join(m.failed, ", ")), m.width-2)))
+		}
+		helpText = "↑/↓: navigate | Space: tick | a: tick all | Enter: apply ticked | Esc: back to search"
+		if m.phase == replaceApplying {
+			helpText = "Applying changes…"
+		} else if m.phase == replaceDone {
+			helpText = "Enter/Esc: back to records"
+		}
+	}
+	sections = append(sections, helpStyle.Render(truncate(helpText, m.width-2)))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// matchList renders the matches around the cursor, each with its diff, and
+// the outcome once applied.
+func (m ReplaceModel) matchList() string {
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	selectedStyle := rowStyle.Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	diffStyle := lipgloss.NewStyle().Faint(true).Padding(0, 0, 0, 8)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 0, 0, 8)
+
+	if len(m.matches) == 0 {
+		return rowStyle.Faint(true).Render("No record content matches.")
+	}
+	ticked := 0
+	for _, match := range m.matches {
+		if match.selected {
+			ticked++
+		}
+	}
+	lines := []string{rowStyle.Bold(true).Render(fmt.Sprintf("%d matches, %d ticked", len(m.matches), ticked))}
+
+	// Each match takes two lines; show a window that keeps the cursor in view.
+	visible := max((m.height-18)/2, 3)
+	start := max(0, min(m.cursor-visible/2, len(m.matches)-visible))
+	end := min(start+visible, len(m.matches))
+	for i := start; i < end; i++ {
+		match := m.matches[i]
+		var mark string
+		switch {
+		case match.status == bulkRunning:
+			mark = m.spinner.View()
+		case match.status == bulkUpdated:
+			mark = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓")
+		case match.status == bulkFailed:
+			mark = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("✗")
+		case match.err != "":
+			mark = "[-]"
+		case match.selected:
+			mark = "[x]"
+		default:
+			mark = "[ ]"
+		}
+		line := fmt.Sprintf("%s %-5s %s", mark, match.record.Type, match.record.Name)
+		if m.allZones {
+			line += "  (" + match.zone.Name + ")"
+		}
+		style := rowStyle
+		if i == m.cursor && m.phase == replacePreview {
+			style = selectedStyle
+		}
+		lines = append(lines, style.Render(truncate(sanitize(line), m.width-2)))
+		diff := fmt.Sprintf("%s → %s", match.record.Content, match.content)
+		lines = append(lines, diffStyle.Render(truncate(sanitize(diff), m.width-8)))
+		if match.err != "" {
+			lines = append(lines, errorStyle.Render(truncate(sanitize(match.err), m.width-8)))
+		}
+	}
+	if end < len(m.matches) {
+		lines = append(lines, rowStyle.Faint(true).Render(fmt.Sprintf("… %d more", len(m.matches)-end)))
+	}
+
+	if m.phase == replaceDone {
+		updated, failed := m.counts()
+		style := rowStyle.Foreground(lipgloss.Color("42"))
+		if failed > 0 {
+			style = rowStyle.Foreground(lipgloss.Color("196"))
+		}
+		lines = append(lines, "", style.Bold(true).Render(fmt.Sprintf("%d updated, %d failed", updated, failed)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Busy returns whether a search or update is in flight.
+func (m ReplaceModel) Busy() bool {
+	return m.phase == replaceSearching || m.phase == replaceApplying
+}
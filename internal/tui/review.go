@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+)
+
+// reviewChanges pairs each field label with its value before and after the
+// pending save. Creating a record has an empty before snapshot.
+func reviewChanges(before, after api.DNSRecord, creating bool) (labels, from, to []string) {
+	labels = append([]string{"Type"}, plan.FieldLabels...)
+	from = append([]string{before.Type}, plan.Fields(before)...)
+	to = append([]string{after.Type}, plan.Fields(after)...)
+	if creating {
+		for i := range from {
+			from[i] = ""
+		}
+	}
+	return labels, from, to
+}
+
+// updateReview handles keys on the review screen. Enter or y sends the
+// pending save, or stages it in staging mode; Esc or n returns to the form.
+func (m EditModel) updateReview(msg tea.KeyMsg) (EditModel, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "y":
+		m.reviewing = false
+		if m.staging {
+			change := stagedChange{zoneID: m.zoneID, zoneName: m.zoneName, before: m.record, params: m.pending.params}
+			if m.creating {
+				change.before = api.DNSRecord{}
+			}
+			return m, func() tea.Msg { return stageEditMsg{change: change} }
+		}
+		m.saving = true
+		return m, tea.Batch(m.spinner.Tick, m.saveCmd(m.pending))
+	case "esc", "n":
+		m.reviewing = false
+		return m, nil
+	}
+	return m, nil
+}
+
+// reviewView renders a field-by-field before/after diff of the pending save,
+// highlighting the fields that change.
+func (m EditModel) reviewView() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(13).Padding(0, 1, 0, 2)
+	cellStyle := lipgloss.NewStyle().Width(36).Padding(0, 1)
+	unchangedStyle := cellStyle.Faint(true)
+	oldStyle := cellStyle.Foreground(lipgloss.Color("196"))
+	newStyle := cellStyle.Foreground(lipgloss.Color("42")).Bold(true)
+	helpStyle := lipgloss.NewStyle().Faint(true).Padding(1, 0, 0, 2)
+
+	verb := "Save"
+	if m.creating {
+		verb = "Create"
+	}
+	if m.staging {
+		verb = "Stage"
+	}
+	title := titleStyle.Render(fmt.Sprintf(" Review %s ", verb))
+	lines := []string{headerStyle.Render(title + "  " + sanitize(m.zoneName))}
+
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+		labelStyle.Render(""),
+		cellStyle.Bold(true).Render("Before"),
+		cellStyle.Bold(true).Render("After"),
+	))
+
+	after := paramsRecord(m.record.ID, m.pending.params)
+	labels, from, to := reviewChanges(m.record, after, m.creating)
+	changed := 0
+	for i, label := range labels {
+		if from[i] == "" && to[i] == "" {
+			continue
+		}
+		if from[i] == to[i] {
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+				labelStyle.Faint(true).Render(label),
+				unchangedStyle.Render(sanitize(from[i])),
+				unchangedStyle.Render(sanitize(to[i])),
+			))
+			continue
+		}
+		changed++
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Foreground(lipgloss.Color("214")).Render("~ "+label),
+			oldStyle.Render(sanitize(from[i])),
+			newStyle.Render(sanitize(to[i])),
+		))
+	}
+
+	summary := fmt.Sprintf("%d field(s) changed", changed)
+	if changed == 0 {
+		summary = "No changes"
+	}
+	lines = append(lines, "", lipgloss.NewStyle().Bold(true).Padding(0, 0, 0, 2).Render(summary))
+	lines = append(lines, helpStyle.Render(fmt.Sprintf("Enter/y: %s | Esc/n: back to form", verb)))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
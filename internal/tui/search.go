@@ -0,0 +1,435 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/query"
+)
+
+// searchWorkers bounds how many zones are listed at once during a global
+// search, keeping well inside Cloudflare's rate limits.
+const searchWorkers = 4
+
+// zoneRecordsResult is the outcome of listing one zone's records.
+type zoneRecordsResult struct {
+	zone    api.Zone
+	records []api.DNSRecord
+	err     error
+}
+
+// searchZones lists the records of every zone with at most workers requests
+// in flight, sending each zone's result on out as it arrives. It returns
+// once every zone is done or ctx is cancelled.
+func searchZones(ctx context.Context, client *api.Client, zones []api.Zone, workers int, out chan<- zoneRecordsResult) {
+	jobs := make(chan api.Zone)
+	var wg sync.WaitGroup
+	for range min(workers, len(zones)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for zone := range jobs {
+				records, err := client.ListDNSRecords(ctx, zone.ID)
+				select {
+				case out <- zoneRecordsResult{zone: zone, records: records, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	for _, zone := range zones {
+		select {
+		case jobs <- zone:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// globalSearchMsg signals that the user wants to search every zone.
+type globalSearchMsg struct{}
+
+// closeSearchMsg signals that the user left the global search.
+type closeSearchMsg struct{}
+
+// jumpToRecordMsg asks the root model to show a record in its zone.
+type jumpToRecordMsg struct {
+	zone   api.Zone
+	record api.DNSRecord
+}
+
+// searchZonesMsg carries the zones a search will cover. id ties it, like the
+// other search messages, to the search that started it.
+type searchZonesMsg struct {
+	id    int
+	zones []api.Zone
+	err   error
+}
+
+// searchResultMsg carries one zone's records during a search.
+type searchResultMsg struct {
+	id     int
+	result zoneRecordsResult
+}
+
+// searchDoneMsg signals that every zone of a search has been listed.
+type searchDoneMsg struct {
+	id int
+}
+
+// searchHit is a matching record and the zone it belongs to.
+type searchHit struct {
+	zone   api.Zone
+	record api.DNSRecord
+}
+
+// SearchModel searches the records of every zone, streaming matches into a
+// table as each zone is listed.
+type SearchModel struct {
+	client *api.Client
+	input  textinput.Model
+	// expr parses the query as an expression; queryErr is its parse error.
+	// match is the compiled query of the current search.
+	expr     bool
+	queryErr error
+	match    func(api.DNSRecord) bool
+
+	// queries are the saved expressions, by name; savePrompt asks for the
+	// name to save the current one under.
+	queries    map[string]string
+	savePrompt queryPrompt
+
+	// id numbers the searches so results of an abandoned one are ignored;
+	// results is the channel the current one streams into and cancel stops it.
+	id      int
+	results chan zoneRecordsResult
+	cancel  context.CancelFunc
+
+	running  bool
+	zones    int
+	searched int
+	failed   []string
+	err      error
+	hits     []searchHit
+	table    table.Model
+	spinner  spinner.Model
+	width    int
+	height   int
+}
+
+// NewSearchModel creates an empty global search with the query focused.
+func NewSearchModel(client *api.Client, width, height int) SearchModel {
+	input := textinput.New()
+	input.Prompt = "Search all zones: "
+	input.Placeholder = "hostname, IP or content"
+	input.CharLimit = 256
+	input.Width = 40
+	input.Focus()
+
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	m := SearchModel{client: client, input: input, savePrompt: newQueryPrompt(), spinner: sp, width: width, height: height}
+	m.table = m.buildTable()
+	return m
+}
+
+// Init returns the text input blink command.
+func (m SearchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the global search.
+func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.table = m.buildTable()
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.running {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case searchZonesMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.running = false
+			m.err = msg.err
+			return m, nil
+		}
+		m.zones = len(msg.zones)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		m.cancel = cancel
+		m.results = make(chan zoneRecordsResult)
+		client, results, zones := m.client, m.results, msg.zones
+		run := func() tea.Msg {
+			searchZones(ctx, client, zones, searchWorkers, results)
+			close(results)
+			return nil
+		}
+		return m, tea.Batch(run, m.next())
+
+	case searchResultMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		m.searched++
+		res := msg.result
+		if res.err != nil {
+			m.failed = append(m.failed, res.zone.Name)
+			return m, m.next()
+		}
+		for _, r := range res.records {
+			if m.match(r) {
+				m.hits = append(m.hits, searchHit{zone: res.zone, record: r})
+			}
+		}
+		m.table = m.buildTable()
+		return m, m.next()
+
+	case searchDoneMsg:
+		if msg.id == m.id {
+			m.stop()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.savePrompt.active {
+			var cmd tea.Cmd
+			m.savePrompt, cmd = m.savePrompt.update(msg, m.queries)
+			return m, cmd
+		}
+		if m.input.Focused() {
+			switch msg.String() {
+			case "enter":
+				return m.start()
+			case "ctrl+e":
+				m.expr = !m.expr
+				m.queryErr = nil
+				return m, nil
+			case "ctrl+n":
+				if _, expr, ok := nextSavedQuery(m.queries, m.input.Value()); ok {
+					m.expr = true
+					m.queryErr = nil
+					m.input.SetValue(expr)
+					m.input.CursorEnd()
+				}
+				return m, nil
+			case "ctrl+s":
+				if m.expr && m.input.Value() != "" {
+					if _, err := query.Parse(m.input.Value()); err != nil {
+						m.queryErr = err
+						return m, nil
+					}
+					return m, m.savePrompt.open(m.input.Value())
+				}
+				return m, nil
+			case "esc":
+				if len(m.hits) == 0 {
+					m.stop()
+					return m, func() tea.Msg { return closeSearchMsg{} }
+				}
+				m.input.Blur()
+				m.table.Focus()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+		switch msg.String() {
+		case "/", "tab":
+			m.table.Blur()
+			return m, m.input.Focus()
+		case "esc", "q":
+			m.stop()
+			return m, func() tea.Msg { return closeSearchMsg{} }
+		case "enter":
+			if i := m.table.Cursor(); i >= 0 && i < len(m.hits) {
+				hit := m.hits[i]
+				return m, func() tea.Msg { return jumpToRecordMsg{zone: hit.zone, record: hit.record} }
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// start begins a new search for the query, abandoning any in progress.
+func (m SearchModel) start() (SearchModel, tea.Cmd) {
+	f := recordFilter{query: m.input.Value(), expr: m.expr}
+	if !f.active() {
+		return m, nil
+	}
+	match, err := f.predicate()
+	if err != nil {
+		m.queryErr = err
+		return m, nil
+	}
+	m.stop()
+	m.id++
+	m.match = match
+	m.queryErr = nil
+	m.running = true
+	m.zones, m.searched = 0, 0
+	m.failed, m.err, m.hits = nil, nil, nil
+	m.input.Blur()
+	m.table = m.buildTable()
+
+	client, id := m.client, m.id
+	listZones := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		zones, err := client.ListZones(ctx)
+		return searchZonesMsg{id: id, zones: zones, err: err}
+	}
+	return m, tea.Batch(m.spinner.Tick, listZones)
+}
+
+// next waits for the next zone's result, or reports the search done once
+// every zone has been listed.
+func (m SearchModel) next() tea.Cmd {
+	results, id := m.results, m.id
+	return func() tea.Msg {
+		res, ok := <-results
+		if !ok {
+			return searchDoneMsg{id: id}
+		}
+		return searchResultMsg{id: id, result: res}
+	}
+}
+
+// stop cancels the search in progress, if any.
+func (m *SearchModel) stop() {
+	m.running = false
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+// buildTable creates the results table, keeping the cursor where it was.
+func (m SearchModel) buildTable() table.Model {
+	width := max(m.width-4, 60)
+	zoneWidth := width / 5
+	nameWidth := (width - zoneWidth - 8) * 2 / 5
+	contentWidth := width - zoneWidth - 8 - nameWidth - 4*cellPadding
+	columns := []table.Column{
+		{Title: "Zone", Width: zoneWidth},
+		{Title: "Type", Width: 8},
+		{Title: "Name", Width: nameWidth},
+		{Title: "Content", Width: max(contentWidth, 12)},
+	}
+	rows := make([]table.Row, len(m.hits))
+	for i, hit := range m.hits {
+		rows[i] = table.Row{sanitize(hit.zone.Name), sanitize(hit.record.Type), sanitize(hit.record.Name), sanitize(hit.record.Content)}
+	}
+
+	h := m.height
+	if h == 0 {
+		h = 24
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithHeight(max(h-9, 3)),
+		table.WithWidth(width),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(true)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57"))
+	t.SetStyles(s)
+	if !m.input.Focused() {
+		t.Focus()
+	}
+	t.SetCursor(min(m.table.Cursor(), max(len(rows)-1, 0)))
+	return t
+}
+
+// View renders the query, progress and results.
+func (m SearchModel) View() string {
+	rowStyle := lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	header := lipgloss.NewStyle().Bold(true).Padding(1, 0, 0, 2).Render("Global Search")
+
+	statusStyle := rowStyle
+	var status string
+	switch {
+	case m.err != nil:
+		status = "Error listing zones: " + m.err.Error()
+	case m.running && m.zones == 0:
+		status = m.spinner.View() + " Listing zones…"
+	case m.running:
+		status = fmt.Sprintf("%s Searched %d/%d zones, %d matches", m.spinner.View(), m.searched, m.zones, len(m.hits))
+	case m.id > 0:
+		status = fmt.Sprintf("Searched %d zones, %d matches", m.searched, len(m.hits))
+	}
+	if len(m.failed) > 0 {
+		status += fmt.Sprintf("  (%d zones failed: %s)", len(m.failed), strings.Join(m.failed, ", "))
+	}
+	if m.err != nil || len(m.failed) > 0 {
+		statusStyle = statusStyle.Foreground(lipgloss.Color("196"))
+	}
+
+	helpText := "Enter: search | Ctrl+E: query mode | Ctrl+N: saved query | Esc: results / back | Ctrl+C: quit"
+	if m.expr {
+		helpText = "Enter: search | Ctrl+E: text mode | Ctrl+N: next saved query | Ctrl+S: save query | fields: " + strings.Join(query.Fields, " ")
+	}
+	if !m.input.Focused() {
+		helpText = "↑/↓: navigate | Enter: go to record | /: new search | q/Esc: back | Ctrl+C: quit"
+	}
+	if m.savePrompt.active {
+		helpText = "Enter: save query | Esc: cancel"
+	}
+	help := lipgloss.NewStyle().Faint(true).Padding(1, 0, 0, 2).Render(truncate(helpText, m.width-2))
+
+	mode := "[ ] query"
+	if m.expr {
+		mode = "[x] query"
+	}
+	status = statusStyle.Render(truncate(sanitize(status), m.width-2))
+	if m.queryErr != nil {
+		status = rowStyle.Render(queryErrorView(m.input.Value(), m.queryErr))
+	}
+	if m.savePrompt.active {
+		status = rowStyle.Render(m.savePrompt.input.View())
+	}
+	return header + "\n" +
+		rowStyle.Render(m.input.View()+"  "+lipgloss.NewStyle().Faint(true).Render(mode)) + "\n" +
+		status + "\n\n" +
+		m.table.View() + "\n" + help
+}
+
+// Running returns whether a search is in progress.
+func (m SearchModel) Running() bool {
+	return m.running
+}
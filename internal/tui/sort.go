@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// sortColumn identifies the records table column the rows are sorted by.
+// The non-zero values follow the table's column order.
+type sortColumn int
+
+const (
+	sortNone sortColumn = iota
+	sortType
+	sortName
+	sortContent
+	sortTTL
+	sortProxied
+	sortModified
+)
+
+// next returns the column after c, wrapping back to sortNone.
+func (c sortColumn) next() sortColumn {
+	return (c + 1) % (sortModified + 1)
+}
+
+// compare orders two records by the column. Names compare with their labels
+// reversed so subdomains group under their parent; ties fall back to the
+// reversed name so the order is stable across refreshes.
+func (c sortColumn) compare(a, b api.DNSRecord) int {
+	var n int
+	switch c {
+	case sortType:
+		n = cmp.Compare(a.Type, b.Type)
+	case sortContent:
+		n = cmp.Compare(strings.ToLower(a.Content), strings.ToLower(b.Content))
+	case sortTTL:
+		n = cmp.Compare(a.TTL, b.TTL)
+	case sortProxied:
+		n = cmp.Compare(boolRank(a.Proxied), boolRank(b.Proxied))
+	case sortModified:
+		n = a.ModifiedOn.Compare(b.ModifiedOn)
+	}
+	if n != 0 {
+		return n
+	}
+	return cmp.Compare(reverseName(a.Name), reverseName(b.Name))
+}
+
+// sortRows orders the record indices in rows by column c, descending if desc.
+func sortRows(records []api.DNSRecord, rows []int, c sortColumn, desc bool) {
+	slices.SortStableFunc(rows, func(i, j int) int {
+		n := c.compare(records[i], records[j])
+		if desc {
+			return -n
+		}
+		return n
+	})
+}
+
+// reverseName returns the lower-case name with its labels in reverse order,
+// e.g. "www.example.com" becomes "com.example.www".
+func reverseName(name string) string {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(name, ".")), ".")
+	slices.Reverse(labels)
+	return strings.Join(labels, ".")
+}
+
+// boolRank maps false and true onto 0 and 1 for ordering.
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
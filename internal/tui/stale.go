@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/plan"
+)
+
+// staleRecordMsg reports that the record was changed on Cloudflare, by
+// another user or tool, after the edit form was opened. pending is the
+// submission that was held back.
+type staleRecordMsg struct {
+	current api.DNSRecord
+	pending submitEditMsg
+}
+
+// recordChanged reports whether current differs from the snapshot the form
+// was opened with. The modification timestamp decides when both records have
+// one; otherwise every editable field is compared.
+func recordChanged(original, current api.DNSRecord) bool {
+	if !original.ModifiedOn.IsZero() && !current.ModifiedOn.IsZero() {
+		return !original.ModifiedOn.Equal(current.ModifiedOn)
+	}
+	a, b := plan.Fields(original), plan.Fields(current)
+	for i := range a {
+		if a[i] != b[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// paramsRecord returns the record that params would produce.
+func paramsRecord(id string, params api.UpdateDNSRecordParams) api.DNSRecord {
+	return api.DNSRecord{
+		ID:       id,
+		Type:     params.Type,
+		Name:     params.Name,
+		Content:  params.Content,
+		TTL:      params.TTL,
+		Proxied:  params.Proxied,
+		Priority: params.Priority,
+		Data:     params.Data,
+		Comment:  params.Comment,
+		Tags:     params.Tags,
+	}
+}
+
+// mergeRecords performs a field-level three-way merge: fields the user left
+// untouched take the other side's value, and fields the user changed keep
+// the user's value. clashes lists the fields both sides changed differently.
+func mergeRecords(original, theirs, mine api.DNSRecord) (merged api.DNSRecord, clashes []string) {
+	merged = mine
+	merged.CreatedOn = theirs.CreatedOn
+	merged.ModifiedOn = theirs.ModifiedOn
+	o, t, m := plan.Fields(original), plan.Fields(theirs), plan.Fields(mine)
+	for i, label := range plan.FieldLabels {
+		switch {
+		case m[i] == o[i] && t[i] != o[i]:
+			switch label {
+			case "Name":
+				merged.Name = theirs.Name
+			case "Content":
+				merged.Content, merged.Data = theirs.Content, theirs.Data
+			case "Priority":
+				merged.Priority = theirs.Priority
+			case "TTL":
+				merged.TTL = theirs.TTL
+			case "Proxied":
+				merged.Proxied = theirs.Proxied
+			case "Comment":
+				merged.Comment = theirs.Comment
+			case "Tags":
+				merged.Tags = theirs.Tags
+			}
+		case m[i] != o[i] && t[i] != o[i] && m[i] != t[i]:
+			clashes = append(clashes, label)
+		}
+	}
+	return merged, clashes
+}
+
+// staleView renders the original, theirs and mine versions of the record side
+// by side, highlighting fields that differ.
+func (m EditModel) staleView() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("196")).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	cellStyle := lipgloss.NewStyle().Width(28).Padding(0, 1)
+	labelStyle := cellStyle.Width(11).Bold(true).Padding(0, 1, 0, 2)
+	changedStyle := cellStyle.Foreground(lipgloss.Color("214")).Bold(true)
+	helpStyle := lipgloss.NewStyle().Faint(true).Padding(1, 0, 0, 2)
+
+	title := titleStyle.Render(" Record changed since it was loaded ")
+	lines := []string{headerStyle.Render(title + "  " + sanitize(m.record.Name))}
+	if !m.theirs.ModifiedOn.IsZero() {
+		lines = append(lines, lipgloss.NewStyle().Faint(true).Padding(0, 0, 1, 2).Render(
+			"Cloudflare copy modified "+m.theirs.ModifiedOn.Local().Format(timestampLayout)))
+	}
+
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+		labelStyle.Render(""),
+		cellStyle.Bold(true).Render("Original"),
+		cellStyle.Bold(true).Render("Theirs"),
+		cellStyle.Bold(true).Render("Mine"),
+	))
+	o := plan.Fields(m.record)
+	t := plan.Fields(m.theirs)
+	mine := plan.Fields(paramsRecord(m.record.ID, m.pending.params))
+	for i, label := range plan.FieldLabels {
+		if o[i] == "" && t[i] == "" && mine[i] == "" {
+			continue
+		}
+		theirStyle, myStyle := cellStyle, cellStyle
+		if t[i] != o[i] {
+			theirStyle = changedStyle
+		}
+		if mine[i] != o[i] {
+			myStyle = changedStyle
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render(label),
+			cellStyle.Render(sanitize(o[i])),
+			theirStyle.Render(sanitize(t[i])),
+			myStyle.Render(sanitize(mine[i])),
+		))
+	}
+
+	lines = append(lines, helpStyle.Render(
+		"o: overwrite with mine | m: merge and review | a/Esc: abort"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
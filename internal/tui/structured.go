@@ -0,0 +1,102 @@
+package tui
+
+import "strings"
+
+// dataField describes one input of a structured record form.
+type dataField struct {
+	key         string // key within api.DNSRecord.Data
+	label       string
+	placeholder string
+	numeric     bool
+	// inName marks SRV service and protocol labels, which are encoded in the
+	// record name (_service._proto.name) rather than in Data.
+	inName bool
+}
+
+// dataLayouts lists the form fields for each record type whose payload is
+// structured data on the Cloudflare side. Types without an entry use the
+// single Content input.
+var dataLayouts = map[string][]dataField{
+	"SRV": {
+		{key: "service", label: "Service", placeholder: "_sip", inName: true},
+		{key: "proto", label: "Proto", placeholder: "_tcp", inName: true},
+		{key: "priority", label: "Priority", placeholder: "0-65535", numeric: true},
+		{key: "weight", label: "Weight", placeholder: "0-65535", numeric: true},
+		{key: "port", label: "Port", placeholder: "0-65535", numeric: true},
+		{key: "target", label: "Target", placeholder: "host.example.com"},
+	},
+	"CAA": {
+		{key: "flags", label: "Flags", placeholder: "0 or 128", numeric: true},
+		{key: "tag", label: "Tag", placeholder: "issue | issuewild | iodef"},
+		{key: "value", label: "Value", placeholder: "letsencrypt.org"},
+	},
+	"HTTPS": svcbLayout,
+	"SVCB":  svcbLayout,
+	"TLSA": {
+		{key: "usage", label: "Usage", placeholder: "0-3", numeric: true},
+		{key: "selector", label: "Selector", placeholder: "0-1", numeric: true},
+		{key: "matching_type", label: "Matching", placeholder: "0-2", numeric: true},
+		{key: "certificate", label: "Cert", placeholder: "hex-encoded association data"},
+	},
+	"SSHFP": {
+		{key: "algorithm", label: "Algorithm", placeholder: "1=RSA 3=ECDSA 4=Ed25519", numeric: true},
+		{key: "type", label: "FP type", placeholder: "1=SHA-1 2=SHA-256", numeric: true},
+		{key: "fingerprint", label: "Finger", placeholder: "hex fingerprint"},
+	},
+	"NAPTR": {
+		{key: "order", label: "Order", placeholder: "0-65535", numeric: true},
+		{key: "preference", label: "Pref", placeholder: "0-65535", numeric: true},
+		{key: "flags", label: "Flags", placeholder: "S, A, U or P"},
+		{key: "service", label: "Service", placeholder: "SIP+D2U"},
+		{key: "regex", label: "Regex", placeholder: "!^.*$!sip:info@example.com!"},
+		{key: "replacement", label: "Replace", placeholder: "."},
+	},
+	"LOC": {
+		{key: "lat_degrees", label: "Lat deg", placeholder: "0-90", numeric: true},
+		{key: "lat_minutes", label: "Lat min", placeholder: "0-59", numeric: true},
+		{key: "lat_seconds", label: "Lat sec", placeholder: "0-59.999", numeric: true},
+		{key: "lat_direction", label: "Lat dir", placeholder: "N or S"},
+		{key: "long_degrees", label: "Long deg", placeholder: "0-180", numeric: true},
+		{key: "long_minutes", label: "Long min", placeholder: "0-59", numeric: true},
+		{key: "long_seconds", label: "Long sec", placeholder: "0-59.999", numeric: true},
+		{key: "long_direction", label: "Long dir", placeholder: "E or W"},
+		{key: "altitude", label: "Altitude", placeholder: "metres", numeric: true},
+		{key: "size", label: "Size", placeholder: "metres", numeric: true},
+		{key: "precision_horz", label: "H prec", placeholder: "metres", numeric: true},
+		{key: "precision_vert", label: "V prec", placeholder: "metres", numeric: true},
+	},
+	"URI": {
+		{key: "weight", label: "Weight", placeholder: "0-65535", numeric: true},
+		{key: "target", label: "Target", placeholder: "https://example.com/"},
+	},
+}
+
+// svcbLayout is shared by HTTPS and SVCB records.
+var svcbLayout = []dataField{
+	{key: "priority", label: "Priority", placeholder: "0 = alias mode", numeric: true},
+	{key: "target", label: "Target", placeholder: ". or host.example.com"},
+	{key: "value", label: "Params", placeholder: `alpn="h2,h3" ipv4hint=192.0.2.1`},
+}
+
+// splitServiceName splits an SRV owner name into its service and protocol
+// labels and the remaining host. ok is false if name does not start with two
+// underscore labels.
+func splitServiceName(name string) (service, proto, host string, ok bool) {
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) < 3 || !strings.HasPrefix(parts[0], "_") || !strings.HasPrefix(parts[1], "_") {
+		return "", "", name, false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// joinServiceName builds an SRV owner name from its parts, adding the leading
+// underscores if the user left them out.
+func joinServiceName(service, proto, host string) string {
+	label := func(s string) string {
+		if strings.HasPrefix(s, "_") {
+			return s
+		}
+		return "_" + s
+	}
+	return label(service) + "." + label(proto) + "." + host
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/validate"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// newTXTEditor creates the multi-line editor used for TXT content. Each line
+// holds one character-string, unquoted and unescaped; see txtEditorText.
+func newTXTEditor(content string) textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "One string per line; long lines are split at 255 bytes"
+	ta.CharLimit = validate.TXTContentMax
+	ta.SetWidth(64)
+	ta.SetHeight(6)
+	ta.SetValue(txtEditorText(content))
+	return ta
+}
+
+// txtEditorText converts stored TXT content into editor text. Quoted content
+// is shown one string per line with its quoting removed; anything else,
+// including content whose quotes do not parse, is shown as written.
+func txtEditorText(content string) string {
+	if !strings.HasPrefix(content, `"`) {
+		return content
+	}
+	chunks, err := zonefile.SplitTXT(content)
+	if err != nil {
+		return content
+	}
+	return strings.Join(chunks, "\n")
+}
+
+// txtEditorStrings splits editor text into the character-strings it will be
+// saved as: one per non-empty line, with lines longer than 255 bytes cut at
+// that boundary.
+func txtEditorStrings(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		out = append(out, zonefile.TXTStrings(line)...)
+	}
+	return out
+}
+
+// encodeTXT joins character-strings into TXT content. A single string that
+// needs no escaping is stored bare; otherwise every string is quoted, with
+// quotes and backslashes escaped.
+func encodeTXT(chunks []string) string {
+	if len(chunks) == 1 && !strings.ContainsAny(chunks[0], `"\`) && strings.TrimSpace(chunks[0]) == chunks[0] {
+		return chunks[0]
+	}
+	quoted := make([]string, len(chunks))
+	for i, c := range chunks {
+		c = strings.ReplaceAll(c, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(c, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// txtBoundaries summarises how editor text will be stored, e.g.
+// "3 strings: 255 + 255 + 90 bytes, 612/2048 characters".
+func txtBoundaries(chunks []string, content string) string {
+	if len(chunks) == 0 {
+		return "No strings"
+	}
+	sizes := make([]string, len(chunks))
+	for i, c := range chunks {
+		sizes[i] = fmt.Sprint(len(c))
+	}
+	noun := "strings"
+	if len(chunks) == 1 {
+		noun = "string"
+	}
+	return fmt.Sprintf("%d %s: %s bytes, %d/%d characters",
+		len(chunks), noun, strings.Join(sizes, " + "), len(content), validate.TXTContentMax)
+}
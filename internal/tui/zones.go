@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -46,6 +47,9 @@ func NewZonesModel(client *api.Client) ZonesModel {
 	delegate := list.NewDefaultDelegate()
 	l := list.New(nil, delegate, 80, 24)
 	l.Title = "Cloudflare Zones"
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "search all zones"))}
+	}
 
 	return ZonesModel{
 		client:  client,
@@ -99,6 +103,9 @@ func (m ZonesModel) Update(msg tea.Msg) (ZonesModel, tea.Cmd) {
 		return m, cmd
 
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+f" && m.list.FilterState() != list.Filtering {
+			return m, func() tea.Msg { return globalSearchMsg{} }
+		}
 		if !m.loading && m.err == nil {
 			if msg.String() == "enter" && m.list.FilterState() != list.Filtering {
 				if selected := m.list.SelectedItem(); selected != nil {
@@ -0,0 +1,188 @@
+// Package validate checks DNS records against the rules Cloudflare applies,
+// so that a bad record is caught before it is sent. The edit form reports
+// each problem beside its field, and commands that change records without
+// the form check them all at once with Record.
+//
+// The field checks return a message, or "" when the value is acceptable.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+	"github.com/Azahorscak/cloudflare-tui/internal/zonefile"
+)
+
+// TTL limits enforced by Cloudflare. 1 means "Auto". Enterprise zones may go
+// down to 30 seconds, but the API does not tell us the plan, so the common
+// minimum applies.
+const (
+	TTLAuto = 1
+	TTLMin  = 60
+	TTLMax  = 86400
+)
+
+// TXTContentMax is the most characters Cloudflare accepts as TXT content.
+const TXTContentMax = 2048
+
+// contentValidators checks the Content field of simple record types. Types
+// without an entry only need non-empty content.
+var contentValidators = map[string]func(string) string{
+	"A":     validateIPv4,
+	"AAAA":  validateIPv6,
+	"CNAME": validateTarget,
+	"NS":    validateTarget,
+	"MX":    validateTarget,
+	"PTR":   validateTarget,
+	"TXT":   validateTXT,
+}
+
+// proxiableTypes lists the record types Cloudflare can proxy.
+var proxiableTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true}
+
+// Content checks the content of a record of the given type. Only simple
+// types have their content checked; any content passes for the others.
+func Content(recordType, content string) string {
+	if check, ok := contentValidators[recordType]; ok {
+		return check(content)
+	}
+	return ""
+}
+
+// Proxiable reports whether Cloudflare can proxy records of the given type.
+func Proxiable(recordType string) bool {
+	return proxiableTypes[recordType]
+}
+
+// validateIPv4 requires a dotted-quad IPv4 address.
+func validateIPv4(s string) string {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is4() {
+		return "A records need an IPv4 address, e.g. 192.0.2.1"
+	}
+	return ""
+}
+
+// validateIPv6 requires an IPv6 address. IPv4-mapped forms are rejected since
+// they belong in an A record.
+func validateIPv6(s string) string {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is6() || addr.Is4In6() {
+		return "AAAA records need an IPv6 address, e.g. 2001:db8::1"
+	}
+	if addr.Zone() != "" {
+		return "AAAA records cannot carry a zone index"
+	}
+	return ""
+}
+
+// validateTarget requires a hostname, as used by CNAME, NS, MX and PTR
+// records, and rejects IP addresses.
+func validateTarget(s string) string {
+	if _, err := netip.ParseAddr(s); err == nil {
+		return "Target must be a hostname, not an IP address"
+	}
+	return validateHostname(s)
+}
+
+// validateHostname checks hostname syntax: at most 253 characters, labels of
+// 1-63 letters, digits, hyphens or underscores that do not start or end with
+// a hyphen. A single trailing dot is allowed.
+func validateHostname(s string) string {
+	name := strings.TrimSuffix(s, ".")
+	if name == "" {
+		return "Hostname must be non-empty"
+	}
+	if len(name) > 253 {
+		return "Hostname must be at most 253 characters"
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return "Hostname must not contain empty labels"
+		}
+		if len(label) > 63 {
+			return fmt.Sprintf("Label %q is longer than 63 characters", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Sprintf("Label %q must not start or end with a hyphen", label)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return fmt.Sprintf("Label %q contains invalid character %q", label, r)
+			}
+		}
+	}
+	return ""
+}
+
+// validateTXT checks TXT content length and quoting. Unquoted content is
+// split into character-strings by Cloudflare; quoted content must consist of
+// balanced strings of at most 255 bytes each.
+func validateTXT(s string) string {
+	if len(s) > TXTContentMax {
+		return fmt.Sprintf("TXT content is %d characters; the limit is %d", len(s), TXTContentMax)
+	}
+	chunks, err := zonefile.SplitTXT(s)
+	if err != nil {
+		return "Invalid TXT content: " + err.Error()
+	}
+	if !strings.HasPrefix(s, `"`) {
+		return ""
+	}
+	for i, c := range chunks {
+		if len(c) > zonefile.TXTStringMax {
+			return fmt.Sprintf("Quoted string %d is %d bytes; each string is limited to %d", i+1, len(c), zonefile.TXTStringMax)
+		}
+	}
+	return ""
+}
+
+// TTL checks a TTL against Cloudflare's range. Proxied records are always
+// served with an automatic TTL, so they must use Auto.
+func TTL(ttl int, proxied bool) string {
+	if proxied && ttl != TTLAuto {
+		return "Proxied records must use an Auto TTL"
+	}
+	if ttl != TTLAuto && (ttl < TTLMin || ttl > TTLMax) {
+		return fmt.Sprintf("TTL must be \"Auto\" or between %d and %d seconds", TTLMin, TTLMax)
+	}
+	return ""
+}
+
+// Proxied rejects proxying for record types Cloudflare cannot proxy.
+func Proxied(recordType string, proxied bool) string {
+	if proxied && !proxiableTypes[recordType] {
+		return "Only A, AAAA and CNAME records can be proxied"
+	}
+	return ""
+}
+
+// Record applies the edit form's checks to params, for callers that change
+// records without the form. Structured records carry their content in Data,
+// so only simple types have their content checked.
+func Record(params api.UpdateDNSRecordParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		return errors.New("name must be non-empty")
+	}
+	if !api.UsesData(params.Type) {
+		if params.Content == "" {
+			return errors.New("content must be non-empty")
+		}
+		if msg := Content(params.Type, params.Content); msg != "" {
+			return errors.New(msg)
+		}
+	}
+	if api.UsesPriority(params.Type) && (params.Priority < 0 || params.Priority > 65535) {
+		return errors.New("priority must be an integer between 0 and 65535")
+	}
+	if msg := Proxied(params.Type, params.Proxied); msg != "" {
+		return errors.New(msg)
+	}
+	if msg := TTL(params.TTL, params.Proxied); msg != "" {
+		return errors.New(msg)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+func TestValidateTXT(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr string
+	}{
+		{`"part one" "part two"`, ""},
+		{`"unterminated`, "unbalanced"},
+		{`"` + strings.Repeat("a", 256) + `"`, "255"},
+		{`say "hi"`, "bare quote"},
+	}
+	for _, tt := range tests {
+		if got := validateTXT(tt.content); (tt.wantErr == "") != (got == "") || !strings.Contains(got, tt.wantErr) {
+			t.Errorf("validateTXT(%q) = %q, want error containing %q", tt.content, got, tt.wantErr)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		params  api.UpdateDNSRecordParams
+		wantErr string
+	}{
+		{api.UpdateDNSRecordParams{Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 300}, ""},
+		{api.UpdateDNSRecordParams{Type: "A", Name: "", Content: "192.0.2.1", TTL: 300}, "name"},
+		{api.UpdateDNSRecordParams{Type: "AAAA", Name: "www.example.com", Content: "192.0.2.1", TTL: 300}, "IPv6"},
+		{api.UpdateDNSRecordParams{Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 300, Proxied: true}, "Auto TTL"},
+		{api.UpdateDNSRecordParams{Type: "TXT", Name: "www.example.com", Content: "v=spf1", TTL: 1, Proxied: true}, "can be proxied"},
+		{api.UpdateDNSRecordParams{Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 300, Priority: 70000}, "priority"},
+		{api.UpdateDNSRecordParams{Type: "SRV", Name: "_sip._tcp.example.com", TTL: 300, Data: map[string]any{"port": 5060}}, ""},
+	}
+	for _, tt := range tests {
+		err := Record(tt.params)
+		if (tt.wantErr == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Record(%s %q) = %v, want error containing %q", tt.params.Type, tt.params.Content, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package zonefile
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// TXTStringMax is the most bytes a TXT character-string holds on the wire.
+const TXTStringMax = 255
+
+// Render renders records as a BIND zone file, for tokens that may list
+// records but not use Cloudflare's export endpoint. Records are grouped by
+// type like Cloudflare's own export, and proxied records carry the same
+// cf_tags comment so the file can be imported again. An automatic TTL is
+// written as 300 seconds, the value Cloudflare serves for it, and tagged
+// cf-ttl:auto so that an import keeps it automatic. The SOA record, which
+// Cloudflare manages, is not included.
+func Render(zoneName string, records []api.DNSRecord) string {
+	return render(zoneName, records, false)
+}
+
+// RenderForImport renders records as a zone file for Cloudflare's import
+// endpoint, which reads a TTL of 1 as automatic.
+func RenderForImport(zoneName string, records []api.DNSRecord) string {
+	return render(zoneName, records, true)
+}
+
+// render renders records as a zone file. endpoint writes an automatic TTL
+// as 1 instead of as a tagged 300.
+func render(zoneName string, records []api.DNSRecord, endpoint bool) string {
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted, func(a, b api.DNSRecord) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)))
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ";; Domain:     %s\n", fqdn(zoneName))
+	b.WriteString(";; Rendered from the DNS record list; the SOA record is not included.\n")
+	fmt.Fprintf(&b, "\n$ORIGIN %s\n", fqdn(zoneName))
+	for i, r := range sorted {
+		if i == 0 || r.Type != sorted[i-1].Type {
+			fmt.Fprintf(&b, "\n;; %s Records\n", r.Type)
+		}
+		b.WriteString(recordLine(r, endpoint))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// recordLine renders one record as a zone file line, with its comment and
+// Cloudflare tags after a semicolon.
+func recordLine(r api.DNSRecord, endpoint bool) string {
+	rdata := RData(r.Type, r.Content, r.Priority, r.Data)
+	if r.Type == "TXT" {
+		chunks := TXTStrings(r.Content)
+		for i, c := range chunks {
+			chunks[i] = Quote(c)
+		}
+		rdata = strings.Join(chunks, " ")
+	}
+	line := Line(r.Name, r.TTL, r.Type, rdata)
+	if endpoint {
+		line = rrLine(r.Name, r.TTL, r.Type, rdata) // 1 is automatic to Cloudflare
+	}
+
+	var tags []string
+	if r.Proxied {
+		tags = append(tags, "cf-proxied:true")
+	}
+	if r.TTL == 1 && !endpoint {
+		tags = append(tags, "cf-ttl:auto")
+	}
+	var notes []string
+	if r.Comment != "" {
+		notes = append(notes, strings.Join(strings.Fields(r.Comment), " "))
+	}
+	if len(tags) > 0 {
+		notes = append(notes, "cf_tags="+strings.Join(tags, ","))
+	}
+	if len(notes) > 0 {
+		line += " ; " + strings.Join(notes, " ")
+	}
+	return line
+}
+
+// Line renders a full zone file resource record line. An automatic TTL is
+// written as 300 seconds, the value Cloudflare serves for it.
+func Line(name string, ttl int, recordType, rdata string) string {
+	if ttl == 1 {
+		ttl = 300
+	}
+	return rrLine(name, ttl, recordType, rdata)
+}
+
+// rrLine renders a zone file resource record line with ttl as given.
+func rrLine(name string, ttl int, recordType, rdata string) string {
+	return fmt.Sprintf("%s %d IN %s %s", fqdn(name), ttl, recordType, rdata)
+}
+
+// RData renders the RDATA of a record as it appears in a zone file, for
+// example "10 5 5060 sip.example.com." for an SRV record. Types without
+// structured data fall back to content (prefixed with priority for MX).
+func RData(recordType, content string, priority int, data map[string]any) string {
+	v := func(key string) string { return DataValue(data, key) }
+	switch recordType {
+	case "MX":
+		return strconv.Itoa(priority) + " " + fqdn(content)
+	case "CNAME", "NS", "PTR":
+		return fqdn(content)
+	case "SRV":
+		return strings.Join([]string{v("priority"), v("weight"), v("port"), fqdn(v("target"))}, " ")
+	case "CAA":
+		return v("flags") + " " + v("tag") + " " + Quote(v("value"))
+	case "HTTPS", "SVCB":
+		target := v("target")
+		if target != "." {
+			target = fqdn(target)
+		}
+		return strings.TrimSpace(v("priority") + " " + target + " " + v("value"))
+	case "TLSA":
+		return strings.Join([]string{v("usage"), v("selector"), v("matching_type"), v("certificate")}, " ")
+	case "SSHFP":
+		return strings.Join([]string{v("algorithm"), v("type"), v("fingerprint")}, " ")
+	case "NAPTR":
+		replacement := v("replacement")
+		if replacement != "." {
+			replacement = fqdn(replacement)
+		}
+		return strings.Join([]string{
+			v("order"), v("preference"), Quote(v("flags")), Quote(v("service")), Quote(v("regex")), replacement,
+		}, " ")
+	case "LOC":
+		return strings.Join([]string{
+			v("lat_degrees"), v("lat_minutes"), v("lat_seconds"), v("lat_direction"),
+			v("long_degrees"), v("long_minutes"), v("long_seconds"), v("long_direction"),
+			v("altitude") + "m", v("size") + "m", v("precision_horz") + "m", v("precision_vert") + "m",
+		}, " ")
+	case "URI":
+		return strconv.Itoa(priority) + " " + v("weight") + " " + Quote(v("target"))
+	}
+	return content
+}
+
+// DataValue formats one Data component as it is written in a zone file:
+// numbers without a trailing ".0", strings as they are.
+func DataValue(data map[string]any, key string) string {
+	switch v := data[key].(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fqdn returns name with a trailing dot, as written in a zone file.
+func fqdn(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// Quote wraps s in double quotes, escaping embedded quotes and backslashes.
+func Quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// TXTStrings returns the character-strings a TXT record is served as.
+// Quoted content is split as written; unquoted content is cut into strings
+// of at most 255 bytes, as Cloudflare does, without splitting a UTF-8
+// sequence.
+func TXTStrings(content string) []string {
+	if strings.HasPrefix(content, `"`) {
+		if chunks, err := SplitTXT(content); err == nil {
+			return chunks
+		}
+	}
+	var chunks []string
+	for len(content) > TXTStringMax {
+		cut := TXTStringMax
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		chunks = append(chunks, content[:cut])
+		content = content[cut:]
+	}
+	return append(chunks, content)
+}
+
+// SplitTXT parses TXT content into its character-strings. Content that does
+// not start with a quote is returned as a single unquoted string, which must
+// not contain bare quotes. Escaped quotes and backslashes are unescaped.
+func SplitTXT(s string) ([]string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		for i := 0; i < len(s); i++ {
+			if s[i] == '\\' {
+				i++
+				continue
+			}
+			if s[i] == '"' {
+				return nil, errors.New("bare quote in unquoted content; quote every string")
+			}
+		}
+		return []string{s}, nil
+	}
+
+	var chunks []string
+	i := 0
+	for i < len(s) {
+		if s[i] == ' ' || s[i] == '\t' {
+			i++
+			continue
+		}
+		if s[i] != '"' {
+			return nil, fmt.Errorf("text outside quotes at position %d", i+1)
+		}
+		var b strings.Builder
+		closed := false
+		for i++; i < len(s); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+				continue
+			}
+			if s[i] == '"' {
+				closed = true
+				i++
+				break
+			}
+			b.WriteByte(s[i])
+		}
+		if !closed {
+			return nil, errors.New("unbalanced quotes")
+		}
+		chunks = append(chunks, b.String())
+	}
+	return chunks, nil
+}
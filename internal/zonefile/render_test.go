@@ -0,0 +1,114 @@
+package zonefile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+func TestRData(t *testing.T) {
+	tests := []struct {
+		recordType string
+		content    string
+		priority   int
+		data       map[string]any
+		want       string
+	}{
+		{"A", "192.0.2.1", 0, nil, "192.0.2.1"},
+		{"MX", "mail.example.com", 10, nil, "10 mail.example.com."},
+		{"HTTPS", "", 0, map[string]any{"priority": float64(1), "target": ".", "value": `alpn="h2"`}, `1 . alpn="h2"`},
+		{"TLSA", "", 0, map[string]any{"usage": float64(3), "selector": float64(1), "matching_type": float64(1), "certificate": "abcd"}, "3 1 1 abcd"},
+		{"SSHFP", "", 0, map[string]any{"algorithm": float64(4), "type": float64(2), "fingerprint": "ff00"}, "4 2 ff00"},
+		{"NAPTR", "", 0, map[string]any{"order": float64(100), "preference": float64(10), "flags": "S", "service": "SIP+D2U", "regex": "", "replacement": "_sip._udp.example.com"},
+			`100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`},
+		{"LOC", "", 0, map[string]any{
+			"lat_degrees": float64(51), "lat_minutes": float64(30), "lat_seconds": 12.5, "lat_direction": "N",
+			"long_degrees": float64(0), "long_minutes": float64(7), "long_seconds": float64(39), "long_direction": "W",
+			"altitude": float64(0), "size": float64(1), "precision_horz": float64(10000), "precision_vert": float64(10),
+		}, "51 30 12.5 N 0 7 39 W 0m 1m 10000m 10m"},
+		{"URI", "", 5, map[string]any{"weight": float64(1), "target": "https://example.com/"}, `5 1 "https://example.com/"`},
+	}
+	for _, tt := range tests {
+		if got := RData(tt.recordType, tt.content, tt.priority, tt.data); got != tt.want {
+			t.Errorf("RData(%s) = %q, want %q", tt.recordType, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTXT(t *testing.T) {
+	got, err := SplitTXT(`"a b" "c\"d"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"a b", `c"d`}) {
+		t.Errorf("unexpected chunks: %q", got)
+	}
+	if _, err := SplitTXT(`"a" b`); err == nil {
+		t.Error("expected error for text outside quotes")
+	}
+}
+
+func TestTXTStrings(t *testing.T) {
+	long := strings.Repeat("a", 600)
+	chunks := TXTStrings(long)
+	var sizes []int
+	for _, c := range chunks {
+		sizes = append(sizes, len(c))
+	}
+	if !reflect.DeepEqual(sizes, []int{255, 255, 90}) {
+		t.Errorf("expected 255/255/90 byte chunks, got %v", sizes)
+	}
+	if got := TXTStrings(`"v=spf1" "-all"`); !reflect.DeepEqual(got, []string{"v=spf1", "-all"}) {
+		t.Errorf("expected quoted strings split as written, got %q", got)
+	}
+	// Multi-byte runes are never split across chunks.
+	for _, c := range TXTStrings(strings.Repeat("é", 200)) {
+		if !utf8.ValidString(c) {
+			t.Errorf("chunk split a rune: %q", c)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	records := []api.DNSRecord{
+		{Type: "MX", Name: "example.com", Content: "mail.example.com", TTL: 300, Priority: 10},
+		{Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true, Comment: "web\nfront"},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 -all", TTL: 3600},
+		{Type: "A", Name: "api.example.com", Content: "192.0.2.2", TTL: 120},
+		{Type: "SRV", Name: "_sip._tcp.example.com", TTL: 300, Data: map[string]any{"priority": 10.0, "weight": 5.0, "port": 5060.0, "target": "sip.example.com"}},
+	}
+	got := Render("example.com", records)
+	want := []string{
+		"$ORIGIN example.com.",
+		";; A Records",
+		"api.example.com. 120 IN A 192.0.2.2",
+		"www.example.com. 300 IN A 192.0.2.1 ; web front cf_tags=cf-proxied:true,cf-ttl:auto",
+		";; MX Records",
+		"example.com. 300 IN MX 10 mail.example.com.",
+		";; SRV Records",
+		"_sip._tcp.example.com. 300 IN SRV 10 5 5060 sip.example.com.",
+		";; TXT Records",
+		`example.com. 3600 IN TXT "v=spf1 -all"`,
+	}
+	last := -1
+	for _, line := range want {
+		i := strings.Index(got, line+"\n")
+		if i < 0 {
+			t.Errorf("missing line %q in:\n%s", line, got)
+			continue
+		}
+		if i < last {
+			t.Errorf("line %q is out of order in:\n%s", line, got)
+		}
+		last = i
+	}
+
+	// Cloudflare's import endpoint reads 1 as automatic, so that file keeps it.
+	endpoint := RenderForImport("example.com", records)
+	if line := "www.example.com. 1 IN A 192.0.2.1 ; web front cf_tags=cf-proxied:true\n"; !strings.Contains(endpoint, line) {
+		t.Errorf("missing line %q in:\n%s", line, endpoint)
+	}
+}
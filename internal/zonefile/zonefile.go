@@ -0,0 +1,590 @@
+// Package zonefile parses BIND zone files into DNS records and renders
+// records as zone files.
+//
+// The parser understands the master file format of RFC 1035: $ORIGIN and
+// $TTL directives, "@" and names relative to the origin, blank owners that
+// repeat the previous one, optional TTL and class in either order, quoted
+// strings, and parentheses that continue a record over several lines.
+// Record data is split into the fields Cloudflare stores, so SRV, CAA,
+// HTTPS, SVCB, TLSA, SSHFP, NAPTR, LOC and URI records carry structured
+// Data like records read from the API.
+//
+// A "cf_tags=cf-proxied:true" comment, as written by Cloudflare's export,
+// marks a proxied record, and a cf-ttl:auto tag a record whose TTL is
+// automatic whatever the file says; any other comment text becomes the
+// record comment. A record without cf_tags says nothing about either, which
+// Record reports so that an import can keep the zone's settings.
+package zonefile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+// Record is a record read from a zone file. Tagged is set when its comment
+// has cf_tags, so that Proxied and an automatic TTL come from the file rather
+// than being left to the zone.
+type Record struct {
+	api.DNSRecord
+	Tagged bool
+}
+
+// Error is a problem with one line of a zone file.
+type Error struct {
+	Line int // 1-based line where the record starts
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// token is one field of a record. quoted is set for "..." strings, which
+// are never names or keywords.
+type token struct {
+	text   string
+	quoted bool
+}
+
+// entry is one logical line: a directive or a record, with the text of its
+// comments.
+type entry struct {
+	line     int
+	indented bool
+	tokens   []token
+	comment  string
+}
+
+// Parse reads the records in src. origin is the zone name, used for
+// relative names until a $ORIGIN directive changes it. Names are returned
+// without their trailing dot. SOA records are skipped because Cloudflare
+// manages the zone's SOA itself. A record without a TTL takes the $TTL
+// value, else the previous record's TTL, else 1 (automatic).
+func Parse(src, origin string) ([]Record, error) {
+	entries, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{origin: strings.TrimSuffix(origin, ".")}
+	var records []Record
+	for _, e := range entries {
+		r, ok, err := p.entry(e)
+		if err != nil {
+			return nil, &Error{Line: e.line, Msg: err.Error()}
+		}
+		if ok {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// lex splits src into entries, joining lines inside parentheses and
+// decoding quoted strings and escapes.
+func lex(src string) ([]entry, error) {
+	var entries []entry
+	var cur entry
+	var tok strings.Builder
+	inToken, depth, line, parenLine := false, 0, 1, 0
+	atLineStart := true
+
+	flush := func(quoted bool) {
+		if inToken || quoted {
+			cur.tokens = append(cur.tokens, token{text: tok.String(), quoted: quoted})
+		}
+		tok.Reset()
+		inToken = false
+	}
+	endEntry := func() {
+		if len(cur.tokens) > 0 {
+			entries = append(entries, cur)
+		}
+		cur = entry{}
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if atLineStart && depth == 0 {
+			cur = entry{line: line, indented: c == ' ' || c == '\t'}
+		}
+		atLineStart = false
+		switch {
+		case c == '\n':
+			flush(false)
+			line++
+			atLineStart = true
+			if depth == 0 {
+				endEntry()
+			}
+		case c == ';':
+			flush(false)
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			if text := strings.TrimSpace(src[i+1 : i+end]); text != "" {
+				cur.comment = strings.TrimSpace(cur.comment + " " + text)
+			}
+			i += end - 1
+		case c == ' ' || c == '\t' || c == '\r':
+			flush(false)
+		case c == '(':
+			flush(false)
+			if depth == 0 {
+				parenLine = line
+			}
+			depth++
+		case c == ')':
+			flush(false)
+			if depth == 0 {
+				return nil, &Error{Line: line, Msg: "unexpected )"}
+			}
+			depth--
+		case c == '"' && !inToken:
+			start := line
+			i++
+			for ; i < len(src) && src[i] != '"'; i++ {
+				switch src[i] {
+				case '\n':
+					return nil, &Error{Line: start, Msg: "unterminated quoted string"}
+				case '\\':
+					n, width := unescape(src[i:])
+					tok.WriteString(n)
+					i += width - 1
+				default:
+					tok.WriteByte(src[i])
+				}
+			}
+			if i == len(src) {
+				return nil, &Error{Line: start, Msg: "unterminated quoted string"}
+			}
+			flush(true)
+		case c == '\\':
+			n, width := unescape(src[i:])
+			tok.WriteString(n)
+			inToken = true
+			i += width - 1
+		default:
+			tok.WriteByte(c)
+			inToken = true
+		}
+	}
+	if depth > 0 {
+		return nil, &Error{Line: parenLine, Msg: "( is never closed"}
+	}
+	flush(false)
+	endEntry()
+	return entries, nil
+}
+
+// unescape decodes the escape at the start of s: \DDD is a byte in decimal
+// and \X is X. It returns the text and the number of bytes consumed.
+func unescape(s string) (string, int) {
+	if len(s) >= 4 && isDigit(s[1]) && isDigit(s[2]) && isDigit(s[3]) {
+		if n, err := strconv.Atoi(s[1:4]); err == nil && n < 256 {
+			return string([]byte{byte(n)}), 4
+		}
+	}
+	if len(s) >= 2 {
+		return s[1:2], 2
+	}
+	return `\`, 1
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// parser holds the state carried from one entry to the next.
+type parser struct {
+	origin     string
+	defaultTTL int // from $TTL; 0 if unset
+	lastOwner  string
+	lastTTL    int
+}
+
+// entry handles one directive or record. ok is false for directives and
+// skipped records.
+func (p *parser) entry(e entry) (r Record, ok bool, err error) {
+	toks := e.tokens
+	if first := toks[0]; !first.quoted && strings.HasPrefix(first.text, "$") && !e.indented {
+		return r, false, p.directive(first.text, toks[1:])
+	}
+
+	owner := p.lastOwner
+	if !e.indented {
+		owner = p.absolute(toks[0].text)
+		toks = toks[1:]
+	} else if owner == "" {
+		return r, false, fmt.Errorf("record has no owner name and follows no other record")
+	}
+	p.lastOwner = owner
+
+	ttl := -1
+	for range 2 {
+		if len(toks) == 0 || toks[0].quoted {
+			break
+		}
+		if class := strings.ToUpper(toks[0].text); class == "IN" || class == "CH" || class == "HS" || class == "CS" {
+			if class != "IN" {
+				return r, false, fmt.Errorf("class %s is not supported", class)
+			}
+			toks = toks[1:]
+			continue
+		}
+		if n, err := parseTTL(toks[0].text); err == nil && ttl < 0 {
+			ttl = n
+			toks = toks[1:]
+			continue
+		}
+		break
+	}
+	switch {
+	case ttl >= 0:
+		p.lastTTL = ttl
+	case p.defaultTTL > 0:
+		ttl = p.defaultTTL
+	case p.lastTTL > 0:
+		ttl = p.lastTTL
+	default:
+		ttl = 1
+	}
+
+	if len(toks) == 0 {
+		return r, false, fmt.Errorf("missing record type")
+	}
+	r.DNSRecord = api.DNSRecord{Type: strings.ToUpper(toks[0].text), Name: owner, TTL: ttl}
+	if r.Type == "SOA" {
+		return r, false, nil
+	}
+	if err := p.rdata(&r.DNSRecord, toks[1:]); err != nil {
+		return r, false, fmt.Errorf("%s record: %w", r.Type, err)
+	}
+	var auto bool
+	r.Proxied, auto, r.Tagged, r.Comment = cfTags(e.comment)
+	if auto {
+		r.TTL = 1
+	}
+	return r, true, nil
+}
+
+// directive applies $ORIGIN or $TTL.
+func (p *parser) directive(name string, args []token) error {
+	switch strings.ToUpper(name) {
+	case "$ORIGIN":
+		if len(args) != 1 {
+			return fmt.Errorf("$ORIGIN takes one domain name")
+		}
+		p.origin = p.absolute(args[0].text)
+	case "$TTL":
+		if len(args) != 1 {
+			return fmt.Errorf("$TTL takes one value")
+		}
+		n, err := parseTTL(args[0].text)
+		if err != nil {
+			return err
+		}
+		p.defaultTTL = n
+	case "$INCLUDE":
+		return fmt.Errorf("$INCLUDE is not supported; paste the included file instead")
+	default:
+		return fmt.Errorf("unknown directive %s", name)
+	}
+	return nil
+}
+
+// absolute resolves name against the origin and drops its trailing dot.
+func (p *parser) absolute(name string) string {
+	switch {
+	case name == "@":
+		return p.origin
+	case name == ".":
+		return "."
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	case p.origin == "":
+		return name
+	}
+	return name + "." + p.origin
+}
+
+// target resolves a name in record data, keeping "." (no target) as is.
+func (p *parser) target(name string) string {
+	if name == "." {
+		return name
+	}
+	return p.absolute(name)
+}
+
+// parseTTL reads a TTL in seconds, also accepting BIND units such as 1h30m.
+func parseTTL(s string) (int, error) {
+	if s == "" || !isDigit(s[0]) {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, n, digits := 0, 0, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isDigit(c) {
+			n = n*10 + int(c-'0')
+			digits = true
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || !digits {
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+		total += n * unit
+		n, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	return total, nil
+}
+
+// cfTags reads the proxy and automatic TTL markers from a comment and
+// returns the rest of the comment. tagged reports whether it has cf_tags.
+func cfTags(comment string) (proxied, autoTTL, tagged bool, rest string) {
+	var words []string
+	for _, w := range strings.Fields(comment) {
+		if tags, ok := strings.CutPrefix(w, "cf_tags="); ok {
+			tagged = true
+			for _, tag := range strings.Split(tags, ",") {
+				switch tag {
+				case "cf-proxied:true":
+					proxied = true
+				case "cf-ttl:auto":
+					autoTTL = true
+				}
+			}
+			continue
+		}
+		words = append(words, w)
+	}
+	return proxied, autoTTL, tagged, strings.Join(words, " ")
+}
+
+// rdata fills in the record data of r from the fields after its type.
+func (p *parser) rdata(r *api.DNSRecord, f []token) error {
+	texts := make([]string, len(f))
+	for i, t := range f {
+		texts[i] = t.text
+	}
+	want := func(n int) error {
+		if len(f) != n {
+			return fmt.Errorf("expected %d fields, found %d", n, len(f))
+		}
+		return nil
+	}
+	atLeast := func(n int) error {
+		if len(f) < n {
+			return fmt.Errorf("expected at least %d fields, found %d", n, len(f))
+		}
+		return nil
+	}
+	// nums reads numeric fields into Data, starting at field from.
+	nums := func(from int, names ...string) (map[string]any, error) {
+		data := make(map[string]any, len(names))
+		for i, name := range names {
+			n, err := strconv.ParseFloat(texts[from+i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s %q is not a number", strings.ReplaceAll(name, "_", " "), texts[from+i])
+			}
+			data[name] = n
+		}
+		return data, nil
+	}
+
+	switch r.Type {
+	case "A", "AAAA":
+		if err := want(1); err != nil {
+			return err
+		}
+		r.Content = texts[0]
+	case "CNAME", "NS", "PTR", "DNAME":
+		if err := want(1); err != nil {
+			return err
+		}
+		r.Content = p.absolute(texts[0])
+	case "MX":
+		if err := want(2); err != nil {
+			return err
+		}
+		prio, err := strconv.Atoi(texts[0])
+		if err != nil {
+			return fmt.Errorf("preference %q is not a number", texts[0])
+		}
+		r.Priority = prio
+		r.Content = p.target(texts[1])
+	case "TXT", "SPF":
+		if err := atLeast(1); err != nil {
+			return err
+		}
+		if len(f) == 1 {
+			r.Content = texts[0]
+			break
+		}
+		quoted := make([]string, len(texts))
+		for i, s := range texts {
+			quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+		}
+		r.Content = strings.Join(quoted, " ")
+	case "SRV":
+		if err := want(4); err != nil {
+			return err
+		}
+		data, err := nums(0, "priority", "weight", "port")
+		if err != nil {
+			return err
+		}
+		data["target"] = p.target(texts[3])
+		r.Data = data
+	case "CAA":
+		if err := want(3); err != nil {
+			return err
+		}
+		data, err := nums(0, "flags")
+		if err != nil {
+			return err
+		}
+		data["tag"] = texts[1]
+		data["value"] = texts[2]
+		r.Data = data
+	case "HTTPS", "SVCB":
+		if err := atLeast(2); err != nil {
+			return err
+		}
+		data, err := nums(0, "priority")
+		if err != nil {
+			return err
+		}
+		data["target"] = p.target(texts[1])
+		data["value"] = strings.Join(texts[2:], " ")
+		r.Data = data
+	case "TLSA":
+		if err := atLeast(4); err != nil {
+			return err
+		}
+		data, err := nums(0, "usage", "selector", "matching_type")
+		if err != nil {
+			return err
+		}
+		data["certificate"] = strings.Join(texts[3:], "")
+		r.Data = data
+	case "SSHFP":
+		if err := atLeast(3); err != nil {
+			return err
+		}
+		data, err := nums(0, "algorithm", "type")
+		if err != nil {
+			return err
+		}
+		data["fingerprint"] = strings.Join(texts[2:], "")
+		r.Data = data
+	case "NAPTR":
+		if err := want(6); err != nil {
+			return err
+		}
+		data, err := nums(0, "order", "preference")
+		if err != nil {
+			return err
+		}
+		data["flags"] = texts[2]
+		data["service"] = texts[3]
+		data["regex"] = texts[4]
+		data["replacement"] = p.target(texts[5])
+		r.Data = data
+	case "URI":
+		if err := want(3); err != nil {
+			return err
+		}
+		prio, err := strconv.Atoi(texts[0])
+		if err != nil {
+			return fmt.Errorf("priority %q is not a number", texts[0])
+		}
+		data, err := nums(1, "weight")
+		if err != nil {
+			return err
+		}
+		r.Priority = prio
+		data["target"] = texts[2]
+		r.Data = data
+	case "LOC":
+		data, err := parseLOC(texts)
+		if err != nil {
+			return err
+		}
+		r.Data = data
+	default:
+		if err := atLeast(1); err != nil {
+			return err
+		}
+		r.Content = strings.Join(texts, " ")
+	}
+	return nil
+}
+
+// parseLOC reads RFC 1876 location data: latitude and longitude as degrees
+// with optional minutes and seconds and a hemisphere letter, then altitude
+// and optional size and precisions in metres.
+func parseLOC(f []string) (map[string]any, error) {
+	data := make(map[string]any)
+	coord := func(prefix, name, hemispheres string) error {
+		keys := []string{prefix + "_degrees", prefix + "_minutes", prefix + "_seconds"}
+		for i := 0; ; i++ {
+			if len(f) == 0 {
+				return fmt.Errorf("expected the %s, ending in %s", name, strings.Join(strings.Split(hemispheres, ""), " or "))
+			}
+			if h := strings.ToUpper(f[0]); len(h) == 1 && strings.Contains(hemispheres, h) && i > 0 {
+				data[prefix+"_direction"] = h
+				f = f[1:]
+				for ; i < len(keys); i++ {
+					data[keys[i]] = 0.0
+				}
+				return nil
+			}
+			if i == len(keys) {
+				return fmt.Errorf("expected %s, found %q", strings.Join(strings.Split(hemispheres, ""), " or "), f[0])
+			}
+			n, err := strconv.ParseFloat(f[0], 64)
+			if err != nil {
+				return fmt.Errorf("%q is not a number", f[0])
+			}
+			data[keys[i]] = n
+			f = f[1:]
+		}
+	}
+	if err := coord("lat", "latitude", "NS"); err != nil {
+		return nil, err
+	}
+	if err := coord("long", "longitude", "EW"); err != nil {
+		return nil, err
+	}
+	sizes := []struct {
+		key string
+		def float64
+	}{{"altitude", 0}, {"size", 1}, {"precision_horz", 10000}, {"precision_vert", 10}}
+	if len(f) == 0 {
+		return nil, fmt.Errorf("expected an altitude")
+	}
+	if len(f) > len(sizes) {
+		return nil, fmt.Errorf("unexpected %q after the precisions", f[len(sizes)])
+	}
+	for i, s := range sizes {
+		if i >= len(f) {
+			data[s.key] = s.def
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(f[i]), "m"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s %q is not a number of metres", strings.ReplaceAll(s.key, "_", " "), f[i])
+		}
+		data[s.key] = n
+	}
+	return data, nil
+}
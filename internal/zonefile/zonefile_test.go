@@ -0,0 +1,143 @@
+package zonefile
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azahorscak/cloudflare-tui/internal/api"
+)
+
+const sample = `; Exported from the old provider
+$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.oldhost.net. hostmaster.example.com. (
+		2024010101 ; serial
+		7200       ; refresh
+		3600 1209600 300 )
+@		IN	NS	ns1.oldhost.net.
+@	300	IN	A	192.0.2.1
+www		IN	CNAME	@
+	IN 600 TXT "hello" ; blank owner repeats www
+api 60 A 192.0.2.2 ; cf_tags=cf-proxied:true
+mail	IN	MX	10 mx1
+auto	300	A	192.0.2.3 ; cf_tags=cf-ttl:auto
+@	TXT	"v=spf1 include:_spf.example.net -all"
+long	TXT	"part one" "part \"two\""
+_sip._tcp	SRV	10 5 5060 sip.example.com.
+@	CAA	0 issue "letsencrypt.org"
+@	HTTPS	1 . alpn=h2,h3
+_443._tcp.www	TLSA	3 1 1 ( 0123456789abcdef
+		0123456789abcdef )
+host	SSHFP	4 2 abcdef0123
+@	NAPTR	100 10 "S" "SIP+D2U" "" _sip._udp
+geo	LOC	52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m
+_ftp._tcp	URI	10 1 "ftp://ftp.example.com/"
+$ORIGIN sub.example.com.
+deep	AAAA	2001:db8::1
+`
+
+func TestParse(t *testing.T) {
+	records, err := Parse(sample, "example.com")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []api.DNSRecord{
+		{Type: "NS", Name: "example.com", Content: "ns1.oldhost.net", TTL: 3600},
+		{Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 300},
+		{Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 3600},
+		{Type: "TXT", Name: "www.example.com", Content: "hello", TTL: 600, Comment: "blank owner repeats www"},
+		{Type: "A", Name: "api.example.com", Content: "192.0.2.2", TTL: 60, Proxied: true},
+		{Type: "MX", Name: "mail.example.com", Content: "mx1.example.com", TTL: 3600, Priority: 10},
+		{Type: "A", Name: "auto.example.com", Content: "192.0.2.3", TTL: 1},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 include:_spf.example.net -all", TTL: 3600},
+		{Type: "TXT", Name: "long.example.com", Content: `"part one" "part \"two\""`, TTL: 3600},
+		{Type: "SRV", Name: "_sip._tcp.example.com", TTL: 3600, Data: map[string]any{
+			"priority": 10.0, "weight": 5.0, "port": 5060.0, "target": "sip.example.com"}},
+		{Type: "CAA", Name: "example.com", TTL: 3600, Data: map[string]any{
+			"flags": 0.0, "tag": "issue", "value": "letsencrypt.org"}},
+		{Type: "HTTPS", Name: "example.com", TTL: 3600, Data: map[string]any{
+			"priority": 1.0, "target": ".", "value": "alpn=h2,h3"}},
+		{Type: "TLSA", Name: "_443._tcp.www.example.com", TTL: 3600, Data: map[string]any{
+			"usage": 3.0, "selector": 1.0, "matching_type": 1.0, "certificate": "0123456789abcdef0123456789abcdef"}},
+		{Type: "SSHFP", Name: "host.example.com", TTL: 3600, Data: map[string]any{
+			"algorithm": 4.0, "type": 2.0, "fingerprint": "abcdef0123"}},
+		{Type: "NAPTR", Name: "example.com", TTL: 3600, Data: map[string]any{
+			"order": 100.0, "preference": 10.0, "flags": "S", "service": "SIP+D2U", "regex": "", "replacement": "_sip._udp.example.com"}},
+		{Type: "LOC", Name: "geo.example.com", TTL: 3600, Data: map[string]any{
+			"lat_degrees": 52.0, "lat_minutes": 22.0, "lat_seconds": 23.0, "lat_direction": "N",
+			"long_degrees": 4.0, "long_minutes": 53.0, "long_seconds": 32.0, "long_direction": "E",
+			"altitude": -2.0, "size": 0.0, "precision_horz": 10000.0, "precision_vert": 10.0}},
+		{Type: "URI", Name: "_ftp._tcp.example.com", TTL: 3600, Priority: 10, Data: map[string]any{
+			"weight": 1.0, "target": "ftp://ftp.example.com/"}},
+		{Type: "AAAA", Name: "deep.sub.example.com", Content: "2001:db8::1", TTL: 3600},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("parsed %d records, want %d: %+v", len(records), len(want), records)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(records[i].DNSRecord, want[i]) {
+			t.Errorf("record %d = %+v\nwant %+v", i, records[i].DNSRecord, want[i])
+		}
+		// Only api and auto carry cf_tags.
+		if tagged := i == 4 || i == 6; records[i].Tagged != tagged {
+			t.Errorf("record %d: Tagged = %v, want %v", i, records[i].Tagged, tagged)
+		}
+	}
+}
+
+func TestParseTTLDefaults(t *testing.T) {
+	records, err := Parse("a A 192.0.2.1\nb 1d2h A 192.0.2.2\nc A 192.0.2.3\n", "example.com.")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got := []int{records[0].TTL, records[1].TTL, records[2].TTL}
+	if want := []int{1, 93600, 93600}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TTLs = %v, want %v", got, want)
+	}
+}
+
+func TestParseLOCDefaults(t *testing.T) {
+	records, err := Parse("geo LOC 52 N 4 30 E 10m\n", "example.com")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	d := records[0].Data
+	if d["lat_minutes"] != 0.0 || d["long_minutes"] != 30.0 || d["long_seconds"] != 0.0 || d["size"] != 1.0 || d["precision_vert"] != 10.0 {
+		t.Errorf("unexpected LOC data: %v", d)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		line    int
+		wantMsg string
+	}{
+		{"www A 192.0.2.1\nbad IN\n", 2, "missing record type"},
+		{"  A 192.0.2.1\n", 1, "no owner name"},
+		{"www CH TXT \"x\"\n", 1, "class CH is not supported"},
+		{"mail MX mx1\n", 1, "MX record: expected 2 fields"},
+		{"mail MX ten mx1\n", 1, `preference "ten" is not a number`},
+		{"txt TXT \"open\n", 1, "unterminated quoted string"},
+		{"@ SOA ns hm ( 1 2\n 3 4 5\n", 1, "( is never closed"},
+		{"www A 192.0.2.1 )\n", 1, "unexpected )"},
+		{"$INCLUDE other.zone\n", 1, "$INCLUDE is not supported"},
+		{"$TTL 1x\n", 1, `invalid TTL "1x"`},
+		{"_s._tcp SRV 1 2 x t\n", 1, `port "x" is not a number`},
+		{"geo LOC 52 22 N 4\n", 1, "expected the longitude, ending in E or W"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantMsg, func(t *testing.T) {
+			_, err := Parse(tt.src, "example.com")
+			var ze *Error
+			if !errors.As(err, &ze) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if ze.Line != tt.line || !strings.Contains(ze.Msg, tt.wantMsg) {
+				t.Errorf("error = %v, want line %d containing %q", err, tt.line, tt.wantMsg)
+			}
+		})
+	}
+}